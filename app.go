@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -14,6 +15,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -28,14 +30,35 @@ type App struct {
 	unsavedFiles      []string
 	configPath        string
 	Config            AppConfig
+	settings          *settingsManager
+	history           *historyStore
+	sftp              *sftpPool
+	listings          *dirListings
+	largeFiles        *largeFiles
+	logs              *logTails
+	passphrases       *passphrases
+	charsets          *fileCharsets
+	instance          *instanceServer
 	isClosing         bool // Neue Variable, um Schließvorgang zu verfolgen
-}
-
-// AppConfig holds persisted data
+	lsp               *lspManager
+	symbols           *symbolIndex
+	linters           *linterRunner
+	terminals         *terminalManager
+	processes         *processManager
+	debug             *debugManager
+	ws                workspaceState
+	coverage          map[string][]CoverageBlock // Per file, from the last test run with coverage
+	coverageMu        sync.Mutex
+}
+
+// AppConfig holds the persisted session state; preferences live in settings.json (see Settings)
 type AppConfig struct {
-	RecentFiles    []string `json:"recent_files"`
-	LastDirectory  string   `json:"last_directory"`
-	MaxRecentFiles int      `json:"max_recent_files"`
+	RecentFiles   []string `json:"recent_files"`
+	LastDirectory string   `json:"last_directory"`
+	// Workspace files or folders, most recent first
+	RecentWorkspaces []string `json:"recent_workspaces,omitempty"`
+	// Workspace open at exit, reopened on the next start
+	LastWorkspace string `json:"last_workspace,omitempty"`
 }
 
 // Result struct for file operations (JSON-tagged for JS)
@@ -43,6 +66,12 @@ type FileResult struct {
 	Content  string `json:"content"`
 	Filename string `json:"filename"`
 	Error    string `json:"error"` // Empty on success
+	// Indentation, line end and charset settings from .editorconfig
+	EditorConfig EditorConfig `json:"editorConfig"`
+	// Charset of the file's BOM, kept when saving; empty without BOM
+	Charset string `json:"charset,omitempty"`
+	// Set instead of Content for files not shown in the editor, see OpenMode
+	Mode string `json:"mode,omitempty"`
 }
 
 // NewApp creates a new App application struct
@@ -51,9 +80,24 @@ func NewApp() *App {
 	app.openedFilePath = ""      // Initialize it as empty
 	app.currentTitle = "Leoedit" // Default title
 	app.configPath = app.getConfigPath()
-	app.Config.MaxRecentFiles = 10
+	app.settings = newSettingsManager(filepath.Join(filepath.Dir(app.configPath), "settings.json"))
 	app.loadConfig()
+	app.history = newHistoryStore(filepath.Join(filepath.Dir(app.configPath), "history"))
+	app.sftp = newSFTPPool()
+	app.listings = newDirListings()
+	app.largeFiles = newLargeFiles()
+	app.logs = newLogTails()
+	app.passphrases = newPassphrases()
+	app.charsets = newFileCharsets()
+	app.instance = &instanceServer{}
 	app.isClosing = false // Initialisieren
+	app.lsp = newLSPManager(app)
+	app.symbols = newSymbolIndex()
+	app.linters = newLinterRunner(app)
+	app.terminals = newTerminalManager(app)
+	app.processes = newProcessManager(app)
+	app.debug = newDebugManager()
+	app.coverage = make(map[string][]CoverageBlock)
 	return app
 }
 
@@ -63,30 +107,28 @@ func (a *App) startup(ctx context.Context) {
 	a.currentTitle = "Leoedit"
 	a.unsavedFiles = []string{}
 
-	// Check for command-line args on startup (Windows/Linux)
-	args := os.Args[1:]
-	if len(args) > 0 {
-		p := args[0]
-		// Verify the path exists and is a file before setting it
-		if info, err := os.Stat(p); err == nil {
-			if !info.IsDir() {
-				a.openedFilePath = p
-				a.currentTitle = a.openedFilePath
-				fmt.Println("Opened file via args:", a.openedFilePath)
-				a.SetAppTitle(filepath.Base(a.openedFilePath))
-			} else {
-				fmt.Println("Argument is a directory, not a file:", p)
-				runtime.EventsEmit(a.ctx, "error", fmt.Sprintf("Argument ist ein Verzeichnis: %s", p))
-			}
-		} else if os.IsNotExist(err) {
-			fmt.Println("File does not exist:", p)
-			runtime.EventsEmit(a.ctx, "error", fmt.Sprintf("Datei existiert nicht: %s", p))
-		} else {
-			fmt.Println("Error checking path:", p, "err:", err)
-			runtime.EventsEmit(a.ctx, "error", fmt.Sprintf("Fehler beim Prüfen des Pfads: %s (%v)", p, err))
+	// Check for command-line args on startup (Windows/Linux); the frontend
+	// takes the files with TakeOpenRequests
+	wd, _ := os.Getwd()
+	a.openArgs(wd, os.Args[1:])
+	if files := a.instance.queue; len(files) > 0 {
+		a.openedFilePath = files[0].Path
+		a.currentTitle = a.openedFilePath
+		a.SetAppTitle(filepath.Base(a.openedFilePath))
+	}
+	if a.instance.listener != nil {
+		go a.serveInstance()
+	}
+
+	// Reopen the workspace of the last session unless the command line opened one
+	if a.workspace() == nil && a.Config.LastWorkspace != "" {
+		if _, err := a.OpenWorkspace(a.Config.LastWorkspace); err != nil {
+			log.Printf("workspace: %v", err)
 		}
 	}
 
+	runtime.OnFileDrop(ctx, a.HandleFileDrop)
+
 	// in startup or somewhere during initialization
 	runtime.EventsOn(ctx, "title-changed", func(args ...any) {
 		//fmt.Println("title-changed")
@@ -128,6 +170,30 @@ func (a *App) startup(ctx context.Context) {
 	})
 }
 
+// shutdown is called after the window has been closed
+func (a *App) shutdown(ctx context.Context) {
+	a.lsp.shutdownAll()
+	a.terminals.closeAll()
+	a.processes.stopAll()
+	a.DebugStop()
+	a.sftp.closeAll()
+	a.logs.closeAll()
+	if a.instance.listener != nil {
+		a.instance.listener.Close()
+	}
+}
+
+// fileSaved is called after every successful write of a buffer to disk
+func (a *App) fileSaved(path string) {
+	if isRemotePath(path) || isArchivePath(path) || a.encrypted(path) {
+		// Language servers, symbols and linters only work on local plain files
+		return
+	}
+	a.lsp.documentSaved(path)
+	go a.symbols.update(path)
+	a.linters.lint(path)
+}
+
 // Wenn false zurückgegeben wird, wird das Fenster geschlossen
 // Wenn true zurückgegeben wird, wird das Fenster nicht geschlossen auch nicht vom System
 func (a *App) onWindowClose(ctx context.Context) (prevent bool) {
@@ -173,11 +239,11 @@ func (a *App) HasUnsavedChanges() bool {
 // Rest der Methoden bleibt unverändert...
 
 func (a *App) fileExists(path string) (bool, error) {
-	info, err := os.Stat(path)
+	info, err := a.statPath(path)
 	if err == nil {
 		return !info.IsDir(), nil
 	}
-	if os.IsNotExist(err) {
+	if errors.Is(err, os.ErrNotExist) {
 		return false, nil
 	}
 	return false, err
@@ -188,11 +254,14 @@ func (a *App) SaveFile(content string, default_filename string, directsave bool)
 		return false
 	}
 	if directsave {
-		if err := os.WriteFile(default_filename, []byte(content), 0644); err != nil {
+		saved, err := a.writeBuffer(default_filename, content)
+		if err != nil {
 			return false
 		}
+		a.bufferSaved(default_filename, content, saved)
 		a.SetAppTitle(default_filename)
 		a.MarkFileAsSaved(default_filename) // Wichtig: Als gespeichert markieren
+		a.fileSaved(default_filename)
 		return true
 	}
 	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
@@ -209,11 +278,16 @@ func (a *App) SaveFile(content string, default_filename string, directsave bool)
 	if filename == "" {
 		return false
 	}
-	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+	a.passphrases.inherit(default_filename, filename)
+	a.charsets.inherit(default_filename, filename)
+	saved, err := a.writeBuffer(filename, content)
+	if err != nil {
 		return false
 	}
+	a.bufferSaved(filename, content, saved)
 	a.SetAppTitle(filename)
 	a.MarkFileAsSaved(filename) // Wichtig: Als gespeichert markieren
+	a.fileSaved(filename)
 	return true
 }
 
@@ -232,12 +306,17 @@ func (a *App) SaveFileUnder(content string, oldfname string) string {
 	if filename == "" {
 		return "Fehler: Abgebrochen"
 	}
-	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+	a.passphrases.inherit(oldfname, filename)
+	a.charsets.inherit(oldfname, filename)
+	saved, err := a.writeBuffer(filename, content)
+	if err != nil {
 		return fmt.Sprintf("Fehler beim Speichern: %v", err)
 	}
+	a.bufferSaved(filename, content, saved)
 	a.SetAppTitle(filepath.Base(filename))
 	a.MarkFileAsSaved(oldfname)
 	a.MarkFileAsSaved(filename)
+	a.fileSaved(filename)
 	return filename
 }
 
@@ -258,15 +337,20 @@ func (a *App) LoadFile() FileResult {
 		// Nutzer hat abgebrochen
 		return FileResult{Error: "Fehler: Abgebrochen"}
 	}
+	if mode := a.openMode(filename); mode != "" {
+		return FileResult{Filename: filename, Mode: mode}
+	}
 
-	data, err := os.ReadFile(filename)
+	content, ec, charset, err := a.readText(filename)
 	if err != nil {
 		return FileResult{Error: fmt.Sprintf("Fehler beim Lesen: %v", err)}
 	}
 	a.SetAppTitle(filepath.Base(filename))
 	return FileResult{
-		Content:  string(data),
-		Filename: filename,
+		Content:      content,
+		Filename:     filename,
+		EditorConfig: ec,
+		Charset:      charset,
 	}
 }
 
@@ -281,15 +365,19 @@ func (a *App) ReadFile(path string) string {
 		runtime.EventsEmit(a.ctx, "error", fmt.Sprintf("Datei existiert nicht oder ist ein Verzeichnis: %s", path))
 		return ""
 	}
+	if a.openMode(path) != "" {
+		runtime.EventsEmit(a.ctx, "error", fmt.Sprintf("Datei kann nicht im Editor geöffnet werden: %s", path))
+		return ""
+	}
 
-	content, err := os.ReadFile(path)
+	content, _, _, err := a.readText(path)
 	if err != nil {
 		runtime.EventsEmit(a.ctx, "error", fmt.Sprintf("Fehler beim Lesen: %v", err))
 		return ""
 	}
 	runtime.EventsEmit(a.ctx, "file-read", path)
 	a.SetAppTitle(filepath.Base(path))
-	return string(content)
+	return content
 }
 
 func (a *App) GetOpenedFilePath() string {
@@ -332,9 +420,24 @@ func (a *App) PasteAction() {
 	fmt.Println("Paste action triggered from frontend")
 }
 
-// HandleFileDrop processes dropped files (called by Wails runtime)
+// HandleFileDrop processes dropped files (called by Wails runtime). A
+// dropped folder or workspace file opens it as workspace.
 func (a *App) HandleFileDrop(posX, posY int, files []string) {
 	fmt.Printf("Files dropped: %v\n", files)
+	var rest []string
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil && (info.IsDir() || isWorkspaceFile(f)) {
+			if _, err := a.OpenWorkspace(f); err != nil {
+				runtime.EventsEmit(a.ctx, "error", err.Error())
+			}
+			continue
+		}
+		rest = append(rest, f)
+	}
+	if len(rest) == 0 {
+		return
+	}
+	files = rest
 	runtime.EventsEmit(a.ctx, "file-drop", map[string]interface{}{
 		"x":     posX,
 		"y":     posY,
@@ -399,6 +502,16 @@ type OpenRouterResponse struct {
 
 func (a *App) QueryOpenRouter(model, prompt string) error {
 	apiKey := os.Getenv("OPENROUTER_API_KEY")
+	endpoint := "https://openrouter.ai/api/v1/chat/completions"
+	// The workspace may point the AI panel at another OpenAI compatible endpoint
+	if ws := a.workspace(); ws != nil && ws.Settings.AI != nil {
+		if ws.Settings.AI.APIKeyEnv != "" {
+			apiKey = os.Getenv(ws.Settings.AI.APIKeyEnv)
+		}
+		if ws.Settings.AI.BaseURL != "" {
+			endpoint = ws.Settings.AI.BaseURL
+		}
+	}
 
 	reqBody := map[string]interface{}{
 		"model": model,
@@ -423,9 +536,7 @@ func (a *App) QueryOpenRouter(model, prompt string) error {
 			TLSHandshakeTimeout:   10 * time.Second,
 			ResponseHeaderTimeout: 60 * time.Second,
 		}}
-	httpReq, _ := http.NewRequestWithContext(ctx, "POST",
-		"https://openrouter.ai/api/v1/chat/completions",
-		bytes.NewBuffer(jsonBody))
+	httpReq, _ := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonBody))
 
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
@@ -585,28 +696,12 @@ func (a *App) MarkFileAsSaved(filename string) {
 	}
 }
 
-// In your Wails app Go code
-func (a *App) ListDir(path string) ([]map[string]interface{}, error) {
-	entries, err := os.ReadDir(path)
-	if err != nil {
-		return nil, err
-	}
-	var result []map[string]interface{}
-	for _, entry := range entries {
-		info, _ := entry.Info()
-		result = append(result, map[string]interface{}{
-			"name":    entry.Name(),
-			"isDir":   entry.IsDir(),
-			"size":    info.Size(),
-			"modTime": info.ModTime().Unix(),
-		})
-	}
-	return result, nil
-}
-
 func (a *App) ReadFileContent(path string) (string, error) {
-	content, err := os.ReadFile(path)
-	return string(content), err
+	if a.openMode(path) != "" {
+		return "", fmt.Errorf("Datei kann nicht im Editor geöffnet werden: %s", path)
+	}
+	content, _, _, err := a.readText(path)
+	return content, err
 }
 
 func (a *App) HomeDir() (string, error) {
@@ -634,25 +729,22 @@ func (a *App) getConfigPath() string {
 	return filepath.Join(appDir, "config.json")
 }
 
-// Load configuration
+// Load configuration; a broken config.json is kept as config.json.broken and reported
 func (a *App) loadConfig() {
-	data, err := os.ReadFile(a.configPath)
-	if err != nil {
-		a.Config = AppConfig{
-			RecentFiles:    []string{},
-			MaxRecentFiles: 10,
-		}
-		return
-	}
-
-	err = json.Unmarshal(data, &a.Config)
-	if err != nil {
-		fmt.Printf("Error loading config: %v\n", err)
-		a.Config = AppConfig{
-			RecentFiles:    []string{},
-			MaxRecentFiles: 10,
+	a.Config = AppConfig{RecentFiles: []string{}}
+	data, _ := os.ReadFile(a.configPath)
+	// Before settings.json existed the settings were part of config.json
+	a.settings.load(data)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &a.Config); err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			a.Config = AppConfig{RecentFiles: []string{}}
+			os.Rename(a.configPath, a.configPath+".broken")
+			a.settings.errors = append(a.settings.errors,
+				fmt.Sprintf("config.json ist fehlerhaft und wurde als config.json.broken gesichert: %v", err))
 		}
 	}
+	a.settings.resolve(nil)
 }
 
 // Save configuration
@@ -670,6 +762,10 @@ func (a *App) ExtractFilePath(input string) (string, error) {
 		return "", fmt.Errorf("empty input")
 	}
 
+	if isRemotePath(input) {
+		return cleanRemotePath(input)
+	}
+
 	cleaned := filepath.Clean(input)
 
 	if filepath.IsAbs(cleaned) {
@@ -733,11 +829,11 @@ func (a *App) AddRecentFile(filePath string) string {
 
 	a.Config.RecentFiles = append([]string{absPath}, a.Config.RecentFiles...)
 
-	if len(a.Config.RecentFiles) > a.Config.MaxRecentFiles {
-		a.Config.RecentFiles = a.Config.RecentFiles[:a.Config.MaxRecentFiles]
+	if max := a.prefs().MaxRecentFiles; len(a.Config.RecentFiles) > max {
+		a.Config.RecentFiles = a.Config.RecentFiles[:max]
 	}
 
-	if dir := filepath.Dir(absPath); dir != "" {
+	if dir := filepath.Dir(absPath); dir != "" && !isRemotePath(absPath) {
 		a.Config.LastDirectory = dir
 	}
 
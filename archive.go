@@ -0,0 +1,517 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	dsbzip2 "github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Paths inside an archive put "!" after the archive: /tmp/bundle.zip!/docs/a.txt
+const archiveSep = "!"
+
+// codec is a compression format recognized by its extension
+type codec struct {
+	ext    string
+	reader func(io.Reader) (io.ReadCloser, error)
+	writer func(io.Writer) (io.WriteCloser, error)
+}
+
+var codecs = []codec{
+	{".gz",
+		func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+		func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }},
+	{".bz2",
+		func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(bzip2.NewReader(r)), nil },
+		func(w io.Writer) (io.WriteCloser, error) {
+			return dsbzip2.NewWriter(w, &dsbzip2.WriterConfig{Level: dsbzip2.DefaultCompression})
+		}},
+	{".xz",
+		func(r io.Reader) (io.ReadCloser, error) {
+			xr, err := xz.NewReader(r)
+			return io.NopCloser(xr), err
+		},
+		func(w io.Writer) (io.WriteCloser, error) { return xz.NewWriter(w) }},
+	{".zst",
+		func(r io.Reader) (io.ReadCloser, error) {
+			d, err := zstd.NewReader(r)
+			if err != nil {
+				return nil, err
+			}
+			return d.IOReadCloser(), nil
+		},
+		func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) }},
+}
+
+// Short forms of compressed tar archives
+var tarAliases = map[string]string{".tgz": ".gz", ".tbz2": ".bz2", ".tbz": ".bz2", ".txz": ".xz", ".tzst": ".zst"}
+
+// codecFor returns the compression of a file name, nil if it has none
+func codecFor(name string) *codec {
+	ext := strings.ToLower(path.Ext(name))
+	if alias, ok := tarAliases[ext]; ok {
+		ext = alias
+	}
+	for i := range codecs {
+		if codecs[i].ext == ext {
+			return &codecs[i]
+		}
+	}
+	return nil
+}
+
+// isArchiveName reports whether name is a zip or (compressed) tar archive
+func isArchiveName(name string) bool {
+	lower := strings.ToLower(name)
+	if _, ok := tarAliases[path.Ext(lower)]; ok {
+		return true
+	}
+	if codecFor(lower) != nil {
+		lower = strings.TrimSuffix(lower, path.Ext(lower))
+	}
+	switch path.Ext(lower) {
+	case ".zip", ".jar", ".tar":
+		return true
+	}
+	return false
+}
+
+func isZip(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".zip" || ext == ".jar"
+}
+
+// isCompressedFile reports whether a file is decompressed on read and
+// compressed again on write; archives are browsed instead
+func isCompressedFile(p string) bool {
+	return codecFor(p) != nil && !isArchiveName(p) && !isArchivePath(p)
+}
+
+// splitArchivePath splits "/a/b.zip!/c/d" into "/a/b.zip" and "c/d"
+func splitArchivePath(p string) (archive, inner string, ok bool) {
+	if isRemotePath(p) {
+		return "", "", false
+	}
+	for i := strings.Index(p, archiveSep); i >= 0; {
+		rest := p[i+len(archiveSep):]
+		if isArchiveName(p[:i]) && (rest == "" || rest[0] == '/' || rest[0] == '\\') {
+			return p[:i], strings.Trim(filepath.ToSlash(rest), "/"), true
+		}
+		next := strings.Index(rest, archiveSep)
+		if next < 0 {
+			break
+		}
+		i += len(archiveSep) + next
+	}
+	return "", "", false
+}
+
+func isArchivePath(p string) bool {
+	_, _, ok := splitArchivePath(p)
+	return ok
+}
+
+// decompress undoes the compression of a file named p
+func decompress(p string, data []byte) ([]byte, error) {
+	c := codecFor(p)
+	if c == nil || !isCompressedFile(p) {
+		return data, nil
+	}
+	r, err := c.reader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("Entpacken fehlgeschlagen: %w", err)
+	}
+	defer r.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("Entpacken fehlgeschlagen: %w", err)
+	}
+	return out, nil
+}
+
+// compress applies the compression of a file named p
+func compress(p string, data []byte) ([]byte, error) {
+	c := codecFor(p)
+	if c == nil || !isCompressedFile(p) {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	w, err := c.writer(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// archiveEntry is a file or folder in an archive
+type archiveEntry struct {
+	name    string // Slash separated, without leading or trailing slash
+	isDir   bool
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// Name, Size, Mode, ModTime, IsDir and Sys make archiveEntry an os.FileInfo
+func (e archiveEntry) Name() string       { return path.Base(e.name) }
+func (e archiveEntry) Size() int64        { return e.size }
+func (e archiveEntry) ModTime() time.Time { return e.modTime }
+func (e archiveEntry) IsDir() bool        { return e.isDir }
+func (e archiveEntry) Sys() any           { return nil }
+func (e archiveEntry) Mode() fs.FileMode {
+	if e.isDir {
+		return e.mode | fs.ModeDir
+	}
+	return e.mode
+}
+
+// openTar returns a tar reader over the decompressed archive
+func openTar(archive string) (*tar.Reader, io.Closer, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, nil, err
+	}
+	var r io.Reader = f
+	closer := io.Closer(f)
+	if c := codecFor(archive); c != nil {
+		dr, err := c.reader(f)
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		r = dr
+		closer = closers{dr, f}
+	}
+	return tar.NewReader(r), closer, nil
+}
+
+type closers []io.Closer
+
+func (cs closers) Close() error {
+	var errs []error
+	for _, c := range cs {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// archiveEntries lists all entries; folders only implied by the paths of
+// files are added
+func archiveEntries(archive string) ([]archiveEntry, error) {
+	var entries []archiveEntry
+	if isZip(archive) {
+		zr, err := zip.OpenReader(archive)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			if strings.Trim(f.Name, "/") == "" {
+				continue
+			}
+			entries = append(entries, archiveEntry{
+				name:    strings.Trim(f.Name, "/"),
+				isDir:   f.FileInfo().IsDir(),
+				size:    int64(f.UncompressedSize64),
+				mode:    f.Mode().Perm(),
+				modTime: f.Modified,
+			})
+		}
+	} else {
+		tr, closer, err := openTar(archive)
+		if err != nil {
+			return nil, err
+		}
+		defer closer.Close()
+		for {
+			h, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			name := strings.Trim(path.Clean(h.Name), "/")
+			if h.Typeflag != tar.TypeReg && h.Typeflag != tar.TypeDir || name == "." || name == "" {
+				continue
+			}
+			entries = append(entries, archiveEntry{
+				name:    name,
+				isDir:   h.Typeflag == tar.TypeDir,
+				size:    h.Size,
+				mode:    fs.FileMode(h.Mode).Perm(),
+				modTime: h.ModTime,
+			})
+		}
+	}
+
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		seen[e.name] = true
+	}
+	for _, e := range entries {
+		for dir := path.Dir(e.name); dir != "." && !seen[dir]; dir = path.Dir(dir) {
+			seen[dir] = true
+			entries = append(entries, archiveEntry{name: dir, isDir: true, mode: 0755, modTime: e.modTime})
+		}
+	}
+	return entries, nil
+}
+
+// statArchivePath describes an entry; the archive root is a folder
+func statArchivePath(p string) (os.FileInfo, error) {
+	archive, inner, _ := splitArchivePath(p)
+	if inner == "" {
+		info, err := os.Stat(archive)
+		if err != nil {
+			return nil, err
+		}
+		return archiveEntry{name: info.Name(), isDir: true, mode: 0755, modTime: info.ModTime()}, nil
+	}
+	entries, err := archiveEntries(archive)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.name == inner {
+			return e, nil
+		}
+	}
+	return nil, &fs.PathError{Op: "stat", Path: p, Err: fs.ErrNotExist}
+}
+
+// listArchiveDir is ListDir for folders inside an archive
+func listArchiveDir(p string, opts ListDirOptions) ([]DirEntry, error) {
+	archive, inner, _ := splitArchivePath(p)
+	entries, err := archiveEntries(archive)
+	if err != nil {
+		return nil, fmt.Errorf("Archiv nicht lesbar: %w", err)
+	}
+	result := []DirEntry{}
+	for _, e := range entries {
+		if dir := path.Dir(e.name); dir != inner && !(dir == "." && inner == "") {
+			continue
+		}
+		hidden := strings.HasPrefix(e.Name(), ".")
+		if hidden && !opts.ShowHidden {
+			continue
+		}
+		d := DirEntry{
+			Name:    e.Name(),
+			IsDir:   e.isDir,
+			Size:    e.size,
+			ModTime: e.modTime.Unix(),
+			Mode:    e.Mode().String(),
+			Perm:    uint32(e.mode.Perm()),
+			Hidden:  hidden,
+			// Nested archives are not opened, they are plain files here
+			Archive: false,
+			Mime:    "inode/directory",
+		}
+		if !e.isDir {
+			d.Mime = mimeByName(e.name)
+		}
+		result = append(result, d)
+	}
+	sortDirEntries(result)
+	return result, nil
+}
+
+// readArchiveEntry returns the content of a file inside an archive
+func readArchiveEntry(p string) ([]byte, error) {
+	archive, inner, _ := splitArchivePath(p)
+	if isZip(archive) {
+		zr, err := zip.OpenReader(archive)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			if strings.Trim(f.Name, "/") == inner && !f.FileInfo().IsDir() {
+				r, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer r.Close()
+				return io.ReadAll(r)
+			}
+		}
+	} else {
+		tr, closer, err := openTar(archive)
+		if err != nil {
+			return nil, err
+		}
+		defer closer.Close()
+		for {
+			h, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if h.Typeflag == tar.TypeReg && strings.Trim(path.Clean(h.Name), "/") == inner {
+				return io.ReadAll(tr)
+			}
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: p, Err: fs.ErrNotExist}
+}
+
+// writeArchiveEntry rewrites the archive with data as the content of the
+// entry; a missing entry is added. The new archive replaces the old one
+// only when it was written completely.
+func (a *App) writeArchiveEntry(p string, data []byte) error {
+	if !a.prefs().Archive.Writable {
+		return fmt.Errorf("Archive sind schreibgeschützt (Einstellung archive.writable)")
+	}
+	archive, inner, _ := splitArchivePath(p)
+	if inner == "" {
+		return fmt.Errorf("Ungültiger Pfad im Archiv: %s", p)
+	}
+	info, err := os.Stat(archive)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(archive), "."+filepath.Base(archive)+".*")
+	if err != nil {
+		return err
+	}
+	if isZip(archive) {
+		err = rewriteZip(archive, tmp, inner, data)
+	} else {
+		err = rewriteTar(archive, tmp, inner, data)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), info.Mode().Perm())
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), archive)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("Archiv konnte nicht geschrieben werden: %w", err)
+	}
+	return nil
+}
+
+// rewriteZip copies the entries unchanged (without recompressing) except inner
+func rewriteZip(archive string, out io.Writer, inner string, data []byte) error {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	zw := zip.NewWriter(out)
+	header := &zip.FileHeader{Name: inner, Method: zip.Deflate}
+	for _, f := range zr.File {
+		if strings.Trim(f.Name, "/") == inner {
+			header = &f.FileHeader
+			continue
+		}
+		if err := zw.Copy(f); err != nil {
+			return err
+		}
+	}
+	// The sizes and the checksum are computed anew
+	h := *header
+	h.Modified = time.Now()
+	w, err := zw.CreateHeader(&h)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := zw.SetComment(zr.Comment); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// rewriteTar streams the archive through, replacing the content of inner
+func rewriteTar(archive string, out io.Writer, inner string, data []byte) error {
+	tr, closer, err := openTar(archive)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	var dst io.Writer = out
+	var cw io.WriteCloser
+	if c := codecFor(archive); c != nil {
+		if cw, err = c.writer(out); err != nil {
+			return err
+		}
+		dst = cw
+	}
+	tw := tar.NewWriter(dst)
+	written := false
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if h.Typeflag == tar.TypeReg && strings.Trim(path.Clean(h.Name), "/") == inner {
+			h.Size = int64(len(data))
+			h.ModTime = time.Now()
+			if err := tw.WriteHeader(h); err != nil {
+				return err
+			}
+			if _, err := tw.Write(data); err != nil {
+				return err
+			}
+			written = true
+			continue
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	if !written {
+		h := &tar.Header{Name: inner, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if cw != nil {
+		return cw.Close()
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// DiffSource is one side of a comparison
+type DiffSource struct {
+	Kind string `json:"kind"` // file, text (a buffer), clipboard
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Text string `json:"text"`
+}
+
+// loadDiffSource returns the display name and text of the source
+func (a *App) loadDiffSource(src DiffSource) (string, string, error) {
+	switch src.Kind {
+	case "file":
+		text, _, _, err := a.readText(src.Path)
+		if err != nil {
+			return "", "", fmt.Errorf("Fehler beim Lesen von %s: %w", filepath.Base(src.Path), err)
+		}
+		name := src.Name
+		if name == "" {
+			name = src.Path
+		}
+		return name, text, nil
+	case "clipboard":
+		text, err := runtime.ClipboardGetText(a.ctx)
+		if err != nil {
+			return "", "", fmt.Errorf("Zwischenablage nicht lesbar: %w", err)
+		}
+		return "Zwischenablage", text, nil
+	case "text":
+		name := src.Name
+		if name == "" {
+			name = "Puffer"
+		}
+		return name, src.Text, nil
+	}
+	return "", "", fmt.Errorf("Unbekannte Vergleichsquelle: %s", src.Kind)
+}
+
+func (a *App) loadDiffSources(left, right DiffSource) (nameA, textA, nameB, textB string, err error) {
+	if nameA, textA, err = a.loadDiffSource(left); err != nil {
+		return
+	}
+	nameB, textB, err = a.loadDiffSource(right)
+	return
+}
+
+// Compare diffs two sources for the side-by-side view
+func (a *App) Compare(left, right DiffSource, opts DiffOptions) (DiffResult, error) {
+	nameA, textA, nameB, textB, err := a.loadDiffSources(left, right)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	return compareTexts(nameA, textA, nameB, textB, opts), nil
+}
+
+// ComparePatch returns the comparison as unified diff
+func (a *App) ComparePatch(left, right DiffSource, opts DiffOptions) (string, error) {
+	nameA, textA, nameB, textB, err := a.loadDiffSources(left, right)
+	if err != nil {
+		return "", err
+	}
+	if opts.Context < 0 {
+		opts.Context = 3
+	}
+	la, lb := splitLines(textA), splitLines(textB)
+	return formatUnified(nameA, nameB, la, lb, diffLines(la, lb, opts), opts.Context), nil
+}
+
+// SaveComparePatch asks for a file name and writes the unified diff there;
+// it returns the path or "" when the dialog was cancelled
+func (a *App) SaveComparePatch(left, right DiffSource, opts DiffOptions) (string, error) {
+	patch, err := a.ComparePatch(left, right, opts)
+	if err != nil {
+		return "", err
+	}
+	if patch == "" {
+		return "", fmt.Errorf("Keine Unterschiede")
+	}
+	base := strings.TrimSuffix(filepath.Base(right.Path), filepath.Ext(right.Path))
+	if base == "" || base == "." {
+		base = "vergleich"
+	}
+	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Patch speichern",
+		DefaultFilename: base + ".patch",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Patch-Dateien", Pattern: "*.patch;*.diff"},
+		},
+	})
+	if err != nil || filename == "" {
+		return "", err
+	}
+	if err := os.WriteFile(filename, []byte(patch), 0644); err != nil {
+		return "", fmt.Errorf("Fehler beim Speichern: %w", err)
+	}
+	return filename, nil
+}
+
+// SelectCompareFile asks for one side of a file comparison; "" when cancelled
+func (a *App) SelectCompareFile(title string) (string, error) {
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		DefaultDirectory: a.Config.LastDirectory,
+		Title:            title,
+	})
+}
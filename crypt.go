@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Encrypted files use the age format with a passphrase (scrypt), so they
+// can be opened with the age command line tool as well. The passphrase is
+// entered in the frontend and only kept in memory; the plaintext is never
+// written to disk.
+
+const ageHeader = "age-encryption.org/v1\n"
+
+// How long a passphrase prompt waits for an answer
+const passphraseTimeout = 5 * time.Minute
+
+var errPassphraseCanceled = errors.New("Passworteingabe abgebrochen")
+
+// PassphraseRequest is sent as "passphrase-request"; the frontend answers
+// with ProvidePassphrase
+type PassphraseRequest struct {
+	ID      string `json:"id"`
+	Path    string `json:"path"`
+	Confirm bool   `json:"confirm"` // New encryption, the passphrase is entered twice
+	Retry   bool   `json:"retry"`   // The passphrase before was wrong
+}
+
+// secret is the passphrase of an opened encrypted file
+type secret struct {
+	passphrase string
+	armored    bool // ASCII armor is kept when saving
+}
+
+// passphrases holds the secrets of opened files and the open prompts
+type passphrases struct {
+	mu      sync.Mutex
+	next    int
+	known   map[string]secret
+	pending map[string]chan string
+}
+
+func newPassphrases() *passphrases {
+	return &passphrases{known: make(map[string]secret), pending: make(map[string]chan string)}
+}
+
+func (p *passphrases) get(path string) (secret, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, ok := p.known[path]
+	return s, ok
+}
+
+func (p *passphrases) set(path string, s secret) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.known[path] = s
+}
+
+func (p *passphrases) forget(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.known, path)
+}
+
+// move keeps the secrets of renamed files and of files in a renamed folder
+func (p *passphrases) move(from, to string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for path, s := range p.known {
+		if path == from || isWithin(path, from) {
+			rel, _ := filepath.Rel(from, path)
+			delete(p.known, path)
+			p.known[filepath.Join(to, rel)] = s
+		}
+	}
+}
+
+// inherit lets a copy saved under a new name use the secret of the
+// encrypted original, so "save as" does not write plaintext
+func (p *passphrases) inherit(from, to string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s, ok := p.known[from]; ok && from != to {
+		if _, known := p.known[to]; !known {
+			p.known[to] = s
+		}
+	}
+}
+
+// isEncryptedName reports whether path is saved encrypted because of its name
+func isEncryptedName(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".age")
+}
+
+// isEncrypted checks for the age header, binary or ASCII armored
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(ageHeader)) || bytes.HasPrefix(data, []byte(armor.Header))
+}
+
+// encrypted reports whether writes to path are encrypted
+func (a *App) encrypted(path string) bool {
+	_, ok := a.passphrases.get(path)
+	return ok || isEncryptedName(path)
+}
+
+// askPassphrase shows the passphrase prompt and waits for the answer
+func (a *App) askPassphrase(path string, confirm, retry bool) (string, error) {
+	if a.ctx == nil {
+		return "", fmt.Errorf("Kein Passwort für %s", path)
+	}
+	answer := make(chan string, 1)
+	a.passphrases.mu.Lock()
+	a.passphrases.next++
+	id := fmt.Sprintf("pass-%d", a.passphrases.next)
+	a.passphrases.pending[id] = answer
+	a.passphrases.mu.Unlock()
+	defer func() {
+		a.passphrases.mu.Lock()
+		delete(a.passphrases.pending, id)
+		a.passphrases.mu.Unlock()
+	}()
+
+	runtime.EventsEmit(a.ctx, "passphrase-request", PassphraseRequest{ID: id, Path: path, Confirm: confirm, Retry: retry})
+	select {
+	case pass := <-answer:
+		if pass == "" {
+			return "", errPassphraseCanceled
+		}
+		return pass, nil
+	case <-time.After(passphraseTimeout):
+		return "", errPassphraseCanceled
+	}
+}
+
+// decrypt asks for the passphrase until it fits or the prompt is canceled
+func (a *App) decrypt(path string, data []byte) ([]byte, error) {
+	armored := bytes.HasPrefix(data, []byte(armor.Header))
+	retry := false
+	for {
+		s, ok := a.passphrases.get(path)
+		if !ok {
+			pass, err := a.askPassphrase(path, false, retry)
+			if err != nil {
+				return nil, err
+			}
+			s = secret{passphrase: pass}
+		}
+		s.armored = armored
+		plain, err := ageDecrypt(data, s)
+		var wrong *age.NoIdentityMatchError
+		switch {
+		case err == nil:
+			a.passphrases.set(path, s)
+			return plain, nil
+		case !errors.As(err, &wrong):
+			return nil, fmt.Errorf("Entschlüsselung fehlgeschlagen: %w", err)
+		}
+		a.passphrases.forget(path)
+		retry = true
+	}
+}
+
+// encrypt uses the passphrase the file was opened with; a new encrypted
+// file asks for one
+func (a *App) encrypt(path string, plain []byte) ([]byte, error) {
+	s, ok := a.passphrases.get(path)
+	if !ok {
+		pass, err := a.askPassphrase(path, true, false)
+		if err != nil {
+			return nil, err
+		}
+		s = secret{passphrase: pass}
+	}
+	data, err := ageEncrypt(plain, s)
+	if err != nil {
+		return nil, fmt.Errorf("Verschlüsselung fehlgeschlagen: %w", err)
+	}
+	a.passphrases.set(path, s)
+	return data, nil
+}
+
+func ageDecrypt(data []byte, s secret) ([]byte, error) {
+	id, err := age.NewScryptIdentity(s.passphrase)
+	if err != nil {
+		return nil, err
+	}
+	var src io.Reader = bytes.NewReader(data)
+	if s.armored {
+		src = armor.NewReader(src)
+	}
+	r, err := age.Decrypt(src, id)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func ageEncrypt(plain []byte, s secret) ([]byte, error) {
+	rcpt, err := age.NewScryptRecipient(s.passphrase)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	var dst io.WriteCloser = nopWriteCloser{&buf}
+	if s.armored {
+		dst = armor.NewWriter(&buf)
+	}
+	w, err := age.Encrypt(dst, rcpt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plain); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := dst.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// ProvidePassphrase answers a "passphrase-request"; an empty passphrase
+// cancels
+func (a *App) ProvidePassphrase(id, passphrase string) {
+	a.passphrases.mu.Lock()
+	answer, ok := a.passphrases.pending[id]
+	a.passphrases.mu.Unlock()
+	if ok {
+		select {
+		case answer <- passphrase:
+		default:
+		}
+	}
+}
+
+// ForgetPassphrase drops the passphrase of a closed file
+func (a *App) ForgetPassphrase(path string) {
+	a.passphrases.forget(path)
+}
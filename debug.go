@@ -0,0 +1,635 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const dapRequestTimeout = 30 * time.Second
+
+// DebugBreakpoint is a breakpoint as confirmed by the debugger (1-based line)
+type DebugBreakpoint struct {
+	Line     int    `json:"line"`
+	Verified bool   `json:"verified"`
+	Message  string `json:"message,omitempty"`
+}
+
+// DebugStackFrame is one frame of a call stack (1-based line)
+type DebugStackFrame struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// DebugThread is a goroutine
+type DebugThread struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// DebugScope is a group of variables of a stack frame (locals, arguments)
+type DebugScope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+// DebugVariable is a variable; a VariablesReference > 0 means it has children
+type DebugVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// ---------------------------------------------------------------------------
+// Debug Adapter Protocol client
+// ---------------------------------------------------------------------------
+
+type dapRequest struct {
+	Seq       int    `json:"seq"`
+	Type      string `json:"type"`
+	Command   string `json:"command"`
+	Arguments any    `json:"arguments,omitempty"`
+}
+
+type dapMessage struct {
+	Seq        int             `json:"seq"`
+	Type       string          `json:"type"` // request, response, event
+	Command    string          `json:"command"`
+	RequestSeq int             `json:"request_seq"`
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Event      string          `json:"event"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// debugSession is one running "dlv dap" connected over TCP
+type debugSession struct {
+	app  *App
+	cmd  *exec.Cmd
+	conn net.Conn
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	seq     int
+	pending map[int]chan *dapMessage
+
+	initialized chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+// startDelve runs "dlv dap" in dir and connects to it
+func startDelve(app *App, dir string) (*debugSession, error) {
+	dlv, err := exec.LookPath("dlv")
+	if err != nil {
+		return nil, fmt.Errorf("Delve (dlv) nicht gefunden: %w", err)
+	}
+
+	cmd := exec.Command(dlv, "dap", "--listen", "127.0.0.1:0")
+	cmd.Dir = dir
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("Delve konnte nicht gestartet werden: %w", err)
+	}
+
+	// "DAP server listening at: 127.0.0.1:40123"
+	addrCh := make(chan string, 1)
+	go func() {
+		sc := bufio.NewScanner(stdout)
+		for sc.Scan() {
+			line := sc.Text()
+			if _, addr, ok := strings.Cut(line, "listening at:"); ok {
+				select {
+				case addrCh <- strings.TrimSpace(addr):
+				default:
+				}
+				continue
+			}
+			app.debugOutput("console", line+"\n")
+		}
+	}()
+
+	var addr string
+	select {
+	case addr = <-addrCh:
+	case <-time.After(10 * time.Second):
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("Delve hat keine Adresse gemeldet")
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("Verbindung zu Delve fehlgeschlagen: %w", err)
+	}
+
+	s := &debugSession{
+		app:         app,
+		cmd:         cmd,
+		conn:        conn,
+		pending:     make(map[int]chan *dapMessage),
+		initialized: make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go s.readLoop(bufio.NewReader(conn))
+	go func() {
+		cmd.Wait()
+		s.close()
+	}()
+	return s, nil
+}
+
+func (s *debugSession) write(v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := fmt.Fprintf(s.conn, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = s.conn.Write(body)
+	return err
+}
+
+// call sends a request and decodes the response body into result (if not nil)
+func (s *debugSession) call(command string, args any, result any) error {
+	s.mu.Lock()
+	s.seq++
+	seq := s.seq
+	ch := make(chan *dapMessage, 1)
+	s.pending[seq] = ch
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, seq)
+		s.mu.Unlock()
+	}()
+
+	if err := s.write(&dapRequest{Seq: seq, Type: "request", Command: command, Arguments: args}); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dapRequestTimeout)
+	defer cancel()
+	select {
+	case resp := <-ch:
+		if !resp.Success {
+			return fmt.Errorf("%s: %s", command, resp.Message)
+		}
+		if result != nil && len(resp.Body) > 0 {
+			return json.Unmarshal(resp.Body, result)
+		}
+		return nil
+	case <-s.done:
+		return fmt.Errorf("Debug-Sitzung wurde beendet")
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", command, ctx.Err())
+	}
+}
+
+func (s *debugSession) readLoop(r *bufio.Reader) {
+	defer s.close()
+	for {
+		body, err := readFramedMessage(r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				fmt.Printf("DAP: read error: %v\n", err)
+			}
+			return
+		}
+		var msg dapMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			continue
+		}
+
+		switch msg.Type {
+		case "response":
+			s.mu.Lock()
+			ch := s.pending[msg.RequestSeq]
+			s.mu.Unlock()
+			if ch != nil {
+				ch <- &msg
+			}
+		case "event":
+			s.handleEvent(&msg)
+		case "request":
+			// Reverse requests (runInTerminal) are not supported
+			s.write(map[string]any{
+				"seq": 0, "type": "response", "request_seq": msg.Seq, "command": msg.Command,
+				"success": false, "message": "not supported",
+			})
+		}
+	}
+}
+
+func (s *debugSession) handleEvent(msg *dapMessage) {
+	switch msg.Event {
+	case "initialized":
+		close(s.initialized)
+	case "output":
+		var body struct {
+			Category string `json:"category"`
+			Output   string `json:"output"`
+		}
+		json.Unmarshal(msg.Body, &body)
+		s.app.debugOutput(body.Category, body.Output)
+	case "stopped":
+		var body struct {
+			Reason            string `json:"reason"`
+			Description       string `json:"description"`
+			ThreadID          int    `json:"threadId"`
+			AllThreadsStopped bool   `json:"allThreadsStopped"`
+		}
+		json.Unmarshal(msg.Body, &body)
+		s.app.debug.mu.Lock()
+		s.app.debug.threadID = body.ThreadID
+		s.app.debug.mu.Unlock()
+		// Requests must not be sent from the read loop, it delivers their responses
+		go func() {
+			frames, _ := s.stackTrace(body.ThreadID)
+			s.app.emitDebug("debug-stopped", map[string]any{
+				"reason":      body.Reason,
+				"description": body.Description,
+				"threadId":    body.ThreadID,
+				"frames":      frames,
+			})
+		}()
+	case "continued":
+		s.app.emitDebug("debug-continued", nil)
+	case "exited":
+		var body struct {
+			ExitCode int `json:"exitCode"`
+		}
+		json.Unmarshal(msg.Body, &body)
+		s.app.debugOutput("console", fmt.Sprintf("[Programm beendet mit Code %d]\n", body.ExitCode))
+	case "terminated":
+		// sessionEnded reports the end to the frontend
+		go func() {
+			s.call("disconnect", map[string]bool{"terminateDebuggee": true}, nil)
+			s.close()
+		}()
+	}
+}
+
+func (s *debugSession) stackTrace(threadID int) ([]DebugStackFrame, error) {
+	var body struct {
+		StackFrames []struct {
+			ID     int    `json:"id"`
+			Name   string `json:"name"`
+			Line   int    `json:"line"`
+			Column int    `json:"column"`
+			Source *struct {
+				Path string `json:"path"`
+			} `json:"source"`
+		} `json:"stackFrames"`
+	}
+	err := s.call("stackTrace", map[string]any{"threadId": threadID, "startFrame": 0, "levels": 50}, &body)
+	if err != nil {
+		return nil, err
+	}
+	frames := make([]DebugStackFrame, 0, len(body.StackFrames))
+	for _, f := range body.StackFrames {
+		frame := DebugStackFrame{ID: f.ID, Name: f.Name, Line: f.Line, Column: f.Column}
+		if f.Source != nil {
+			frame.Path = f.Source.Path
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+func (s *debugSession) setBreakpoints(path string, lines []int) ([]DebugBreakpoint, error) {
+	bps := make([]map[string]int, len(lines))
+	for i, l := range lines {
+		bps[i] = map[string]int{"line": l}
+	}
+	var body struct {
+		Breakpoints []DebugBreakpoint `json:"breakpoints"`
+	}
+	err := s.call("setBreakpoints", map[string]any{
+		"source":      map[string]string{"path": path, "name": filepath.Base(path)},
+		"breakpoints": bps,
+	}, &body)
+	return body.Breakpoints, err
+}
+
+func (s *debugSession) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.conn.Close()
+		if s.cmd.Process != nil {
+			s.cmd.Process.Kill()
+		}
+		s.app.debug.sessionEnded(s)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Manager
+// ---------------------------------------------------------------------------
+
+// debugManager holds the breakpoints per file and the (single) running session
+type debugManager struct {
+	mu          sync.Mutex
+	session     *debugSession
+	starting    bool             // DebugStart is setting up a session
+	breakpoints map[string][]int // 1-based lines per file
+	threadID    int              // Goroutine of the last stop, target of step commands
+}
+
+func newDebugManager() *debugManager {
+	return &debugManager{breakpoints: make(map[string][]int)}
+}
+
+func (m *debugManager) current() (*debugSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.session == nil {
+		return nil, fmt.Errorf("Keine aktive Debug-Sitzung")
+	}
+	return m.session, nil
+}
+
+func (m *debugManager) sessionEnded(s *debugSession) {
+	m.mu.Lock()
+	ended := m.session == s
+	if ended {
+		m.session = nil
+	}
+	m.mu.Unlock()
+	if ended {
+		s.app.emitDebug("debug-terminated", nil)
+	}
+}
+
+func (a *App) emitDebug(event string, data any) {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, event, data)
+	}
+}
+
+func (a *App) debugOutput(category string, output string) {
+	a.emitDebug("debug-output", map[string]string{"category": category, "output": output})
+}
+
+// DebugStart debugs the package of path. mode "test" debugs its tests, or
+// only the test function at line (0-based) when line >= 0.
+func (a *App) DebugStart(path string, mode string, line int) error {
+	// The slot is taken before delve starts, so a second start fails
+	a.debug.mu.Lock()
+	busy := a.debug.session != nil || a.debug.starting
+	if !busy {
+		a.debug.starting = true
+	}
+	a.debug.mu.Unlock()
+	if busy {
+		return fmt.Errorf("Es läuft bereits eine Debug-Sitzung")
+	}
+	defer func() {
+		a.debug.mu.Lock()
+		a.debug.starting = false
+		a.debug.mu.Unlock()
+	}()
+
+	dir := filepath.Dir(path)
+	launch := map[string]any{
+		"request":    "launch",
+		"mode":       "debug",
+		"program":    dir,
+		"cwd":        dir,
+		"outputMode": "remote",
+	}
+	if mode == "test" {
+		launch["mode"] = "test"
+		if line >= 0 {
+			if test, err := goTestAt(path, line); err == nil {
+				launch["args"] = []string{"-test.run", "^" + test + "$"}
+			}
+		}
+	}
+
+	s, err := startDelve(a, dir)
+	if err != nil {
+		return err
+	}
+	fail := func(err error) error {
+		s.close()
+		return fmt.Errorf("Fehler beim Starten des Debuggers: %w", err)
+	}
+
+	if err := s.call("initialize", map[string]any{
+		"clientID":                     "leoedit",
+		"clientName":                   "Leoedit",
+		"adapterID":                    "go",
+		"pathFormat":                   "path",
+		"linesStartAt1":                true,
+		"columnsStartAt1":              true,
+		"supportsVariableType":         true,
+		"supportsRunInTerminalRequest": false,
+	}, nil); err != nil {
+		return fail(err)
+	}
+
+	a.debug.mu.Lock()
+	a.debug.session = s
+	breakpoints := make(map[string][]int, len(a.debug.breakpoints))
+	for p, lines := range a.debug.breakpoints {
+		breakpoints[p] = lines
+	}
+	a.debug.mu.Unlock()
+
+	// Delve answers launch after building the program, then sends "initialized"
+	if err := s.call("launch", launch, nil); err != nil {
+		return fail(err)
+	}
+	select {
+	case <-s.initialized:
+	case <-s.done:
+		return fail(fmt.Errorf("Delve wurde beendet"))
+	case <-time.After(dapRequestTimeout):
+		return fail(fmt.Errorf("keine Initialisierung"))
+	}
+
+	for p, lines := range breakpoints {
+		if bps, err := s.setBreakpoints(p, lines); err == nil {
+			a.emitDebug("debug-breakpoints", map[string]any{"path": p, "breakpoints": bps})
+		}
+	}
+	if err := s.call("configurationDone", nil, nil); err != nil {
+		return fail(err)
+	}
+
+	a.emitDebug("debug-started", map[string]string{"path": path, "mode": mode})
+	return nil
+}
+
+// DebugStop ends the debug session and the debugged program
+func (a *App) DebugStop() error {
+	s, err := a.debug.current()
+	if err != nil {
+		return nil
+	}
+	s.call("disconnect", map[string]bool{"terminateDebuggee": true}, nil)
+	s.close()
+	return nil
+}
+
+func (a *App) debugStep(command string) error {
+	s, err := a.debug.current()
+	if err != nil {
+		return err
+	}
+	a.debug.mu.Lock()
+	threadID := a.debug.threadID
+	a.debug.mu.Unlock()
+	return s.call(command, map[string]int{"threadId": threadID}, nil)
+}
+
+// DebugContinue resumes all goroutines
+func (a *App) DebugContinue() error { return a.debugStep("continue") }
+
+// DebugNext steps over the current line
+func (a *App) DebugNext() error { return a.debugStep("next") }
+
+// DebugStepIn steps into the call on the current line
+func (a *App) DebugStepIn() error { return a.debugStep("stepIn") }
+
+// DebugStepOut runs until the current function returns
+func (a *App) DebugStepOut() error { return a.debugStep("stepOut") }
+
+// DebugPause stops the running program
+func (a *App) DebugPause() error { return a.debugStep("pause") }
+
+// DebugSetBreakpoints replaces the breakpoints of a file (1-based lines). In a
+// running session they are sent to the debugger right away.
+func (a *App) DebugSetBreakpoints(path string, lines []int) ([]DebugBreakpoint, error) {
+	sort.Ints(lines)
+	a.debug.mu.Lock()
+	if len(lines) == 0 {
+		delete(a.debug.breakpoints, path)
+	} else {
+		a.debug.breakpoints[path] = lines
+	}
+	s := a.debug.session
+	a.debug.mu.Unlock()
+
+	if s == nil {
+		result := make([]DebugBreakpoint, len(lines))
+		for i, l := range lines {
+			result[i] = DebugBreakpoint{Line: l}
+		}
+		return result, nil
+	}
+	return s.setBreakpoints(path, lines)
+}
+
+// DebugGetBreakpoints returns the breakpoints of all files
+func (a *App) DebugGetBreakpoints() map[string][]int {
+	a.debug.mu.Lock()
+	defer a.debug.mu.Unlock()
+	result := make(map[string][]int, len(a.debug.breakpoints))
+	for p, lines := range a.debug.breakpoints {
+		result[p] = append([]int(nil), lines...)
+	}
+	return result
+}
+
+// DebugThreads returns the goroutines of the stopped program
+func (a *App) DebugThreads() ([]DebugThread, error) {
+	s, err := a.debug.current()
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Threads []DebugThread `json:"threads"`
+	}
+	if err := s.call("threads", nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Threads, nil
+}
+
+// DebugStackTrace returns the call stack of a goroutine and makes it the
+// target of the step commands
+func (a *App) DebugStackTrace(threadID int) ([]DebugStackFrame, error) {
+	s, err := a.debug.current()
+	if err != nil {
+		return nil, err
+	}
+	a.debug.mu.Lock()
+	a.debug.threadID = threadID
+	a.debug.mu.Unlock()
+	return s.stackTrace(threadID)
+}
+
+// DebugScopes returns the variable scopes (locals, arguments) of a stack frame
+func (a *App) DebugScopes(frameID int) ([]DebugScope, error) {
+	s, err := a.debug.current()
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Scopes []DebugScope `json:"scopes"`
+	}
+	if err := s.call("scopes", map[string]int{"frameId": frameID}, &body); err != nil {
+		return nil, err
+	}
+	return body.Scopes, nil
+}
+
+// DebugVariables returns the children of a scope or structured variable
+func (a *App) DebugVariables(reference int) ([]DebugVariable, error) {
+	s, err := a.debug.current()
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Variables []DebugVariable `json:"variables"`
+	}
+	if err := s.call("variables", map[string]int{"variablesReference": reference}, &body); err != nil {
+		return nil, err
+	}
+	return body.Variables, nil
+}
+
+// DebugEvaluate evaluates an expression in the context of a stack frame
+func (a *App) DebugEvaluate(expression string, frameID int) (DebugVariable, error) {
+	s, err := a.debug.current()
+	if err != nil {
+		return DebugVariable{}, err
+	}
+	var body struct {
+		Result             string `json:"result"`
+		Type               string `json:"type"`
+		VariablesReference int    `json:"variablesReference"`
+	}
+	err = s.call("evaluate", map[string]any{"expression": expression, "frameId": frameID, "context": "repl"}, &body)
+	if err != nil {
+		return DebugVariable{}, err
+	}
+	return DebugVariable{Name: expression, Value: body.Result, Type: body.Type, VariablesReference: body.VariablesReference}, nil
+}
@@ -0,0 +1,529 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+// diffEdit is one line of an edit script; A indexes the old lines (equal,
+// delete), B the new ones (equal, insert)
+type diffEdit struct {
+	Op   diffOp
+	A, B int
+}
+
+// DiffOptions control how lines are compared and how much context is kept
+type DiffOptions struct {
+	IgnoreWhitespace bool `json:"ignoreWhitespace"`
+	IgnoreCase       bool `json:"ignoreCase"`
+	Patience         bool `json:"patience"` // Patience instead of Myers
+	Context          int  `json:"context"`  // Lines around each change, negative: the whole file
+}
+
+// DiffSegment is a piece of a line; Changed marks the words that differ
+type DiffSegment struct {
+	Text    string `json:"text"`
+	Changed bool   `json:"changed"`
+}
+
+// DiffRow is one row of the side-by-side view. Line numbers are 1-based,
+// 0 where the side has no line.
+type DiffRow struct {
+	Kind      string        `json:"kind"` // equal, delete, insert, change
+	LeftLine  int           `json:"leftLine"`
+	RightLine int           `json:"rightLine"`
+	Left      []DiffSegment `json:"left"`
+	Right     []DiffSegment `json:"right"`
+}
+
+// DiffHunk is a group of changes with its context; starts are 1-based
+type DiffHunk struct {
+	LeftStart  int       `json:"leftStart"`
+	LeftCount  int       `json:"leftCount"`
+	RightStart int       `json:"rightStart"`
+	RightCount int       `json:"rightCount"`
+	Rows       []DiffRow `json:"rows"`
+}
+
+// DiffResult is the structured comparison of two texts
+type DiffResult struct {
+	LeftName  string     `json:"leftName"`
+	RightName string     `json:"rightName"`
+	Hunks     []DiffHunk `json:"hunks"`
+	Added     int        `json:"added"`
+	Removed   int        `json:"removed"`
+	Changed   int        `json:"changed"`
+	Identical bool       `json:"identical"`
+}
+
+// splitLines splits text into lines without their line ends
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// normalize returns the comparison key of a line under the options
+func (o DiffOptions) normalize(line string) string {
+	if o.IgnoreWhitespace {
+		line = strings.Join(strings.Fields(line), "")
+	}
+	if o.IgnoreCase {
+		line = strings.ToLower(line)
+	}
+	return line
+}
+
+func (o DiffOptions) keys(lines []string) []string {
+	if !o.IgnoreWhitespace && !o.IgnoreCase {
+		return lines
+	}
+	keys := make([]string, len(lines))
+	for i, l := range lines {
+		keys[i] = o.normalize(l)
+	}
+	return keys
+}
+
+// diffLines computes the edit script for two line slices under the options
+func diffLines(a, b []string, opts DiffOptions) []diffEdit {
+	ka, kb := opts.keys(a), opts.keys(b)
+	if opts.Patience {
+		return patienceDiff(ka, kb)
+	}
+	return myersDiff(ka, kb)
+}
+
+// myersDiff computes a shortest edit script from a to b (Myers 1986)
+func myersDiff(a, b []string) []diffEdit {
+	return diffRange(a, b, 0, len(a), 0, len(b), myersMiddle)
+}
+
+// patienceDiff anchors the diff on lines that occur exactly once on both
+// sides and falls back to Myers between the anchors
+func patienceDiff(a, b []string) []diffEdit {
+	return diffRange(a, b, 0, len(a), 0, len(b), patienceMiddle)
+}
+
+// diffRange strips the common prefix and suffix of a[a0:a1] and b[b0:b1]
+// and leaves the rest to middle
+func diffRange(a, b []string, a0, a1, b0, b1 int,
+	middle func(a, b []string, a0, a1, b0, b1 int) []diffEdit) []diffEdit {
+	var edits []diffEdit
+	for a0 < a1 && b0 < b1 && a[a0] == b[b0] {
+		edits = append(edits, diffEdit{diffEqual, a0, b0})
+		a0++
+		b0++
+	}
+	suf := 0
+	for a1-suf > a0 && b1-suf > b0 && a[a1-1-suf] == b[b1-1-suf] {
+		suf++
+	}
+	edits = append(edits, middle(a, b, a0, a1-suf, b0, b1-suf)...)
+	for i := suf; i > 0; i-- {
+		edits = append(edits, diffEdit{diffEqual, a1 - i, b1 - i})
+	}
+	return edits
+}
+
+// myersMiddle is the linear space variant of Myers: the range is split
+// where a shortest edit script crosses the middle diagonal, both halves
+// recurse. The trace of the plain algorithm needs O((N+M)·D) memory.
+func myersMiddle(a, b []string, a0, a1, b0, b1 int) []diffEdit {
+	var edits []diffEdit
+	var rec func(a0, a1, b0, b1 int)
+	rec = func(a0, a1, b0, b1 int) {
+		for a0 < a1 && b0 < b1 && a[a0] == b[b0] {
+			edits = append(edits, diffEdit{diffEqual, a0, b0})
+			a0++
+			b0++
+		}
+		suf := 0
+		for a1-suf > a0 && b1-suf > b0 && a[a1-1-suf] == b[b1-1-suf] {
+			suf++
+		}
+		a1, b1 = a1-suf, b1-suf
+
+		x, y := -1, -1
+		if a0 < a1 && b0 < b1 {
+			x, y = myersSplit(a, b, a0, a1, b0, b1)
+		}
+		if x < 0 || (x == a0 && y == b0) || (x == a1 && y == b1) {
+			// Nothing in common: all old lines go, all new ones come
+			for i := a0; i < a1; i++ {
+				edits = append(edits, diffEdit{diffDelete, i, b0})
+			}
+			for j := b0; j < b1; j++ {
+				edits = append(edits, diffEdit{diffInsert, a1, j})
+			}
+		} else {
+			rec(a0, x, b0, y)
+			rec(x, a1, y, b1)
+		}
+
+		for i := suf; i > 0; i-- {
+			edits = append(edits, diffEdit{diffEqual, a1 + suf - i, b1 + suf - i})
+		}
+	}
+	rec(a0, a1, b0, b1)
+	return edits
+}
+
+// myersSplit runs the forward and the reverse search at the same time and
+// returns the point where they overlap, or -1, -1 if a[a0:a1] and b[b0:b1]
+// have no line in common
+func myersSplit(a, b []string, a0, a1, b0, b1 int) (int, int) {
+	n, m := a1-a0, b1-b0
+	maxD := (n + m + 1) / 2
+	off := maxD
+	v1 := make([]int, 2*maxD+2)
+	v2 := make([]int, 2*maxD+2)
+	for i := range v1 {
+		v1[i], v2[i] = -1, -1
+	}
+	v1[off+1], v2[off+1] = 0, 0
+	delta := n - m
+	// With an odd delta the forward search meets the reverse one
+	front := delta%2 != 0
+	// Diagonals that left the grid are skipped
+	k1start, k1end, k2start, k2end := 0, 0, 0, 0
+	for d := 0; d < maxD; d++ {
+		for k1 := -d + k1start; k1 <= d-k1end; k1 += 2 {
+			k1off := off + k1
+			var x1 int
+			if k1 == -d || (k1 != d && v1[k1off-1] < v1[k1off+1]) {
+				x1 = v1[k1off+1]
+			} else {
+				x1 = v1[k1off-1] + 1
+			}
+			y1 := x1 - k1
+			for x1 < n && y1 < m && a[a0+x1] == b[b0+y1] {
+				x1++
+				y1++
+			}
+			v1[k1off] = x1
+			switch {
+			case x1 > n:
+				k1end += 2
+			case y1 > m:
+				k1start += 2
+			case front:
+				if k2off := off + delta - k1; k2off >= 0 && k2off < len(v2) && v2[k2off] != -1 {
+					if x1 >= n-v2[k2off] {
+						return a0 + x1, b0 + y1
+					}
+				}
+			}
+		}
+		for k2 := -d + k2start; k2 <= d-k2end; k2 += 2 {
+			k2off := off + k2
+			var x2 int
+			if k2 == -d || (k2 != d && v2[k2off-1] < v2[k2off+1]) {
+				x2 = v2[k2off+1]
+			} else {
+				x2 = v2[k2off-1] + 1
+			}
+			y2 := x2 - k2
+			for x2 < n && y2 < m && a[a1-1-x2] == b[b1-1-y2] {
+				x2++
+				y2++
+			}
+			v2[k2off] = x2
+			switch {
+			case x2 > n:
+				k2end += 2
+			case y2 > m:
+				k2start += 2
+			case !front:
+				if k1off := off + delta - k2; k1off >= 0 && k1off < len(v1) && v1[k1off] != -1 {
+					x1 := v1[k1off]
+					if x1 >= n-x2 {
+						return a0 + x1, b0 + off + x1 - k1off
+					}
+				}
+			}
+		}
+	}
+	return -1, -1
+}
+
+func patienceMiddle(a, b []string, a0, a1, b0, b1 int) []diffEdit {
+	if a0 == a1 || b0 == b1 {
+		return myersMiddle(a, b, a0, a1, b0, b1)
+	}
+
+	// Lines unique in both ranges, in the order of a
+	type anchor struct{ a, b int }
+	count := map[string][2]int{}
+	pos := map[string]int{}
+	for i := a0; i < a1; i++ {
+		c := count[a[i]]
+		c[0]++
+		count[a[i]] = c
+	}
+	for j := b0; j < b1; j++ {
+		c := count[b[j]]
+		c[1]++
+		count[b[j]] = c
+		pos[b[j]] = j
+	}
+	var candidates []anchor
+	for i := a0; i < a1; i++ {
+		if c := count[a[i]]; c[0] == 1 && c[1] == 1 {
+			candidates = append(candidates, anchor{i, pos[a[i]]})
+		}
+	}
+	if len(candidates) == 0 {
+		return myersMiddle(a, b, a0, a1, b0, b1)
+	}
+
+	// Longest increasing subsequence of the b positions (patience sorting)
+	tails := []int{}
+	prev := make([]int, len(candidates))
+	for i, c := range candidates {
+		k := sort.Search(len(tails), func(t int) bool { return candidates[tails[t]].b >= c.b })
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	anchors := make([]anchor, len(tails))
+	for i, k := len(tails)-1, tails[len(tails)-1]; i >= 0; i, k = i-1, prev[k] {
+		anchors[i] = candidates[k]
+	}
+
+	var edits []diffEdit
+	pa, pb := a0, b0
+	for _, an := range anchors {
+		edits = append(edits, diffRange(a, b, pa, an.a, pb, an.b, patienceMiddle)...)
+		edits = append(edits, diffEdit{diffEqual, an.a, an.b})
+		pa, pb = an.a+1, an.b+1
+	}
+	return append(edits, diffRange(a, b, pa, a1, pb, b1, patienceMiddle)...)
+}
+
+// hunkRanges groups the edits into [start, end) ranges: each change with
+// context equal lines around it, changes closer than 2*context merged.
+// A negative context yields one range over everything.
+func hunkRanges(edits []diffEdit, context int) [][2]int {
+	if context < 0 {
+		for _, e := range edits {
+			if e.Op != diffEqual {
+				return [][2]int{{0, len(edits)}}
+			}
+		}
+		return nil
+	}
+	var ranges [][2]int
+	for i := 0; i < len(edits); {
+		if edits[i].Op == diffEqual {
+			i++
+			continue
+		}
+		start := max(i-context, 0)
+		end := i
+		for end < len(edits) {
+			if edits[end].Op != diffEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].Op == diffEqual {
+				run++
+			}
+			if run == len(edits) || run-end > 2*context {
+				end = min(end+context, len(edits))
+				break
+			}
+			end = run
+		}
+		ranges = append(ranges, [2]int{start, end})
+		i = end
+	}
+	return ranges
+}
+
+// unifiedDiff renders the difference of a and b as a unified diff with
+// context lines around each change; empty if both are equal
+func unifiedDiff(nameA, nameB string, a, b []string, context int) string {
+	return formatUnified(nameA, nameB, a, b, myersDiff(a, b), context)
+}
+
+func formatUnified(nameA, nameB string, a, b []string, edits []diffEdit, context int) string {
+	var sb strings.Builder
+	for _, r := range hunkRanges(edits, context) {
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", nameA, nameB)
+		}
+		aStart, bStart := edits[r[0]].A, edits[r[0]].B
+		aLen, bLen := 0, 0
+		var body strings.Builder
+		for _, e := range edits[r[0]:r[1]] {
+			switch e.Op {
+			case diffEqual:
+				aLen++
+				bLen++
+				body.WriteString(" " + a[e.A] + "\n")
+			case diffDelete:
+				aLen++
+				body.WriteString("-" + a[e.A] + "\n")
+			case diffInsert:
+				bLen++
+				body.WriteString("+" + b[e.B] + "\n")
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		sb.WriteString(body.String())
+	}
+	return sb.String()
+}
+
+// hunkRange formats the 1-based start and length of a hunk side
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+// compareTexts builds the side-by-side diff of two texts
+func compareTexts(leftName, left, rightName, right string, opts DiffOptions) DiffResult {
+	a, b := splitLines(left), splitLines(right)
+	edits := diffLines(a, b, opts)
+	res := DiffResult{LeftName: leftName, RightName: rightName, Hunks: []DiffHunk{}}
+
+	for _, r := range hunkRanges(edits, opts.Context) {
+		first := edits[r[0]]
+		hunk := DiffHunk{LeftStart: first.A + 1, RightStart: first.B + 1}
+		var dels, ins []int
+		flush := func() {
+			// Deleted and inserted lines side by side become changed rows
+			n := min(len(dels), len(ins))
+			for i := 0; i < n; i++ {
+				l, r := wordDiff(a[dels[i]], b[ins[i]], opts)
+				hunk.Rows = append(hunk.Rows, DiffRow{Kind: "change", LeftLine: dels[i] + 1, RightLine: ins[i] + 1, Left: l, Right: r})
+				res.Changed++
+			}
+			for _, d := range dels[n:] {
+				hunk.Rows = append(hunk.Rows, DiffRow{Kind: "delete", LeftLine: d + 1, Left: []DiffSegment{{Text: a[d], Changed: true}}})
+				res.Removed++
+			}
+			for _, i := range ins[n:] {
+				hunk.Rows = append(hunk.Rows, DiffRow{Kind: "insert", RightLine: i + 1, Right: []DiffSegment{{Text: b[i], Changed: true}}})
+				res.Added++
+			}
+			dels, ins = dels[:0], ins[:0]
+		}
+		for _, e := range edits[r[0]:r[1]] {
+			switch e.Op {
+			case diffEqual:
+				flush()
+				hunk.LeftCount++
+				hunk.RightCount++
+				hunk.Rows = append(hunk.Rows, DiffRow{Kind: "equal", LeftLine: e.A + 1, RightLine: e.B + 1,
+					Left: []DiffSegment{{Text: a[e.A]}}, Right: []DiffSegment{{Text: b[e.B]}}})
+			case diffDelete:
+				hunk.LeftCount++
+				dels = append(dels, e.A)
+			case diffInsert:
+				hunk.RightCount++
+				ins = append(ins, e.B)
+			}
+		}
+		flush()
+		res.Hunks = append(res.Hunks, hunk)
+	}
+	res.Identical = res.Added == 0 && res.Removed == 0 && res.Changed == 0
+	return res
+}
+
+// wordTokens splits a line into words, runs of whitespace and single other characters
+func wordTokens(line string) []string {
+	var tokens []string
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		j := i + 1
+		switch {
+		case isWordRune(runes[i]):
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+		case unicode.IsSpace(runes[i]):
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+		}
+		tokens = append(tokens, string(runes[i:j]))
+		i = j
+	}
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wordDiff marks the differing words of a changed line pair
+func wordDiff(left, right string, opts DiffOptions) ([]DiffSegment, []DiffSegment) {
+	ta, tb := wordTokens(left), wordTokens(right)
+	key := func(tokens []string) []string {
+		keys := make([]string, len(tokens))
+		for i, t := range tokens {
+			switch {
+			case opts.IgnoreWhitespace && strings.TrimSpace(t) == "":
+				keys[i] = " "
+			case opts.IgnoreCase:
+				keys[i] = strings.ToLower(t)
+			default:
+				keys[i] = t
+			}
+		}
+		return keys
+	}
+
+	var l, r []DiffSegment
+	add := func(segs []DiffSegment, text string, changed bool) []DiffSegment {
+		if n := len(segs); n > 0 && segs[n-1].Changed == changed {
+			segs[n-1].Text += text
+			return segs
+		}
+		return append(segs, DiffSegment{Text: text, Changed: changed})
+	}
+	for _, e := range myersDiff(key(ta), key(tb)) {
+		switch e.Op {
+		case diffEqual:
+			l = add(l, ta[e.A], false)
+			r = add(r, tb[e.B], false)
+		case diffDelete:
+			l = add(l, ta[e.A], true)
+		case diffInsert:
+			r = add(r, tb[e.B], true)
+		}
+	}
+	return l, r
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	goruntime "runtime"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Default ignore globs for directory comparisons
+var defaultDirCompareIgnore = []string{".git", "node_modules"}
+
+// DirCompareOptions control a directory comparison
+type DirCompareOptions struct {
+	// Globs as in WorkspaceSettings.Exclude; nil uses defaultDirCompareIgnore
+	Ignore []string `json:"ignore"`
+	// Hash files of equal size even if their mtime matches
+	Thorough bool `json:"thorough"`
+}
+
+// DirCompareEntry is a file or folder present on at least one side
+type DirCompareEntry struct {
+	Path   string `json:"path"` // Relative, slash separated
+	Name   string `json:"name"`
+	Depth  int    `json:"depth"`
+	IsDir  bool   `json:"isDir"`
+	Status string `json:"status"` // added (only right), removed (only left), changed, identical
+	// Size and mtime (unix ms) per side, -1 where the entry is missing
+	LeftSize      int64 `json:"leftSize"`
+	RightSize     int64 `json:"rightSize"`
+	LeftModified  int64 `json:"leftModified"`
+	RightModified int64 `json:"rightModified"`
+	// Set when the folder could not be read; its content is missing
+	Error string `json:"error,omitempty"`
+}
+
+// DirCompareResult lists all entries in tree order
+type DirCompareResult struct {
+	Left      string            `json:"left"`
+	Right     string            `json:"right"`
+	Entries   []DirCompareEntry `json:"entries"`
+	Added     int               `json:"added"`
+	Removed   int               `json:"removed"`
+	Changed   int               `json:"changed"`
+	Identical int               `json:"identical"`
+}
+
+// dirComparer walks both trees together; files that need a content
+// comparison are hashed in parallel afterwards
+type dirComparer struct {
+	left, right string
+	opts        DirCompareOptions
+	entries     []DirCompareEntry
+	pending     []int // Indexes of files to hash
+}
+
+func (c *dirComparer) walk(rel string, depth int) error {
+	left, err := readDirInfos(filepath.Join(c.left, filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+	right, err := readDirInfos(filepath.Join(c.right, filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(left)+len(right))
+	for name := range left {
+		names = append(names, name)
+	}
+	for name := range right {
+		if _, ok := left[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// Folders first, like the explorer
+		di := isDirInfo(left[names[i]]) || isDirInfo(right[names[i]])
+		dj := isDirInfo(left[names[j]]) || isDirInfo(right[names[j]])
+		if di != dj {
+			return di
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		entryRel := path.Join(rel, name)
+		if matchExcludes(c.opts.Ignore, entryRel) {
+			continue
+		}
+		l, r := left[name], right[name]
+		e := DirCompareEntry{Path: entryRel, Name: name, Depth: depth,
+			LeftSize: -1, RightSize: -1, LeftModified: -1, RightModified: -1}
+		if l != nil {
+			e.LeftSize, e.LeftModified = fileSize(l), l.ModTime().UnixMilli()
+		}
+		if r != nil {
+			e.RightSize, e.RightModified = fileSize(r), r.ModTime().UnixMilli()
+		}
+		e.IsDir = isDirInfo(l) || isDirInfo(r)
+
+		switch {
+		case l == nil:
+			e.Status = "added"
+		case r == nil:
+			e.Status = "removed"
+		case l.IsDir() != r.IsDir():
+			e.Status = "changed"
+		case e.IsDir:
+			e.Status = "identical" // Set from the children below
+		case l.Size() != r.Size():
+			e.Status = "changed"
+		case l.ModTime().Equal(r.ModTime()) && !c.opts.Thorough:
+			e.Status = "identical"
+		default:
+			e.Status = "identical"
+			c.pending = append(c.pending, len(c.entries))
+		}
+
+		c.entries = append(c.entries, e)
+		if e.IsDir {
+			// One-sided folders are listed with their content as well. An
+			// unreadable folder is marked, the comparison goes on.
+			index := len(c.entries) - 1
+			if err := c.walk(entryRel, depth+1); err != nil {
+				c.entries[index].Status = "changed"
+				c.entries[index].Error = err.Error()
+			}
+		}
+	}
+	return nil
+}
+
+// readDirInfos lists a directory by name; a missing directory, or a file
+// where the other side has a folder, is empty
+func readDirInfos(dir string) (map[string]os.FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) || errors.Is(err, syscall.ENOTDIR) {
+		return map[string]os.FileInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	infos := make(map[string]os.FileInfo, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		infos[entry.Name()] = info
+	}
+	return infos, nil
+}
+
+// fileSize is the size of a file and 0 for folders
+func fileSize(info os.FileInfo) int64 {
+	if info.IsDir() {
+		return 0
+	}
+	return info.Size()
+}
+
+func isDirInfo(info os.FileInfo) bool {
+	return info != nil && info.IsDir()
+}
+
+func hashFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// hashPending compares the contents of the pending files in parallel
+func (c *dirComparer) hashPending() {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, goruntime.NumCPU())
+	for _, index := range c.pending {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(e *DirCompareEntry) {
+			defer func() { <-sem; wg.Done() }()
+			rel := filepath.FromSlash(e.Path)
+			lh, err1 := hashFile(filepath.Join(c.left, rel))
+			rh, err2 := hashFile(filepath.Join(c.right, rel))
+			if err1 != nil || err2 != nil || string(lh) != string(rh) {
+				e.Status = "changed"
+			}
+		}(&c.entries[index])
+	}
+	wg.Wait()
+}
+
+// propagate marks folders as changed when anything below them differs
+func (c *dirComparer) propagate() {
+	for i := len(c.entries) - 1; i >= 0; i-- {
+		e := c.entries[i]
+		if e.Status == "identical" || e.Depth == 0 {
+			continue
+		}
+		// The parent is the closest preceding entry one level up
+		for j := i - 1; j >= 0; j-- {
+			if c.entries[j].Depth == e.Depth-1 {
+				if c.entries[j].Status == "identical" {
+					c.entries[j].Status = "changed"
+				}
+				break
+			}
+		}
+	}
+}
+
+// CompareDirectories compares two folder trees by size, mtime and content hash
+func (a *App) CompareDirectories(left, right string, opts DirCompareOptions) (DirCompareResult, error) {
+	for _, dir := range []string{left, right} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return DirCompareResult{}, fmt.Errorf("Kein Ordner: %s", dir)
+		}
+	}
+	if opts.Ignore == nil {
+		opts.Ignore = defaultDirCompareIgnore
+	}
+
+	c := &dirComparer{left: filepath.Clean(left), right: filepath.Clean(right), opts: opts}
+	if err := c.walk("", 0); err != nil {
+		return DirCompareResult{}, fmt.Errorf("Fehler beim Vergleichen: %w", err)
+	}
+	c.hashPending()
+	c.propagate()
+
+	res := DirCompareResult{Left: c.left, Right: c.right, Entries: c.entries}
+	if res.Entries == nil {
+		res.Entries = []DirCompareEntry{}
+	}
+	for _, e := range c.entries {
+		if e.IsDir {
+			continue
+		}
+		switch e.Status {
+		case "added":
+			res.Added++
+		case "removed":
+			res.Removed++
+		case "changed":
+			res.Changed++
+		case "identical":
+			res.Identical++
+		}
+	}
+	return res, nil
+}
+
+// CopyCompareEntry copies a file or folder (relative path) from one
+// compared tree to the other, replacing what is there. The replaced entry
+// goes to the trash; if that fails the error has op "trash" and the entry
+// is only deleted when permanent is set.
+func (a *App) CopyCompareEntry(fromRoot, toRoot, rel string, permanent bool) FileOpResult {
+	src := filepath.Join(fromRoot, filepath.FromSlash(rel))
+	dst := filepath.Join(toRoot, filepath.FromSlash(rel))
+	if !strings.HasPrefix(dst, filepath.Clean(toRoot)+string(filepath.Separator)) {
+		return FileOpResult{Path: dst, Error: newFileOpError("copy", dst, "invalid", "Ungültiger Pfad: %s", rel)}
+	}
+	if _, err := os.Stat(src); err != nil {
+		return fileOpResult("copy", src, err)
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		res := a.TrashPath(dst)
+		if res.Error != nil && !permanent {
+			return res
+		}
+		if res.Error != nil {
+			if err := os.RemoveAll(dst); err != nil {
+				return fileOpResult("copy", dst, err)
+			}
+		}
+	}
+	if err := copyTree(src, dst); err != nil {
+		return fileOpResult("copy", dst, err)
+	}
+	return FileOpResult{Path: dst}
+}
+
+// copyTree copies a file or a folder recursively; symlinks are copied as links
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		sub, _ := filepath.Rel(src, p)
+		target := filepath.Join(dst, sub)
+		switch {
+		case fi.IsDir():
+			return os.MkdirAll(target, fi.Mode().Perm()|0700)
+		case fi.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			os.Remove(target)
+			return os.Symlink(link, target)
+		}
+		return copyFile(p, target, fi)
+	})
+}
+
+// copyFile copies content, permissions and mtime
+func copyFile(src, dst string, info os.FileInfo) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// SelectCompareDirectory asks for one side of a folder comparison; "" when cancelled
+func (a *App) SelectCompareDirectory(title string) (string, error) {
+	return runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		DefaultDirectory: a.Config.LastDirectory,
+		Title:            title,
+	})
+}
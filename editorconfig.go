@@ -0,0 +1,468 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// EditorConfig holds the .editorconfig properties resolved for one file.
+// Empty strings and zero values mean "not set".
+type EditorConfig struct {
+	IndentStyle            string `json:"indentStyle"` // space, tab
+	IndentSize             int    `json:"indentSize"`
+	TabWidth               int    `json:"tabWidth"`
+	EndOfLine              string `json:"endOfLine"` // lf, crlf, cr
+	Charset                string `json:"charset"`   // utf-8, utf-8-bom, latin1, utf-16be, utf-16le
+	TrimTrailingWhitespace bool   `json:"trimTrailingWhitespace"`
+	// nil: leave the end of the file alone, false: remove final newlines
+	InsertFinalNewline *bool `json:"insertFinalNewline,omitempty"`
+}
+
+type editorConfigSection struct {
+	re         *regexp.Regexp
+	properties map[string]string
+}
+
+type editorConfigFile struct {
+	modTime  time.Time
+	root     bool
+	sections []editorConfigSection
+}
+
+// Parsed .editorconfig files, reparsed when they change on disk
+var editorConfigCache = struct {
+	sync.Mutex
+	files map[string]*editorConfigFile
+}{files: make(map[string]*editorConfigFile)}
+
+// loadEditorConfigFile returns the parsed .editorconfig at path, nil if there is none
+func loadEditorConfigFile(path string) *editorConfigFile {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return nil
+	}
+
+	editorConfigCache.Lock()
+	defer editorConfigCache.Unlock()
+	if f, ok := editorConfigCache.files[path]; ok && f.modTime.Equal(info.ModTime()) {
+		return f
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	f := parseEditorConfig(data, filepath.ToSlash(filepath.Dir(path)))
+	f.modTime = info.ModTime()
+	editorConfigCache.files[path] = f
+	return f
+}
+
+// parseEditorConfig reads the INI format of .editorconfig; dir is the
+// slash separated directory the section globs are relative to
+func parseEditorConfig(data []byte, dir string) *editorConfigFile {
+	f := &editorConfigFile{}
+	var current *editorConfigSection
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' && strings.HasSuffix(line, "]") {
+			current = nil
+			re, err := regexp.Compile(editorConfigGlobRegexp(dir, line[1:len(line)-1]))
+			if err != nil {
+				continue // Sections with invalid globs are ignored
+			}
+			f.sections = append(f.sections, editorConfigSection{re: re, properties: make(map[string]string)})
+			current = &f.sections[len(f.sections)-1]
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if current == nil {
+			if key == "root" {
+				f.root = strings.EqualFold(value, "true")
+			}
+			continue
+		}
+		current.properties[key] = strings.ToLower(value)
+	}
+	return f
+}
+
+// editorConfigGlobRegexp translates a section glob. Globs without a slash
+// match file names in any subdirectory, others match paths relative to dir.
+func editorConfigGlobRegexp(dir string, glob string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	sb.WriteString(regexp.QuoteMeta(strings.TrimSuffix(dir, "/")))
+	if strings.Contains(glob, "/") {
+		sb.WriteString("/")
+		glob = strings.TrimPrefix(glob, "/")
+	} else {
+		sb.WriteString("/(?:.*/)?")
+	}
+
+	braces := 0
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end
+		case '{':
+			end := strings.IndexByte(glob[i:], '}')
+			if end > 0 {
+				// {1..10}: numeric range
+				if lo, hi, ok := strings.Cut(glob[i+1:i+end], ".."); ok {
+					a, errA := strconv.Atoi(lo)
+					b, errB := strconv.Atoi(hi)
+					if errA == nil && errB == nil && a <= b && b-a <= 1000 {
+						nums := make([]string, 0, b-a+1)
+						for n := a; n <= b; n++ {
+							nums = append(nums, strconv.Itoa(n))
+						}
+						sb.WriteString("(?:" + strings.Join(nums, "|") + ")")
+						i += end
+						continue
+					}
+				}
+				// {single} is a literal
+				if !strings.Contains(glob[i+1:i+end], ",") && !strings.Contains(glob[i+1:i+end], "{") {
+					sb.WriteString(regexp.QuoteMeta(glob[i : i+end+1]))
+					i += end
+					continue
+				}
+			}
+			braces++
+			sb.WriteString("(?:")
+		case '}':
+			if braces > 0 {
+				braces--
+				sb.WriteString(")")
+			} else {
+				sb.WriteString(`\}`)
+			}
+		case ',':
+			if braces > 0 {
+				sb.WriteString("|")
+			} else {
+				sb.WriteString(",")
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	for ; braces > 0; braces-- {
+		sb.WriteString(")")
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// resolveEditorConfig collects the properties for path from all
+// .editorconfig files up to the nearest one with root = true
+func resolveEditorConfig(path string) EditorConfig {
+	if isRemotePath(path) {
+		return EditorConfig{}
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return EditorConfig{}
+	}
+	target := filepath.ToSlash(abs)
+
+	// Closest file last, so that it wins
+	var files []*editorConfigFile
+	for dir := filepath.Dir(abs); ; {
+		if f := loadEditorConfigFile(filepath.Join(dir, ".editorconfig")); f != nil {
+			files = append([]*editorConfigFile{f}, files...)
+			if f.root {
+				break
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	props := make(map[string]string)
+	for _, f := range files {
+		for _, s := range f.sections {
+			if s.re.MatchString(target) {
+				for k, v := range s.properties {
+					props[k] = v
+				}
+			}
+		}
+	}
+	return editorConfigFromProperties(props)
+}
+
+func editorConfigFromProperties(props map[string]string) EditorConfig {
+	for k, v := range props {
+		if v == "unset" {
+			delete(props, k)
+		}
+	}
+
+	var ec EditorConfig
+	switch props["indent_style"] {
+	case "space", "tab":
+		ec.IndentStyle = props["indent_style"]
+	}
+	ec.TabWidth, _ = strconv.Atoi(props["tab_width"])
+	indentSize := props["indent_size"]
+	if indentSize == "" && ec.IndentStyle == "tab" {
+		indentSize = "tab"
+	}
+	if indentSize == "tab" {
+		ec.IndentSize = ec.TabWidth
+	} else {
+		ec.IndentSize, _ = strconv.Atoi(indentSize)
+		if ec.TabWidth == 0 {
+			ec.TabWidth = ec.IndentSize
+		}
+	}
+	switch props["end_of_line"] {
+	case "lf", "crlf", "cr":
+		ec.EndOfLine = props["end_of_line"]
+	}
+	switch props["charset"] {
+	case "utf-8", "utf-8-bom", "latin1", "utf-16be", "utf-16le":
+		ec.Charset = props["charset"]
+	}
+	ec.TrimTrailingWhitespace = props["trim_trailing_whitespace"] == "true"
+	if v, ok := props["insert_final_newline"]; ok && (v == "true" || v == "false") {
+		b := v == "true"
+		ec.InsertFinalNewline = &b
+	}
+	return ec
+}
+
+var trailingWhitespaceRe = regexp.MustCompile(`[ \t]+(\r?\n|$)`)
+
+// cleanup applies the whitespace rules to the buffer content ("\n" line ends)
+func (ec EditorConfig) cleanup(content string) string {
+	if ec.TrimTrailingWhitespace {
+		content = trailingWhitespaceRe.ReplaceAllString(content, "$1")
+	}
+	if ec.InsertFinalNewline != nil {
+		if *ec.InsertFinalNewline {
+			if content != "" && !strings.HasSuffix(content, "\n") {
+				content += "\n"
+			}
+		} else {
+			content = strings.TrimRight(content, "\r\n")
+		}
+	}
+	return content
+}
+
+// encode converts the buffer content into the bytes written to disk
+func (ec EditorConfig) encode(content string) []byte {
+	if ec.EndOfLine != "" {
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+		switch ec.EndOfLine {
+		case "crlf":
+			content = strings.ReplaceAll(content, "\n", "\r\n")
+		case "cr":
+			content = strings.ReplaceAll(content, "\n", "\r")
+		}
+	}
+
+	switch ec.Charset {
+	case "utf-8-bom":
+		return append([]byte{0xEF, 0xBB, 0xBF}, content...)
+	case "latin1":
+		out := make([]byte, 0, len(content))
+		for _, r := range content {
+			if r > 0xFF {
+				r = '?'
+			}
+			out = append(out, byte(r))
+		}
+		return out
+	case "utf-16be", "utf-16le":
+		units := utf16.Encode([]rune(content))
+		out := make([]byte, 0, 2*len(units)+2)
+		for _, u := range append([]uint16{0xFEFF}, units...) {
+			if ec.Charset == "utf-16be" {
+				out = append(out, byte(u>>8), byte(u))
+			} else {
+				out = append(out, byte(u), byte(u>>8))
+			}
+		}
+		return out
+	}
+	return []byte(content)
+}
+
+// detectCharset names the charset given by a BOM, "" without one
+func detectCharset(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return "utf-8-bom"
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return "utf-16be"
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return "utf-16le"
+	}
+	return ""
+}
+
+// decodeText turns file bytes into buffer text: BOMs are removed, UTF-16
+// and Latin-1 files are decoded. It also returns the charset of the BOM.
+func decodeText(data []byte, charset string) (string, string) {
+	detected := detectCharset(data)
+	switch {
+	case detected == "utf-8-bom":
+		return string(data[3:]), detected
+	case detected != "":
+		big := detected == "utf-16be"
+		units := make([]uint16, 0, len(data)/2)
+		for i := 2; i+1 < len(data); i += 2 {
+			if big {
+				units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+			} else {
+				units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+			}
+		}
+		return string(utf16.Decode(units)), detected
+	case charset == "latin1" && !utf8.Valid(data):
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes), ""
+	}
+	return string(data), ""
+}
+
+// fileCharsets remembers the BOM charset of read files, so a file keeps its
+// BOM or UTF-16 on save when the EditorConfig sets no charset
+type fileCharsets struct {
+	mu     sync.Mutex
+	byPath map[string]string
+}
+
+func newFileCharsets() *fileCharsets {
+	return &fileCharsets{byPath: make(map[string]string)}
+}
+
+func (c *fileCharsets) get(path string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.byPath[path]
+}
+
+func (c *fileCharsets) set(path, charset string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if charset == "" {
+		delete(c.byPath, path)
+	} else {
+		c.byPath[path] = charset
+	}
+}
+
+// move follows renamed files and files in a renamed folder
+func (c *fileCharsets) move(from, to string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for path, charset := range c.byPath {
+		if path == from || isWithin(path, from) {
+			rel, _ := filepath.Rel(from, path)
+			delete(c.byPath, path)
+			c.byPath[filepath.Join(to, rel)] = charset
+		}
+	}
+}
+
+// inherit keeps the charset for a copy saved under a new name
+func (c *fileCharsets) inherit(from, to string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if charset, ok := c.byPath[from]; ok && from != to {
+		c.byPath[to] = charset
+	}
+}
+
+// readText reads a file for the editor together with its EditorConfig and
+// the charset of its BOM
+func (a *App) readText(path string) (string, EditorConfig, string, error) {
+	ec := resolveEditorConfig(path)
+	data, err := a.readData(path)
+	if err != nil {
+		return "", ec, "", err
+	}
+	text, charset := decodeText(data, ec.Charset)
+	a.charsets.set(path, charset)
+	return text, ec, charset, nil
+}
+
+// writeBuffer saves buffer content to path: format-on-save, the EditorConfig
+// whitespace rules, line ends and charset. Without an EditorConfig charset
+// the charset of the file as read is kept. It returns the saved buffer
+// content; the written bytes go to the local history.
+func (a *App) writeBuffer(path string, content string) (string, error) {
+	ec := resolveEditorConfig(path)
+	if ec.Charset == "" {
+		ec.Charset = a.charsets.get(path)
+	}
+	formatted := a.formatBeforeSave(path, content)
+	cleaned := ec.cleanup(formatted)
+	data := ec.encode(cleaned)
+	if err := a.writeData(path, data); err != nil {
+		return "", err
+	}
+	a.recordHistory(path, data)
+	a.configFileSaved(path)
+	return cleaned, nil
+}
+
+// GetEditorConfig returns the EditorConfig properties for path
+func (a *App) GetEditorConfig(path string) EditorConfig {
+	return resolveEditorConfig(path)
+}
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// FileOpError describes a failed explorer operation
+type FileOpError struct {
+	Op      string `json:"op"` // create, mkdir, rename, move, copy, duplicate, trash, restore, chmod
+	Path    string `json:"path"`
+	Code    string `json:"code"` // exists, notFound, permission, invalid, unsupported, io
+	Message string `json:"message"`
+}
+
+func (e *FileOpError) Error() string {
+	return e.Message
+}
+
+// FileOpResult is the path created or changed by an operation, or the error
+type FileOpResult struct {
+	Path  string       `json:"path"`
+	Error *FileOpError `json:"error,omitempty"`
+}
+
+// TrashItem is an entry of the freedesktop trash
+type TrashItem struct {
+	ID           string `json:"id"` // Name below Trash/files
+	Name         string `json:"name"`
+	OriginalPath string `json:"originalPath"`
+	DeletedAt    int64  `json:"deletedAt"` // Unix ms
+	IsDir        bool   `json:"isDir"`
+}
+
+func newFileOpError(op, path, code, format string, args ...interface{}) *FileOpError {
+	return &FileOpError{Op: op, Path: path, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// fileOpError classifies an error from the os package
+func fileOpError(op, path string, err error) *FileOpError {
+	var fe *FileOpError
+	if errors.As(err, &fe) {
+		return fe
+	}
+	name := filepath.Base(path)
+	switch {
+	case errors.Is(err, os.ErrExist):
+		return newFileOpError(op, path, "exists", "%s existiert bereits", name)
+	case errors.Is(err, os.ErrNotExist):
+		return newFileOpError(op, path, "notFound", "%s nicht gefunden", name)
+	case errors.Is(err, os.ErrPermission):
+		return newFileOpError(op, path, "permission", "Keine Berechtigung für %s", name)
+	}
+	return newFileOpError(op, path, "io", "%s: %v", name, err)
+}
+
+func fileOpResult(op, path string, err error) FileOpResult {
+	if err != nil {
+		return FileOpResult{Path: path, Error: fileOpError(op, path, err)}
+	}
+	return FileOpResult{Path: path}
+}
+
+// checkLocal rejects remote paths, which only support reading and saving
+func checkLocal(op string, paths ...string) error {
+	for _, p := range paths {
+		if isRemotePath(p) {
+			return newFileOpError(op, p, "unsupported", "Für Remote-Pfade nicht verfügbar")
+		}
+	}
+	return nil
+}
+
+// checkFree fails with "exists" if something is at path
+func checkFree(op, path string) error {
+	if _, err := os.Lstat(path); err == nil {
+		return fileOpError(op, path, os.ErrExist)
+	}
+	return nil
+}
+
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// isWithin reports whether path is dir or below it
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// movePath renames src to dst, copying across file systems
+func movePath(src, dst string) error {
+	err := os.Rename(src, dst)
+	if errors.Is(err, syscall.EXDEV) {
+		if err = copyTree(src, dst); err == nil {
+			err = os.RemoveAll(src)
+		}
+	}
+	return err
+}
+
+// pathRenamed moves recent files below from to their new place and tells the
+// frontend via "path-renamed", so open tabs follow
+func (a *App) pathRenamed(from, to string) {
+	changed := false
+	for i, p := range a.Config.RecentFiles {
+		if p == from || isWithin(p, from) {
+			rel, _ := filepath.Rel(from, p)
+			a.Config.RecentFiles[i] = filepath.Join(to, rel)
+			changed = true
+		}
+	}
+	if changed {
+		a.saveConfig()
+	}
+	a.passphrases.move(from, to)
+	a.charsets.move(from, to)
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "path-renamed", map[string]string{"from": from, "to": to})
+	}
+}
+
+// CreateFile creates an empty file; missing parent folders are created
+func (a *App) CreateFile(path string) FileOpResult {
+	if err := checkLocal("create", path); err != nil {
+		return fileOpResult("create", path, err)
+	}
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err == nil {
+		var f *os.File
+		if f, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644); err == nil {
+			err = f.Close()
+		}
+	}
+	return fileOpResult("create", path, err)
+}
+
+// CreateFolder creates a folder; missing parent folders are created
+func (a *App) CreateFolder(path string) FileOpResult {
+	err := checkLocal("mkdir", path)
+	if err == nil {
+		if err = checkFree("mkdir", path); err == nil {
+			err = os.MkdirAll(path, 0755)
+		}
+	}
+	return fileOpResult("mkdir", path, err)
+}
+
+// RenamePath gives a file or folder a new name in the same folder
+func (a *App) RenamePath(path, newName string) FileOpResult {
+	if err := checkLocal("rename", path); err != nil {
+		return fileOpResult("rename", path, err)
+	}
+	if !validName(newName) {
+		return FileOpResult{Path: path, Error: newFileOpError("rename", path, "invalid", "Ungültiger Name: %s", newName)}
+	}
+	target := filepath.Join(filepath.Dir(path), newName)
+	if target == path {
+		return FileOpResult{Path: path}
+	}
+	// Only the case changes on case-insensitive file systems
+	if !strings.EqualFold(target, path) {
+		if err := checkFree("rename", target); err != nil {
+			return fileOpResult("rename", target, err)
+		}
+	}
+	if err := os.Rename(path, target); err != nil {
+		return fileOpResult("rename", path, err)
+	}
+	a.pathRenamed(path, target)
+	return FileOpResult{Path: target}
+}
+
+// MovePath moves a file or folder into destDir
+func (a *App) MovePath(path, destDir string) FileOpResult {
+	if err := checkLocal("move", path, destDir); err != nil {
+		return fileOpResult("move", path, err)
+	}
+	target := filepath.Join(destDir, filepath.Base(path))
+	if target == path {
+		return FileOpResult{Path: path}
+	}
+	if isWithin(destDir, path) {
+		return FileOpResult{Path: path, Error: newFileOpError("move", path, "invalid", "%s kann nicht in sich selbst verschoben werden", filepath.Base(path))}
+	}
+	if err := checkFree("move", target); err != nil {
+		return fileOpResult("move", target, err)
+	}
+	if err := movePath(path, target); err != nil {
+		return fileOpResult("move", path, err)
+	}
+	a.pathRenamed(path, target)
+	return FileOpResult{Path: target}
+}
+
+// CopyPath copies a file or folder (recursively) into destDir
+func (a *App) CopyPath(path, destDir string) FileOpResult {
+	if err := checkLocal("copy", path, destDir); err != nil {
+		return fileOpResult("copy", path, err)
+	}
+	target := filepath.Join(destDir, filepath.Base(path))
+	if isWithin(destDir, path) {
+		return FileOpResult{Path: path, Error: newFileOpError("copy", path, "invalid", "%s kann nicht in sich selbst kopiert werden", filepath.Base(path))}
+	}
+	if err := checkFree("copy", target); err != nil {
+		return fileOpResult("copy", target, err)
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return fileOpResult("copy", path, err)
+	}
+	return fileOpResult("copy", target, copyTree(path, target))
+}
+
+// DuplicatePath copies a file or folder next to itself as "name Kopie.ext",
+// "name Kopie 2.ext", ...
+func (a *App) DuplicatePath(path string) FileOpResult {
+	if err := checkLocal("duplicate", path); err != nil {
+		return fileOpResult("duplicate", path, err)
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return fileOpResult("duplicate", path, err)
+	}
+	base := filepath.Base(path)
+	ext := ""
+	if !info.IsDir() {
+		ext = filepath.Ext(base)
+	}
+	stem := strings.TrimSuffix(base, ext)
+	target := filepath.Join(filepath.Dir(path), stem+" Kopie"+ext)
+	for n := 2; ; n++ {
+		if _, err := os.Lstat(target); os.IsNotExist(err) {
+			break
+		}
+		target = filepath.Join(filepath.Dir(path), fmt.Sprintf("%s Kopie %d%s", stem, n, ext))
+	}
+	return fileOpResult("duplicate", target, copyTree(path, target))
+}
+
+// SetExecutable sets or clears the executable bits; they are added where
+// the file is readable
+func (a *App) SetExecutable(path string, executable bool) FileOpResult {
+	if err := checkLocal("chmod", path); err != nil {
+		return fileOpResult("chmod", path, err)
+	}
+	if goruntime.GOOS == "windows" {
+		return FileOpResult{Path: path, Error: newFileOpError("chmod", path, "unsupported", "Unter Windows nicht verfügbar")}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileOpResult("chmod", path, err)
+	}
+	mode := info.Mode().Perm()
+	if executable {
+		mode |= (mode & 0444) >> 2
+	} else {
+		mode &^= 0111
+	}
+	return fileOpResult("chmod", path, os.Chmod(path, mode))
+}
+
+// trashDir is the home trash of the freedesktop.org trash specification
+func trashDir() (string, error) {
+	if goruntime.GOOS == "windows" || goruntime.GOOS == "darwin" {
+		return "", newFileOpError("trash", "", "unsupported", "Papierkorb wird auf diesem System nicht unterstützt")
+	}
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		data = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(data, "Trash"), nil
+}
+
+// TrashPath moves a file or folder to the trash and returns its trash id.
+// Files on other file systems are copied into the home trash.
+func (a *App) TrashPath(path string) FileOpResult {
+	if err := checkLocal("trash", path); err != nil {
+		return fileOpResult("trash", path, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fileOpResult("trash", path, err)
+	}
+	if _, err := os.Lstat(abs); err != nil {
+		return fileOpResult("trash", path, err)
+	}
+	trash, err := trashDir()
+	if err == nil {
+		err = os.MkdirAll(filepath.Join(trash, "files"), 0700)
+	}
+	if err == nil {
+		err = os.MkdirAll(filepath.Join(trash, "info"), 0700)
+	}
+	if err != nil {
+		return fileOpResult("trash", path, err)
+	}
+
+	// The info file is created exclusively first to reserve the name
+	base := filepath.Base(abs)
+	ext := filepath.Ext(base)
+	id := base
+	var info *os.File
+	for n := 2; ; n++ {
+		info, err = os.OpenFile(filepath.Join(trash, "info", id+".trashinfo"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if !errors.Is(err, os.ErrExist) {
+			break
+		}
+		id = fmt.Sprintf("%s.%d%s", strings.TrimSuffix(base, ext), n, ext)
+	}
+	if err != nil {
+		return fileOpResult("trash", path, err)
+	}
+	_, err = fmt.Fprintf(info, "[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: filepath.ToSlash(abs)}).EscapedPath(), time.Now().Format("2006-01-02T15:04:05"))
+	if cerr := info.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = movePath(abs, filepath.Join(trash, "files", id))
+	}
+	if err != nil {
+		os.Remove(filepath.Join(trash, "info", id+".trashinfo"))
+		return fileOpResult("trash", path, err)
+	}
+	return FileOpResult{Path: id}
+}
+
+// readTrashInfo returns the original path and deletion time of a trash entry
+func readTrashInfo(file string) (string, time.Time, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer f.Close()
+	var orig string
+	var deleted time.Time
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Path":
+			if orig, err = url.PathUnescape(value); err != nil {
+				return "", time.Time{}, err
+			}
+			orig = filepath.FromSlash(orig)
+		case "DeletionDate":
+			deleted, _ = time.ParseInLocation("2006-01-02T15:04:05", value, time.Local)
+		}
+	}
+	if orig == "" {
+		return "", time.Time{}, fmt.Errorf("Ungültige Papierkorb-Info: %s", filepath.Base(file))
+	}
+	return orig, deleted, scanner.Err()
+}
+
+// ListTrash lists the trash, most recently deleted first
+func (a *App) ListTrash() ([]TrashItem, error) {
+	items := []TrashItem{}
+	trash, err := trashDir()
+	if err != nil {
+		return items, err
+	}
+	infos, err := filepath.Glob(filepath.Join(trash, "info", "*.trashinfo"))
+	if err != nil {
+		return items, err
+	}
+	for _, file := range infos {
+		id := strings.TrimSuffix(filepath.Base(file), ".trashinfo")
+		fi, err := os.Lstat(filepath.Join(trash, "files", id))
+		if err != nil {
+			continue
+		}
+		orig, deleted, err := readTrashInfo(file)
+		if err != nil {
+			continue
+		}
+		items = append(items, TrashItem{
+			ID:           id,
+			Name:         filepath.Base(orig),
+			OriginalPath: orig,
+			DeletedAt:    deleted.UnixMilli(),
+			IsDir:        fi.IsDir(),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].DeletedAt > items[j].DeletedAt })
+	return items, nil
+}
+
+// RestoreTrash moves a trash entry back to its original path
+func (a *App) RestoreTrash(id string) FileOpResult {
+	trash, err := trashDir()
+	if err != nil {
+		return fileOpResult("restore", id, err)
+	}
+	if !validName(id) {
+		return FileOpResult{Path: id, Error: newFileOpError("restore", id, "invalid", "Ungültiger Eintrag: %s", id)}
+	}
+	infoFile := filepath.Join(trash, "info", id+".trashinfo")
+	orig, _, err := readTrashInfo(infoFile)
+	if err != nil {
+		return fileOpResult("restore", id, err)
+	}
+	if err := checkFree("restore", orig); err != nil {
+		return fileOpResult("restore", orig, err)
+	}
+	err = os.MkdirAll(filepath.Dir(orig), 0755)
+	if err == nil {
+		err = movePath(filepath.Join(trash, "files", id), orig)
+	}
+	if err != nil {
+		return fileOpResult("restore", orig, err)
+	}
+	os.Remove(infoFile)
+	return FileOpResult{Path: orig}
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// FormatterConfig describes an external formatter that reads the buffer on
+// stdin and writes the formatted result to stdout. "${file}" in Args is
+// replaced with the path of the buffer.
+type FormatterConfig struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+}
+
+// FormatError is a formatter message with an optional position (1-based, 0 = unknown)
+type FormatError struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+type FormatResult struct {
+	Content string        `json:"content"`
+	Changed bool          `json:"changed"`
+	Errors  []FormatError `json:"errors"`
+}
+
+// Built-in formatters per file extension; entries in Settings.Formatters take precedence
+var defaultFormatters = map[string]FormatterConfig{
+	".py":   {Command: "black", Args: []string{"-q", "-"}},
+	".rs":   {Command: "rustfmt", Args: []string{"--emit", "stdout"}},
+	".js":   {Command: "prettier", Args: []string{"--stdin-filepath", "${file}"}},
+	".mjs":  {Command: "prettier", Args: []string{"--stdin-filepath", "${file}"}},
+	".jsx":  {Command: "prettier", Args: []string{"--stdin-filepath", "${file}"}},
+	".ts":   {Command: "prettier", Args: []string{"--stdin-filepath", "${file}"}},
+	".tsx":  {Command: "prettier", Args: []string{"--stdin-filepath", "${file}"}},
+	".css":  {Command: "prettier", Args: []string{"--stdin-filepath", "${file}"}},
+	".html": {Command: "prettier", Args: []string{"--stdin-filepath", "${file}"}},
+	".json": {Command: "prettier", Args: []string{"--stdin-filepath", "${file}"}},
+}
+
+const formatterTimeout = 20 * time.Second
+
+// formatterFor returns the external formatter configured for path; the
+// workspace settings win over the global configuration
+func (a *App) formatterFor(path string) (FormatterConfig, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ws, _, ok := a.workspaceFor(path); ok {
+		if f, ok := ws.Settings.Formatters[ext]; ok {
+			return f, f.Command != ""
+		}
+	}
+	if f, ok := a.prefs().Formatters[ext]; ok {
+		return f, f.Command != ""
+	}
+	f, ok := defaultFormatters[ext]
+	return f, ok
+}
+
+// formatSource formats content according to the file type of path
+func (a *App) formatSource(path string, content string) FormatResult {
+	var formatted string
+	var errs []FormatError
+
+	if strings.EqualFold(filepath.Ext(path), ".go") {
+		formatted, errs = formatGo(path, content)
+	} else if f, ok := a.formatterFor(path); ok {
+		formatted, errs = runExternalFormatter(f, path, content)
+	} else {
+		errs = []FormatError{{Message: fmt.Sprintf("Kein Formatierer für %s konfiguriert", filepath.Base(path))}}
+	}
+
+	if len(errs) > 0 {
+		return FormatResult{Content: content, Errors: errs}
+	}
+	return FormatResult{Content: formatted, Changed: formatted != content, Errors: []FormatError{}}
+}
+
+// formatGo runs gofmt and groups imports like goimports does:
+// standard library first, then everything else, each group sorted.
+func formatGo(path string, content string) (string, []FormatError) {
+	src, err := format.Source([]byte(content))
+	if err != nil {
+		return "", goFormatErrors(err)
+	}
+	if grouped, ok := groupGoImports(path, src); ok {
+		if src2, err := format.Source(grouped); err == nil {
+			src = src2
+		}
+	}
+	return string(src), nil
+}
+
+func goFormatErrors(err error) []FormatError {
+	var list scanner.ErrorList
+	if errors.As(err, &list) {
+		result := make([]FormatError, 0, len(list))
+		for _, e := range list {
+			result = append(result, FormatError{Line: e.Pos.Line, Column: e.Pos.Column, Message: e.Msg})
+		}
+		return result
+	}
+	return []FormatError{{Message: err.Error()}}
+}
+
+// groupGoImports rewrites parenthesized import blocks into a standard library
+// group and a third-party group. Blocks with free-standing comments are left alone.
+func groupGoImports(path string, src []byte) ([]byte, bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, false
+	}
+	off := func(p token.Pos) int { return fset.Position(p).Offset }
+
+	type block struct {
+		start, end int
+		text       []byte
+	}
+	var blocks []block
+
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT || !gd.Lparen.IsValid() || len(gd.Specs) < 2 {
+			continue
+		}
+
+		// Comments inside the block must all belong to a spec
+		owned := map[*ast.CommentGroup]bool{}
+		for _, s := range gd.Specs {
+			is := s.(*ast.ImportSpec)
+			owned[is.Doc] = true
+			owned[is.Comment] = true
+		}
+		floating := false
+		for _, cg := range file.Comments {
+			if cg.Pos() > gd.Lparen && cg.End() < gd.Rparen && !owned[cg] {
+				floating = true
+			}
+		}
+		if floating {
+			continue
+		}
+
+		type entry struct {
+			path string
+			text string
+		}
+		var std, other []entry
+		for _, s := range gd.Specs {
+			is := s.(*ast.ImportSpec)
+			start := off(is.Pos())
+			if is.Doc != nil {
+				start = off(is.Doc.Pos())
+			}
+			end := off(is.End())
+			if is.Comment != nil {
+				end = off(is.Comment.End())
+			}
+			p, _ := strconv.Unquote(is.Path.Value)
+			e := entry{path: p, text: string(src[start:end])}
+			if first, _, _ := strings.Cut(p, "/"); strings.Contains(first, ".") {
+				other = append(other, e)
+			} else {
+				std = append(std, e)
+			}
+		}
+
+		var buf bytes.Buffer
+		buf.WriteString("import (\n")
+		for gi, group := range [][]entry{std, other} {
+			if len(group) == 0 {
+				continue
+			}
+			if gi == 1 && len(std) > 0 {
+				buf.WriteString("\n")
+			}
+			sort.SliceStable(group, func(i, j int) bool { return group[i].path < group[j].path })
+			for _, e := range group {
+				buf.WriteString("\t" + e.text + "\n")
+			}
+		}
+		buf.WriteString(")")
+		blocks = append(blocks, block{start: off(gd.Pos()), end: off(gd.End()), text: buf.Bytes()})
+	}
+
+	if len(blocks) == 0 {
+		return nil, false
+	}
+	var out bytes.Buffer
+	last := 0
+	for _, b := range blocks {
+		out.Write(src[last:b.start])
+		out.Write(b.text)
+		last = b.end
+	}
+	out.Write(src[last:])
+	return out.Bytes(), true
+}
+
+// Positions in formatter messages: "3:14", "line 3, column 14", "(3:14)"
+var (
+	formatPosRe     = regexp.MustCompile(`(\d+):(\d+)`)
+	formatLineColRe = regexp.MustCompile(`line (\d+),? col(?:umn)? (\d+)`)
+)
+
+func runExternalFormatter(f FormatterConfig, path string, content string) (string, []FormatError) {
+	if _, err := exec.LookPath(f.Command); err != nil {
+		return "", []FormatError{{Message: fmt.Sprintf("Formatierer %s nicht gefunden", f.Command)}}
+	}
+
+	args := make([]string, len(f.Args))
+	for i, arg := range f.Args {
+		args[i] = strings.ReplaceAll(arg, "${file}", path)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), formatterTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, f.Command, args...)
+	if !isRemotePath(path) {
+		cmd.Dir = filepath.Dir(path)
+	}
+	cmd.Stdin = strings.NewReader(content)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", parseFormatterErrors(f.Command, msg)
+	}
+	return stdout.String(), nil
+}
+
+func parseFormatterErrors(command string, output string) []FormatError {
+	var result []FormatError
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fe := FormatError{Message: command + ": " + line}
+		m := formatLineColRe.FindStringSubmatch(line)
+		if m == nil {
+			m = formatPosRe.FindStringSubmatch(line)
+		}
+		if m != nil {
+			fe.Line, _ = strconv.Atoi(m[1])
+			fe.Column, _ = strconv.Atoi(m[2])
+		}
+		result = append(result, fe)
+		if len(result) >= 20 {
+			break
+		}
+	}
+	return result
+}
+
+// formatBeforeSave applies format-on-save and tells the frontend about
+// formatter errors. On errors the original content is saved unchanged.
+func (a *App) formatBeforeSave(path string, content string) string {
+	if !a.prefs().FormatOnSave {
+		return content
+	}
+	if _, ok := a.formatterFor(path); !ok && !strings.EqualFold(filepath.Ext(path), ".go") {
+		return content
+	}
+
+	res := a.formatSource(path, content)
+	if len(res.Errors) > 0 {
+		if a.ctx != nil {
+			runtime.EventsEmit(a.ctx, "format-errors", map[string]interface{}{
+				"path":   path,
+				"errors": res.Errors,
+			})
+		}
+		return content
+	}
+	return res.Content
+}
+
+// bufferSaved sends the saved content back to the frontend as
+// "buffer-formatted" if saving changed the buffer
+func (a *App) bufferSaved(path string, content string, saved string) {
+	if saved != content && a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "buffer-formatted", map[string]interface{}{
+			"path":    path,
+			"content": saved,
+		})
+	}
+}
+
+// FormatBuffer formats the given buffer content without saving it
+func (a *App) FormatBuffer(path string, content string) FormatResult {
+	return a.formatSource(path, content)
+}
+
+// GetFormatOnSave reports whether buffers are formatted when saved
+func (a *App) GetFormatOnSave() bool {
+	return a.prefs().FormatOnSave
+}
+
+// SetFormatOnSave enables or disables formatting on save
+func (a *App) SetFormatOnSave(enabled bool) string {
+	if err := a.setSetting("format_on_save", enabled); err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	if enabled {
+		return "Formatieren beim Speichern aktiviert"
+	}
+	return "Formatieren beim Speichern deaktiviert"
+}
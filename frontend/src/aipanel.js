@@ -1,7 +1,7 @@
 import { LoadHTMLFile } from '../wailsjs/go/main/App.js';
 import { EventsOn, EventsOff } from "../wailsjs/runtime/runtime.js";
 import { Logger } from './logger.js';
-import { QueryOpenRouter, Ping } from '../wailsjs/go/main/App.js';
+import { QueryOpenRouter, Ping, GetWorkspace } from '../wailsjs/go/main/App.js';
 import { marked } from 'marked';
 import { markedHighlight } from "marked-highlight";
 import hljs from 'highlight.js';
@@ -43,6 +43,8 @@ Fähigkeiten: „Erstelle mir eine Liste deiner Kernkompetenzen. Kannst du Bilde
         this.cacheDOMElements();
         this.setupEventListeners();
         this.setDefaultModel();
+        this.applyWorkspaceModel(await GetWorkspace());
+        this.offWorkspaceChanged = EventsOn('workspace-changed', (ws) => this.applyWorkspaceModel(ws));
         this.updateStatusDot();
         setInterval(this.updateStatusDot, 30000);
     }
@@ -457,11 +459,24 @@ Fähigkeiten: „Erstelle mir eine Liste deiner Kernkompetenzen. Kannst du Bilde
     }
 
     destroy() {
+        this.offWorkspaceChanged?.();
         if (this.panel && this.panel.parentNode) {
             this.panel.parentNode.removeChild(this.panel);
         }
     }
 
+    // Modell aus den Workspace-Einstellungen (settings.ai.model) übernehmen
+    applyWorkspaceModel(ws) {
+        const model = ws?.settings?.ai?.model;
+        const selector = this.elements['ai-model-selector'];
+        if (!model || !selector) return;
+        if (!selector.querySelector(`option[value="${model}"]`)) {
+            selector.appendChild(new Option(model, model));
+        }
+        selector.value = model;
+        this.onModelChange(model);
+    }
+
    setDefaultModel() {
         const selector = this.elements['ai-model-selector'];
         const option = document.querySelector(`#ai-model-selector option[value="${selector.value}"]`);
@@ -0,0 +1,301 @@
+import { renderIcon } from './lib/icons.js';
+import { Logger } from './logger.js';
+import { EventsOn } from "../wailsjs/runtime/runtime.js";
+import {
+    DebugStart, DebugStop, DebugContinue, DebugNext, DebugStepIn, DebugStepOut, DebugPause,
+    DebugThreads, DebugStackTrace, DebugScopes, DebugVariables, DebugEvaluate
+} from '../wailsjs/go/main/App.js';
+import { appState } from './state.js';
+import { editorManager } from './editor.js';
+import { updateStatus, SidepanelCloser } from './ui.js';
+import { debugStore } from './debugger.js';
+import { openFileAtPosition } from './navigation.js';
+import { saveFile } from './fileOperations.js';
+
+// Go debugger (Delve): controls, goroutines, call stack and variables
+export class DebugPanel {
+    constructor(parentSelector = '.leftToolbarContainer') {
+        this.logger = new Logger("DebugPanel");
+        this.parent = document.querySelector(parentSelector);
+        if (!this.parent) {
+            this.logger.error(`Container "${parentSelector}" not found.`);
+            return;
+        }
+        this.threadId = null;
+        this.frameId = null;
+
+        this.createPanel();
+        this.updateControls();
+        debugStore.onChange((kind) => this.onDebugChange(kind));
+        EventsOn('debug-output', ({ output }) => this.appendOutput(output));
+    }
+
+    createPanel() {
+        const panel = document.createElement('div');
+        panel.id = 'debug-panel';
+        panel.className = 'recent-files-panel hidden';
+
+        const header = document.createElement('div');
+        header.className = 'recent-header';
+        header.innerHTML = `<h3>Debugger</h3>`;
+
+        const start = document.createElement('div');
+        start.className = 'test-toolbar debug-start';
+        start.innerHTML = `
+            <button data-mode="debug" title="Paket der aktuellen Datei debuggen">Paket</button>
+            <button data-mode="test" title="Tests des Pakets debuggen">Tests</button>
+            <button data-mode="test-at" title="Test unter dem Cursor debuggen">Test</button>
+        `;
+        start.querySelectorAll('button').forEach(btn => {
+            btn.addEventListener('click', () => this.start(btn.dataset.mode));
+        });
+
+        const controls = document.createElement('div');
+        controls.className = 'debug-controls';
+        const actions = [
+            ['Play', 'Fortsetzen (F8)', () => DebugContinue()],
+            ['Pause', 'Anhalten', () => DebugPause()],
+            ['Redo', 'Prozedurschritt (F10)', () => DebugNext()],
+            ['ArrowDownToLine', 'Einzelschritt (F11)', () => DebugStepIn()],
+            ['ArrowUpFromLine', 'Rücksprung (Shift+F11)', () => DebugStepOut()],
+            ['SquareX', 'Beenden', () => DebugStop()]
+        ];
+        this.buttons = actions.map(([icon, title, action]) => {
+            const btn = document.createElement('button');
+            btn.className = 'debug-btn';
+            btn.title = title;
+            btn.appendChild(renderIcon(icon, { width: 14, height: 14 }));
+            btn.addEventListener('click', () => this.command(action));
+            controls.appendChild(btn);
+            return btn;
+        });
+
+        const content = document.createElement('div');
+        content.className = 'recent-files-list debug-content';
+        content.innerHTML = `
+            <div class="debug-section-title">Goroutinen</div>
+            <div class="debug-threads"></div>
+            <div class="debug-section-title">Aufrufliste</div>
+            <div class="debug-stack"></div>
+            <div class="debug-section-title">Variablen</div>
+            <input class="debug-eval" type="text" placeholder="Ausdruck auswerten…">
+            <div class="debug-variables"></div>
+            <div class="debug-section-title">Ausgabe</div>
+            <pre class="debug-output"></pre>
+        `;
+
+        panel.append(header, start, controls, content);
+        this.parent.appendChild(panel);
+        this.panel = panel;
+        this.status = header.querySelector('h3');
+        this.threadsEl = content.querySelector('.debug-threads');
+        this.stackEl = content.querySelector('.debug-stack');
+        this.variablesEl = content.querySelector('.debug-variables');
+        this.outputEl = content.querySelector('.debug-output');
+
+        content.querySelector('.debug-eval').addEventListener('keydown', (e) => {
+            if (e.key === 'Enter' && e.target.value.trim()) this.evaluate(e.target.value.trim());
+        });
+    }
+
+    async start(mode) {
+        const tab = appState.getActiveTab();
+        if (!tab?.filePath || !tab.filePath.endsWith('.go')) {
+            updateStatus("Bitte eine Go-Datei öffnen", "error");
+            return;
+        }
+        const path = tab.filePath;
+        if (tab.dirty && !(await saveFile())) return;
+
+        let line = -1;
+        if (mode === 'test-at') {
+            const view = editorManager.getActiveView();
+            line = view ? view.state.doc.lineAt(view.state.selection.main.head).number - 1 : 0;
+        }
+
+        this.outputEl.textContent = '';
+        this.status.textContent = 'Debugger – startet …';
+        try {
+            await DebugStart(path, mode === 'debug' ? 'debug' : 'test', line);
+        } catch (err) {
+            this.status.textContent = 'Debugger';
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    async command(action) {
+        try {
+            await action();
+        } catch (err) {
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    updateControls() {
+        const { active, stopped } = debugStore;
+        // Fortsetzen und Schritte nur im Halt, Anhalten nur während das Programm läuft
+        const enabled = [stopped, active && !stopped, stopped, stopped, stopped, active];
+        this.buttons.forEach((btn, i) => { btn.disabled = !enabled[i]; });
+    }
+
+    async onDebugChange(kind) {
+        this.updateControls();
+        switch (kind) {
+            case 'started':
+                this.status.textContent = 'Debugger – läuft';
+                break;
+            case 'continued':
+                this.status.textContent = 'Debugger – läuft';
+                this.stackEl.innerHTML = '';
+                this.variablesEl.innerHTML = '';
+                break;
+            case 'terminated':
+                this.status.textContent = 'Debugger';
+                this.threadsEl.innerHTML = '';
+                this.stackEl.innerHTML = '';
+                this.variablesEl.innerHTML = '';
+                break;
+            case 'stopped': {
+                const { reason, threadId, frames } = debugStore.stopped;
+                this.status.textContent = `Debugger – angehalten (${reason})`;
+                SidepanelCloser('Debugger');
+                this.panel.classList.remove('hidden');
+                this.threadId = threadId;
+                this.renderStack(frames || []);
+                this.loadThreads();
+                const loc = debugStore.location;
+                if (loc) await openFileAtPosition(loc.path, loc.line - 1, 0);
+                break;
+            }
+        }
+    }
+
+    async loadThreads() {
+        try {
+            const threads = await DebugThreads();
+            this.threadsEl.innerHTML = '';
+            threads.forEach(t => {
+                const row = document.createElement('div');
+                row.className = 'test-node debug-row' + (t.id === this.threadId ? ' debug-selected' : '');
+                row.textContent = t.name;
+                row.addEventListener('click', () => this.selectThread(t.id));
+                this.threadsEl.appendChild(row);
+            });
+        } catch (err) {
+            this.logger.error(`${err}`);
+        }
+    }
+
+    async selectThread(id) {
+        this.threadId = id;
+        try {
+            this.renderStack(await DebugStackTrace(id));
+            this.loadThreads();
+        } catch (err) {
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    renderStack(frames) {
+        this.stackEl.innerHTML = '';
+        frames.forEach((frame, i) => {
+            const row = document.createElement('div');
+            row.className = 'test-node debug-row';
+            const name = document.createElement('span');
+            name.className = 'test-name';
+            name.textContent = frame.name;
+            const loc = document.createElement('span');
+            loc.className = 'test-time';
+            loc.textContent = frame.path ? `${frame.path.split(/[/\\]/).pop()}:${frame.line}` : '';
+            row.title = frame.path ? `${frame.path}:${frame.line}` : '';
+            row.append(name, loc);
+            row.addEventListener('click', () => this.selectFrame(frame, row));
+            this.stackEl.appendChild(row);
+            if (i === 0) this.selectFrame(frame, row, false);
+        });
+    }
+
+    async selectFrame(frame, row, open = true) {
+        this.frameId = frame.id;
+        this.stackEl.querySelectorAll('.debug-selected').forEach(el => el.classList.remove('debug-selected'));
+        row.classList.add('debug-selected');
+        if (open && frame.path) openFileAtPosition(frame.path, frame.line - 1, 0);
+
+        this.variablesEl.innerHTML = '';
+        try {
+            const scopes = await DebugScopes(frame.id);
+            for (const scope of scopes) {
+                this.variablesEl.appendChild(this.renderVariable(
+                    { name: scope.name, value: '', type: '', variablesReference: scope.variablesReference }, 0, !scope.expensive));
+            }
+        } catch (err) {
+            this.logger.error(`${err}`);
+        }
+    }
+
+    // Ein Variablenknoten; Kinder werden erst beim Aufklappen geladen
+    renderVariable(variable, depth, expanded = false) {
+        const wrapper = document.createElement('div');
+        const row = document.createElement('div');
+        row.className = 'test-node debug-variable';
+        row.style.paddingLeft = `${depth * 14 + 4}px`;
+
+        const toggle = document.createElement('span');
+        toggle.className = 'test-status';
+        toggle.textContent = variable.variablesReference > 0 ? '▸' : '';
+        const name = document.createElement('span');
+        name.className = 'debug-var-name';
+        name.textContent = variable.name;
+        const value = document.createElement('span');
+        value.className = 'test-name debug-var-value';
+        value.textContent = variable.value;
+        row.title = variable.type ? `${variable.type} = ${variable.value}` : variable.value;
+        row.append(toggle, name, value);
+        wrapper.appendChild(row);
+
+        if (variable.variablesReference > 0) {
+            const children = document.createElement('div');
+            children.className = 'hidden';
+            wrapper.appendChild(children);
+            let loaded = false;
+            const expand = async () => {
+                const open = children.classList.toggle('hidden') === false;
+                toggle.textContent = open ? '▾' : '▸';
+                if (!open || loaded) return;
+                loaded = true;
+                try {
+                    const vars = await DebugVariables(variable.variablesReference);
+                    vars.forEach(v => children.appendChild(this.renderVariable(v, depth + 1)));
+                } catch (err) {
+                    this.logger.error(`${err}`);
+                }
+            };
+            row.addEventListener('click', expand);
+            if (expanded) expand();
+        }
+        return wrapper;
+    }
+
+    async evaluate(expression) {
+        if (!debugStore.stopped) {
+            updateStatus("Das Programm ist nicht angehalten", "error");
+            return;
+        }
+        try {
+            const result = await DebugEvaluate(expression, this.frameId || 0);
+            this.variablesEl.prepend(this.renderVariable(result, 0));
+        } catch (err) {
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    appendOutput(text) {
+        if (!this.outputEl) return;
+        this.outputEl.textContent += text;
+        // Nur das Ende behalten
+        if (this.outputEl.textContent.length > 50000) {
+            this.outputEl.textContent = this.outputEl.textContent.slice(-40000);
+        }
+        this.outputEl.scrollTop = this.outputEl.scrollHeight;
+    }
+}
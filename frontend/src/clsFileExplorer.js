@@ -2,7 +2,8 @@
 
 import { renderIcon } from './lib/icons.js';
 import {
-    ListDir,
+    ListDirStream,
+    CancelListDir,
     ReadFileContent,
     HomeDir,
     AddRecentFile,
@@ -12,20 +13,34 @@ import {
     ExtractFilePaths,
     ClearRecentFiles,
     RemoveRecentFile,
-    OpenFileDialog
+    OpenFileDialog,
+    GetWorkspace,
+    OpenWorkspace,
+    GetRecentWorkspaces,
+    RemoveRecentWorkspace,
+    RemoveWorkspaceFolder,
+    RenamePath,
+    DuplicatePath,
+    TrashPath
 } from "../wailsjs/go/main/App.js";
 import * as runtime from "../wailsjs/runtime";
+import { settingsStore } from './settings.js';
 
 export class FileExplorer {
     constructor(containerId, onSelect, onOpenFolder) {
         this.container = document.getElementById(containerId);
         this.onSelect = onSelect; // called when file is double-clicked
         this.onOpenFolder = onOpenFolder; // optional: for breadcrumbs or status
+        this.onOpenLog = null; // optional: opens a file in the log view
         this.currentPath = ''; // current root path being displayed
         this.startDir = '';
         this.selectedFile = null; // Track selected file
         this.recentFiles = [];
         this.recentFilesContainer = null; // For recent files panel
+        this.workspace = null; // { file, name, folders[], settings }
+        this.recentWorkspaces = [];
+        this.recentWorkspacesContainer = null;
+        this.listing = null; // { id, path, entries, resolve } of the directory being read
     }
 
     async init() {
@@ -40,24 +55,93 @@ export class FileExplorer {
             console.log('No last directory saved, using home directory');
         }
 
+        // An open workspace starts at its first folder
+        this.workspace = await GetWorkspace();
+        if (this.workspace.folders.length) {
+            this.startDir = this.workspace.folders[0].path;
+        }
+        runtime.EventsOn('explorer-open', (path) => this.loadDirectory(path));
+        runtime.EventsOn('listdir-page', (page) => this.onListPage(page));
+        let options = JSON.stringify(this.listOptions());
+        settingsStore.onChange(() => {
+            const changed = JSON.stringify(this.listOptions());
+            if (changed !== options) {
+                options = changed;
+                this.refresh();
+            }
+        });
+        runtime.EventsOn('workspace-changed', async (ws) => {
+            this.workspace = ws;
+            await this.loadRecentWorkspaces();
+            this.updateRecentWorkspacesPanel();
+            if (ws.folders.length) await this.loadDirectory(ws.folders[0].path);
+            else this.refresh();
+        });
+
         console.log('Starting directory:', this.startDir);
         await this.loadDirectory(this.startDir);
         await this.loadRecentFiles();
+        await this.loadRecentWorkspaces();
         this.createRecentFilesPanel();
     }
 
-    async loadDirectory(path) {
-        try {
-            this.currentPath = path;
-            const entries = await ListDir(path);
-            this.render(entries, path);
-            if (this.onOpenFolder) this.onOpenFolder(path);
-        } catch (err) {
-            console.error('Failed to load directory:', err);
-            // Consider showing error in UI instead of alert
-            this.showError(`Cannot open folder: ${err.message || err}`);
-            // Optionally revert to previous directory
+    listOptions() {
+        const explorer = settingsStore.settings?.explorer;
+        return {
+            showHidden: explorer?.show_hidden ?? true,
+            hideIgnored: explorer?.hide_ignored ?? false
+        };
+    }
+
+    // Reads the directory in pages (see onListPage); resolves when the last page arrived
+    loadDirectory(path) {
+        if (this.listing) {
+            CancelListDir(this.listing.id);
+            this.listing.resolve();
         }
+        return new Promise(async (resolve) => {
+            // Pages can arrive before ListDirStream has returned the id; they wait in early
+            const listing = { id: null, path, entries: [], early: [], resolve };
+            this.listing = listing;
+            try {
+                listing.id = await ListDirStream(path, this.listOptions(), 500);
+            } catch (err) {
+                this.listFailed(listing, err);
+                return;
+            }
+            for (const page of listing.early.splice(0)) {
+                if (page.id === listing.id) this.onListPage(page);
+            }
+        });
+    }
+
+    onListPage(page) {
+        const listing = this.listing;
+        if (listing && listing.id === null) {
+            listing.early.push(page);
+            return;
+        }
+        if (!listing || page.id !== listing.id) return;
+        if (page.error && listing.entries.length === 0) {
+            this.listFailed(listing, page.error);
+            return;
+        }
+        this.currentPath = listing.path;
+        listing.entries.push(...page.entries);
+        this.render(listing.entries, listing.path);
+        if (!listing.shown && this.onOpenFolder) this.onOpenFolder(listing.path);
+        listing.shown = true;
+        if (page.done) {
+            this.listing = null;
+            listing.resolve();
+        }
+    }
+
+    listFailed(listing, err) {
+        console.error('Failed to load directory:', err);
+        this.showError(`Cannot open folder: ${err.message || err}`);
+        if (this.listing === listing) this.listing = null;
+        listing.resolve();
     }
 
 getFileIcon(entry) {
@@ -165,8 +249,12 @@ getFileIcon(entry) {
         this.container.innerHTML = '';
         this.selectedFile = null; // Clear selection when directory changes
 
-        // Optional: Up button
-        if (basePath !== '/') {
+        if (this.workspace?.folders.length) {
+            this.container.appendChild(this.createWorkspaceBar(basePath));
+        }
+
+        // Optional: Up button (not at the root of a remote host)
+        if (basePath !== '/' && !/^sftp:\/\/[^/]+$/.test(basePath)) {
             const upItem = this.createItem({ name: '..', isDir: true }, basePath);
             upItem.classList.add('up-item');
             upItem.addEventListener('click', () => {
@@ -183,7 +271,7 @@ getFileIcon(entry) {
         const sortedEntries = entries.sort((a, b) => {
             if (a.isDir && !b.isDir) return -1;
             if (!a.isDir && b.isDir) return 1;
-            return a.name.localeCompare(b.name);
+            return a.name.localeCompare(b.name, undefined, { numeric: true, sensitivity: 'base' });
         });
 
         sortedEntries.forEach(entry => {
@@ -192,6 +280,41 @@ getFileIcon(entry) {
         });
     }
 
+    // Name of the workspace and its root folders; the folder shown is highlighted
+    createWorkspaceBar(basePath) {
+        const bar = document.createElement('div');
+        bar.className = 'workspace-bar';
+
+        const title = document.createElement('div');
+        title.className = 'workspace-name';
+        title.textContent = this.workspace.name;
+        title.title = this.workspace.file || this.workspace.folders[0].path;
+        bar.appendChild(title);
+
+        if (this.workspace.folders.length > 1) {
+            this.workspace.folders.forEach(folder => {
+                const root = document.createElement('div');
+                root.className = 'workspace-root';
+                const inside = basePath === folder.path || basePath.startsWith(folder.path + '/') || basePath.startsWith(folder.path + '\\');
+                if (inside) root.classList.add('active');
+                root.textContent = folder.name;
+                root.title = folder.path;
+                root.addEventListener('click', () => this.loadDirectory(folder.path));
+                root.addEventListener('contextmenu', async (e) => {
+                    e.preventDefault();
+                    if (!confirm(`Ordner "${folder.name}" aus dem Workspace entfernen?`)) return;
+                    try {
+                        await RemoveWorkspaceFolder(folder.path);
+                    } catch (err) {
+                        this.showError(`${err}`);
+                    }
+                });
+                bar.appendChild(root);
+            });
+        }
+        return bar;
+    }
+
     createItem(entry, basePath) {
         const div = document.createElement('div');
         div.className = `file-item ${entry.isDir ? 'folder' : 'file'}`;
@@ -247,6 +370,12 @@ getFileIcon(entry) {
 
             if (entry.name === '..') return;
 
+            if (entry.archive) {
+                // zip- und tar-Archive wie Ordner durchsuchen
+                this.loadDirectory(`${fullPath}!`);
+                return;
+            }
+
             if (!entry.isDir && this.onSelect) {
                 // Add to recent files first
                 await this.addToRecentFiles(fullPath);
@@ -318,6 +447,23 @@ getFileIcon(entry) {
         }
     }
 
+    async loadRecentWorkspaces() {
+        try {
+            this.recentWorkspaces = await GetRecentWorkspaces() || [];
+        } catch (err) {
+            console.error('Failed to load recent workspaces:', err);
+            this.recentWorkspaces = [];
+        }
+    }
+
+    async openWorkspace(path) {
+        try {
+            await OpenWorkspace(path);
+        } catch (err) {
+            this.showError(`Cannot open workspace: ${err.message || err}`);
+        }
+    }
+
     // Create recent files panel
     createRecentFilesPanel() {
         const panel = document.createElement('div');
@@ -335,8 +481,17 @@ getFileIcon(entry) {
         list.id = 'recent-files-list';
         list.className = 'recent-files-list';
 
+        const workspacesHeader = document.createElement('div');
+        workspacesHeader.className = 'recent-header';
+        workspacesHeader.innerHTML = `<h3>Workspaces</h3>`;
+
+        const workspaces = document.createElement('div');
+        workspaces.className = 'recent-files-list recent-workspaces-list';
+
         panel.appendChild(header);
         panel.appendChild(list);
+        panel.appendChild(workspacesHeader);
+        panel.appendChild(workspaces);
 
         const icon = renderIcon("Trash2");
         header.appendChild(icon);
@@ -353,7 +508,50 @@ getFileIcon(entry) {
         });
 
         this.recentFilesContainer = list;
+        this.recentWorkspacesContainer = workspaces;
         this.updateRecentFilesPanel();
+        this.updateRecentWorkspacesPanel();
+    }
+
+    // Recent workspace files and folders; a click opens the workspace
+    updateRecentWorkspacesPanel() {
+        if (!this.recentWorkspacesContainer) return;
+        this.recentWorkspacesContainer.innerHTML = '';
+
+        if (this.recentWorkspaces.length === 0) {
+            const emptyMsg = document.createElement('div');
+            emptyMsg.className = 'recent-empty';
+            emptyMsg.textContent = 'Keine zuletzt verwendeten Workspaces';
+            this.recentWorkspacesContainer.appendChild(emptyMsg);
+            return;
+        }
+
+        this.recentWorkspaces.forEach(path => {
+            const item = document.createElement('div');
+            item.className = 'recent-item';
+            item.title = path;
+
+            const name = document.createElement('div');
+            name.className = 'recent-name';
+            // "<dir>/.leoedit-workspace" is named after its folder
+            name.textContent = this.getFilenameFromPath(path).replace(/\.?leoedit-workspace$/, '')
+                || this.getFilenameFromPath(this.getDirectoryFromPath(path));
+            const dir = document.createElement('div');
+            dir.className = 'recent-path';
+            dir.textContent = this.getDirectoryFromPath(path);
+            const remove = document.createElement('button');
+            remove.className = 'btn-remove-recent';
+            remove.textContent = '×';
+
+            item.append(name, dir, remove);
+            item.addEventListener('click', () => this.openWorkspace(path));
+            remove.addEventListener('click', async (e) => {
+                e.stopPropagation();
+                this.recentWorkspaces = await RemoveRecentWorkspace(path);
+                this.updateRecentWorkspacesPanel();
+            });
+            this.recentWorkspacesContainer.appendChild(item);
+        });
     }
 
     // Update recent files panel
@@ -466,10 +664,15 @@ getFileIcon(entry) {
 
         menu.innerHTML = `
             <div class="context-item" data-action="open">Öffnen</div>
+            ${this.onOpenLog ? '<div class="context-item" data-action="open-log">Als Log öffnen</div>' : ''}
             <div class="context-item" data-action="add-recent">Zu Zuletzt hinzufügen</div>
             <div class="context-item" data-action="show-in-folder">Im Ordner anzeigen</div>
             <hr>
             <div class="context-item" data-action="copy-path">Pfad kopieren</div>
+            <hr>
+            <div class="context-item" data-action="rename">Umbenennen…</div>
+            <div class="context-item" data-action="duplicate">Duplizieren</div>
+            <div class="context-item" data-action="trash">In den Papierkorb</div>
         `;
 
         document.body.appendChild(menu);
@@ -487,6 +690,10 @@ getFileIcon(entry) {
                     }
                     break;
 
+                case 'open-log':
+                    this.onOpenLog(filePath);
+                    break;
+
                 case 'add-recent':
                     await this.addToRecentFiles(filePath);
                     break;
@@ -506,6 +713,22 @@ getFileIcon(entry) {
                 case 'copy-path':
                     navigator.clipboard.writeText(filePath);
                     break;
+
+                case 'rename': {
+                    const name = prompt('Neuer Name', this.getFilenameFromPath(filePath));
+                    if (name) await this.fileOperation(RenamePath(filePath, name));
+                    break;
+                }
+
+                case 'duplicate':
+                    await this.fileOperation(DuplicatePath(filePath));
+                    break;
+
+                case 'trash':
+                    if (confirm(`"${this.getFilenameFromPath(filePath)}" in den Papierkorb verschieben?`)) {
+                        await this.fileOperation(TrashPath(filePath));
+                    }
+                    break;
             }
 
             menu.remove();
@@ -523,6 +746,17 @@ getFileIcon(entry) {
         }, 100);
     }
 
+    // Wartet auf eine Dateioperation, zeigt Fehler an und lädt den Ordner neu
+    async fileOperation(promise) {
+        const result = await promise;
+        if (result.error) {
+            this.showError(result.error.message);
+            return null;
+        }
+        await this.loadDirectory(this.currentPath);
+        return result.path;
+    }
+
     // Utility methods
     getFilenameFromPath(fullPath) {
         if (!fullPath) return '';
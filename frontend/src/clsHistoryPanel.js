@@ -0,0 +1,175 @@
+import { renderIcon } from './lib/icons.js';
+import { Logger } from './logger.js';
+import { EventsOn } from "../wailsjs/runtime/runtime.js";
+import { HistoryList, HistoryDiff, HistoryRestore } from '../wailsjs/go/main/App.js';
+import { appState } from './state.js';
+import { updateStatus } from './ui.js';
+
+// Local file history of the active file: revisions, diff and restore
+export class HistoryPanel {
+    constructor(parentSelector = '.leftToolbarContainer') {
+        this.logger = new Logger("HistoryPanel");
+        this.parent = document.querySelector(parentSelector);
+        if (!this.parent) {
+            this.logger.error(`Container "${parentSelector}" not found.`);
+            return;
+        }
+        this.path = null;
+        this.revisions = [];
+        this.selected = null;   // Ausgewählter Stand
+        this.compare = null;    // Mit Shift gewählter Vergleichsstand, sonst die Datei
+
+        this.createPanel();
+        EventsOn('history-updated', (path) => {
+            if (path === this.path && !this.panel.classList.contains('hidden')) this.refresh();
+        });
+    }
+
+    createPanel() {
+        const panel = document.createElement('div');
+        panel.id = 'history-panel';
+        panel.className = 'recent-files-panel hidden';
+
+        const header = document.createElement('div');
+        header.className = 'recent-header';
+        header.innerHTML = `<h3>Verlauf</h3>`;
+
+        const refresh = document.createElement('button');
+        refresh.className = 'btn-clear-recent btn-history-refresh';
+        refresh.title = 'Verlauf der aktiven Datei laden';
+        refresh.appendChild(renderIcon("RefreshCw", { width: 16, height: 16 }));
+        refresh.addEventListener('click', () => this.refresh());
+        header.appendChild(refresh);
+
+        const toolbar = document.createElement('div');
+        toolbar.className = 'test-toolbar';
+        toolbar.innerHTML = `
+            <button class="history-restore" title="Ausgewählten Stand wiederherstellen">Wiederherstellen</button>
+            <span class="history-hint">Shift+Klick: zwei Stände vergleichen</span>
+        `;
+        toolbar.querySelector('.history-restore').addEventListener('click', () => this.restore());
+
+        this.list = document.createElement('div');
+        this.list.className = 'recent-files-list history-list';
+
+        this.diff = document.createElement('pre');
+        this.diff.className = 'history-diff';
+
+        panel.append(header, toolbar, this.list, this.diff);
+        this.parent.appendChild(panel);
+        this.panel = panel;
+        this.title = header.querySelector('h3');
+    }
+
+    async refresh() {
+        const tab = appState.getActiveTab();
+        if (this.path !== tab?.filePath) {
+            this.selected = null;
+            this.compare = null;
+            this.diff.innerHTML = '';
+        }
+        this.path = tab?.filePath || null;
+        this.title.textContent = this.path ? `Verlauf – ${this.path.split(/[/\\]/).pop()}` : 'Verlauf';
+        this.title.title = this.path || '';
+
+        if (!this.path) {
+            this.list.innerHTML = `<div class="recent-empty">Keine gespeicherte Datei aktiv</div>`;
+            return;
+        }
+        try {
+            this.revisions = await HistoryList(this.path);
+            this.render(this.revisions);
+        } catch (err) {
+            this.logger.error(`${err}`);
+        }
+    }
+
+    render(revisions) {
+        this.list.innerHTML = '';
+        if (revisions.length === 0) {
+            this.list.innerHTML = `<div class="recent-empty">Noch keine Stände gespeichert</div>`;
+            return;
+        }
+        revisions.forEach(rev => {
+            const row = document.createElement('div');
+            row.className = 'test-node';
+            if (rev.id === this.selected) row.classList.add('debug-selected');
+            if (rev.id === this.compare) row.classList.add('history-compare');
+
+            const time = document.createElement('span');
+            time.className = 'test-name';
+            time.textContent = new Date(rev.time).toLocaleString();
+            const size = document.createElement('span');
+            size.className = 'test-time';
+            size.textContent = formatSize(rev.size);
+            row.append(time, size);
+
+            row.addEventListener('click', (e) => this.select(rev.id, e.shiftKey));
+            this.list.appendChild(row);
+        });
+    }
+
+    async select(id, shift) {
+        if (shift && this.selected && this.selected !== id) {
+            this.compare = id;
+        } else {
+            this.selected = id;
+            this.compare = null;
+        }
+        this.render(this.revisions);
+
+        // Ältere Stände links, die Datei oder der neuere Stand rechts
+        let from = this.selected;
+        let to = this.compare || '';
+        if (to && to < from) [from, to] = [to, from];
+        try {
+            this.showDiff(await HistoryDiff(this.path, from, to));
+        } catch (err) {
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    showDiff(text) {
+        this.diff.innerHTML = '';
+        if (!text) {
+            this.diff.textContent = 'Keine Unterschiede';
+            return;
+        }
+        text.split('\n').forEach(line => {
+            const span = document.createElement('span');
+            if (line.startsWith('@@')) span.className = 'diff-hunk';
+            else if (line.startsWith('+') && !line.startsWith('+++')) span.className = 'diff-insert';
+            else if (line.startsWith('-') && !line.startsWith('---')) span.className = 'diff-delete';
+            span.textContent = line + '\n';
+            this.diff.appendChild(span);
+        });
+    }
+
+    async restore() {
+        if (!this.path || !this.selected) {
+            updateStatus("Bitte einen Stand auswählen", "error");
+            return;
+        }
+        const tab = appState.getActiveTab();
+        if (tab?.filePath === this.path && tab.dirty &&
+            !confirm("Die Datei hat ungespeicherte Änderungen. Trotzdem wiederherstellen?")) {
+            return;
+        }
+        try {
+            await HistoryRestore(this.path, this.selected);
+            updateStatus("Stand wiederhergestellt");
+            this.selected = null;
+            this.compare = null;
+            this.diff.innerHTML = '';
+            this.refresh();
+        } catch (err) {
+            updateStatus(`${err}`, "error");
+        }
+    }
+}
+
+function formatSize(bytes) {
+    if (bytes < 1024) return `${bytes} B`;
+    if (bytes < 1024 * 1024) return `${(bytes / 1024).toFixed(1)} KB`;
+    return `${(bytes / 1024 / 1024).toFixed(1)} MB`;
+}
@@ -34,6 +34,22 @@ export class LeftToolbar {
                     const icon = renderIcon("Clock");
                     ele.appendChild(icon);
                 }
+                if (title === "Probleme") {
+                    const icon = renderIcon("TriangleAlert");
+                    ele.appendChild(icon);
+                }
+                if (title === "Tests") {
+                    const icon = renderIcon("FlaskConical");
+                    ele.appendChild(icon);
+                }
+                if (title === "Debugger") {
+                    const icon = renderIcon("Bug");
+                    ele.appendChild(icon);
+                }
+                if (title === "Verlauf") {
+                    const icon = renderIcon("History");
+                    ele.appendChild(icon);
+                }
                 if (title === "AI Fenster") {
                     const icon = renderIcon("Sparkles");
                     ele.appendChild(icon);
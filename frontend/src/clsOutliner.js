@@ -2,6 +2,7 @@ import { renderIcon } from './lib/icons.js';
 import { Logger } from './logger.js';
 import { Decoration, EditorView } from '@codemirror/view';
 import { StateEffect, StateField } from '@codemirror/state';
+import { GetOutline } from '../wailsjs/go/main/App.js';
 
 // At the top of clsOutliner.js, after imports
 const highlightLineEffect = StateEffect.define();
@@ -106,7 +107,8 @@ export class CodeMirrorOutliner {
             return;
         }
 
-        this.outlineTree = this.parseOutline(content);
+        const filePath = typeof this.editor.getFilePath === 'function' ? this.editor.getFilePath() : '';
+        this.outlineTree = await this.loadBackendOutline(filePath, content) ?? this.parseOutline(content);
         this.renderOutline();
 
         // Lucide Icons aktualisieren
@@ -123,6 +125,27 @@ export class CodeMirrorOutliner {
         return '';
     }
 
+    // Exakte Symbole vom Go-Backend (Go, Markdown, JS/TS, Python); null = Regex-Fallback
+    async loadBackendOutline(filePath, content) {
+        if (!filePath) return null;
+        try {
+            const symbols = await GetOutline(filePath, content);
+            const convert = (items) => items.map(sym => ({
+                type: sym.kind,
+                name: sym.kind === 'method' && sym.detail.startsWith('(')
+                    ? `${sym.detail.slice(0, sym.detail.indexOf(')') + 1)} ${sym.name}`
+                    : sym.name,
+                line: sym.range.start.line,
+                range: sym.range,
+                level: sym.level,
+                children: convert(sym.children || [])
+            }));
+            return convert(symbols || []);
+        } catch (e) {
+            return null;
+        }
+    }
+
     parseOutline(content) {
         const outline = [];
         const lines = content.split('\n');
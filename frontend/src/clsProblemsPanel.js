@@ -0,0 +1,103 @@
+import { renderIcon } from './lib/icons.js';
+import { Logger } from './logger.js';
+import { RunLinters } from '../wailsjs/go/main/App.js';
+import { appState } from './state.js';
+import { diagnosticsStore } from './diagnostics.js';
+import { openFileAtPosition } from './navigation.js';
+
+// Problems list of all linter and language server diagnostics
+export class ProblemsPanel {
+    constructor(parentSelector = '.leftToolbarContainer') {
+        this.logger = new Logger("ProblemsPanel");
+        this.parent = document.querySelector(parentSelector);
+        if (!this.parent) {
+            this.logger.error(`Container "${parentSelector}" not found.`);
+            return;
+        }
+
+        this.createPanel();
+        diagnosticsStore.onChange(() => this.scheduleRender());
+    }
+
+    createPanel() {
+        const panel = document.createElement('div');
+        panel.id = 'problems-panel';
+        panel.className = 'recent-files-panel hidden';
+
+        const header = document.createElement('div');
+        header.className = 'recent-header';
+        const title = document.createElement('h3');
+        title.textContent = 'Probleme';
+        header.appendChild(title);
+
+        const lintBtn = document.createElement('button');
+        lintBtn.className = 'btn-clear-recent';
+        lintBtn.title = 'Aktuelle Datei prüfen';
+        lintBtn.appendChild(renderIcon("RefreshCw", { width: 16, height: 16 }));
+        lintBtn.addEventListener('click', () => {
+            const tab = appState.getActiveTab();
+            if (tab?.filePath) RunLinters(tab.filePath);
+        });
+        header.appendChild(lintBtn);
+
+        this.list = document.createElement('div');
+        this.list.className = 'recent-files-list';
+
+        panel.appendChild(header);
+        panel.appendChild(this.list);
+        this.parent.appendChild(panel);
+        this.panel = panel;
+        this.render();
+    }
+
+    // Mehrere Events kurz hintereinander nur einmal zeichnen
+    scheduleRender() {
+        if (this.renderTimer) return;
+        this.renderTimer = setTimeout(() => {
+            this.renderTimer = null;
+            this.render();
+        }, 100);
+    }
+
+    render() {
+        this.list.innerHTML = '';
+        const paths = diagnosticsStore.paths();
+        const { errors, warnings } = diagnosticsStore.counts();
+        this.panel.querySelector('h3').textContent = `Probleme (${errors} Fehler, ${warnings} Warnungen)`;
+
+        if (paths.length === 0) {
+            const empty = document.createElement('div');
+            empty.className = 'recent-empty';
+            empty.textContent = 'Keine Probleme gefunden';
+            this.list.appendChild(empty);
+            return;
+        }
+
+        for (const path of paths) {
+            const fileHeader = document.createElement('div');
+            fileHeader.className = 'problem-file';
+            fileHeader.textContent = path.split(/[/\\]/).pop();
+            fileHeader.title = path;
+            this.list.appendChild(fileHeader);
+
+            for (const d of diagnosticsStore.get(path)) {
+                const item = document.createElement('div');
+                item.className = `recent-item problem-item problem-${d.severity}`;
+                item.title = `${path}:${d.line + 1}:${d.column + 1}`;
+
+                const message = document.createElement('div');
+                message.className = 'recent-name';
+                message.textContent = d.message;
+
+                const location = document.createElement('div');
+                location.className = 'recent-path';
+                location.textContent = `${d.source} · Zeile ${d.line + 1}, Spalte ${d.column + 1}`;
+
+                item.appendChild(message);
+                item.appendChild(location);
+                item.addEventListener('click', () => openFileAtPosition(path, d.line, d.column));
+                this.list.appendChild(item);
+            }
+        }
+    }
+}
@@ -0,0 +1,169 @@
+import { renderIcon } from './lib/icons.js';
+import { Logger } from './logger.js';
+import { EventsOn } from "../wailsjs/runtime/runtime.js";
+import { RunGoTests } from '../wailsjs/go/main/App.js';
+import { appState } from './state.js';
+import { editorManager } from './editor.js';
+import { updateStatus } from './ui.js';
+import { coverageStore } from './coverage.js';
+import { outputPanel } from './outputPanel.js';
+import { openFileAtPosition } from './navigation.js';
+import { saveFile } from './fileOperations.js';
+
+const STATUS_SYMBOL = { pass: '✓', fail: '✗', skip: '↷', run: '…' };
+
+// Go test runner: results tree of "go test -json" and coverage toggle
+export class TestPanel {
+    constructor(parentSelector = '.leftToolbarContainer') {
+        this.logger = new Logger("TestPanel");
+        this.parent = document.querySelector(parentSelector);
+        if (!this.parent) {
+            this.logger.error(`Container "${parentSelector}" not found.`);
+            return;
+        }
+        this.collapsed = new Set();   // Eingeklappte Knoten (package + test)
+
+        this.createPanel();
+        EventsOn('gotest-update', ({ tree, final }) => this.render(tree, final));
+    }
+
+    createPanel() {
+        const panel = document.createElement('div');
+        panel.id = 'test-panel';
+        panel.className = 'recent-files-panel hidden';
+
+        const header = document.createElement('div');
+        header.className = 'recent-header';
+        header.innerHTML = `<h3>Go Tests</h3>`;
+
+        const toolbar = document.createElement('div');
+        toolbar.className = 'test-toolbar';
+        toolbar.innerHTML = `
+            <button data-scope="package" title="Alle Tests des Pakets">Paket</button>
+            <button data-scope="file" title="Tests dieser Datei">Datei</button>
+            <button data-scope="test" title="Test unter dem Cursor">Test</button>
+            <label title="Mit -coverprofile ausführen"><input type="checkbox" class="test-coverage"> Coverage</label>
+            <label title="Abdeckung im Editor anzeigen"><input type="checkbox" class="test-show-coverage" checked> Anzeigen</label>
+        `;
+        toolbar.querySelectorAll('button').forEach(btn => {
+            btn.addEventListener('click', () => this.run(btn.dataset.scope));
+        });
+        toolbar.querySelector('.test-show-coverage').addEventListener('change', (e) => {
+            coverageStore.setVisible(e.target.checked);
+        });
+
+        this.tree = document.createElement('div');
+        this.tree.className = 'recent-files-list test-tree';
+        this.tree.innerHTML = `<div class="recent-empty">Noch keine Testergebnisse</div>`;
+
+        panel.appendChild(header);
+        panel.appendChild(toolbar);
+        panel.appendChild(this.tree);
+        this.parent.appendChild(panel);
+        this.panel = panel;
+        this.header = header.querySelector('h3');
+
+        const icon = renderIcon("RefreshCw", { width: 16, height: 16 });
+        const rerun = document.createElement('button');
+        rerun.className = 'btn-clear-recent';
+        rerun.title = 'Letzten Lauf wiederholen';
+        rerun.appendChild(icon);
+        rerun.addEventListener('click', () => this.lastRun && this.start(this.lastRun));
+        header.appendChild(rerun);
+    }
+
+    async run(scope) {
+        const tab = appState.getActiveTab();
+        if (!tab?.filePath || !tab.filePath.endsWith('.go')) {
+            updateStatus("Bitte eine Go-Datei öffnen", "error");
+            return;
+        }
+        if (tab.dirty && !(await saveFile())) return;
+
+        let line = 0;
+        const view = editorManager.getActiveView();
+        if (view) {
+            line = view.state.doc.lineAt(view.state.selection.main.head).number - 1;
+        }
+        const coverage = this.panel.querySelector('.test-coverage').checked;
+        this.start({ path: tab.filePath, scope, line, coverage });
+    }
+
+    async start(request) {
+        this.lastRun = request;
+        try {
+            const info = await RunGoTests(request.path, request.scope, request.line, request.coverage);
+            outputPanel.attach(info);
+            this.header.textContent = 'Go Tests …';
+        } catch (err) {
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    render(tree, final) {
+        const counts = { pass: 0, fail: 0, skip: 0 };
+        const countTests = (node) => {
+            if (node.test && node.children.length === 0) counts[node.status] = (counts[node.status] || 0) + 1;
+            node.children.forEach(countTests);
+        };
+        countTests(tree);
+        this.header.textContent = `Go Tests ${final ? '' : '… '}(${counts.pass} ✓, ${counts.fail} ✗, ${counts.skip} ↷)`;
+
+        this.tree.innerHTML = '';
+        if (tree.children.length === 0) {
+            this.tree.innerHTML = `<div class="recent-empty">Keine Tests ausgeführt</div>`;
+            return;
+        }
+        tree.children.forEach(child => this.tree.appendChild(this.renderNode(child, 0)));
+
+        if (final) {
+            updateStatus(`Tests: ${counts.pass} bestanden, ${counts.fail} fehlgeschlagen, ${counts.skip} übersprungen`,
+                counts.fail || tree.status === 'fail' ? "error" : "success");
+        }
+    }
+
+    renderNode(node, depth) {
+        const key = `${node.package}\u0000${node.test}`;
+        const wrapper = document.createElement('div');
+
+        const row = document.createElement('div');
+        row.className = `test-node test-${node.status}`;
+        row.style.paddingLeft = `${depth * 14 + 4}px`;
+
+        const symbol = document.createElement('span');
+        symbol.className = 'test-status';
+        symbol.textContent = STATUS_SYMBOL[node.status] || '?';
+
+        const name = document.createElement('span');
+        name.className = 'test-name';
+        name.textContent = node.name;
+
+        const time = document.createElement('span');
+        time.className = 'test-time';
+        time.textContent = node.status !== 'run' && node.elapsed ? `${node.elapsed.toFixed(2)}s` : '';
+
+        row.append(symbol, name, time);
+        if (node.output.length) {
+            row.title = node.output.slice(-20).join('\n');
+        }
+        row.addEventListener('click', () => {
+            if (node.path) {
+                openFileAtPosition(node.path, node.line - 1, 0);
+            } else if (node.children.length) {
+                if (this.collapsed.has(key)) this.collapsed.delete(key);
+                else this.collapsed.add(key);
+                wrapper.querySelector('.test-children')?.classList.toggle('hidden');
+            }
+        });
+        wrapper.appendChild(row);
+
+        if (node.children.length) {
+            const children = document.createElement('div');
+            children.className = 'test-children';
+            if (this.collapsed.has(key)) children.classList.add('hidden');
+            node.children.forEach(child => children.appendChild(this.renderNode(child, depth + 1)));
+            wrapper.appendChild(children);
+        }
+        return wrapper;
+    }
+}
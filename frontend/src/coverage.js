@@ -0,0 +1,82 @@
+// Coverage overlay: covered/uncovered lines from the last Go test run with coverage
+import { Decoration, EditorView } from '@codemirror/view';
+import { StateEffect, StateField } from '@codemirror/state';
+import { EventsOn } from "../wailsjs/runtime/runtime.js";
+import { GetCoverage, ClearCoverage } from '../wailsjs/go/main/App.js';
+
+const setCoverageEffect = StateEffect.define();
+
+export const coverageField = StateField.define({
+    create() {
+        return Decoration.none;
+    },
+    update(decorations, tr) {
+        decorations = decorations.map(tr.changes);
+        for (let effect of tr.effects) {
+            if (effect.is(setCoverageEffect)) {
+                decorations = effect.value;
+            }
+        }
+        return decorations;
+    },
+    provide: f => EditorView.decorations.from(f)
+});
+
+class CoverageStore {
+    constructor() {
+        this.visible = true;
+        this.files = new Set();     // Dateien mit Coverage-Daten
+        this.listeners = [];
+
+        EventsOn('coverage-updated', (files) => {
+            (files || []).forEach(f => this.files.add(f));
+            this.listeners.forEach(cb => cb(files || []));
+        });
+    }
+
+    setVisible(visible) {
+        this.visible = visible;
+        this.listeners.forEach(cb => cb([...this.files]));
+    }
+
+    async clear() {
+        const files = [...this.files];
+        this.files.clear();
+        await ClearCoverage();
+        this.listeners.forEach(cb => cb(files));
+    }
+
+    onChange(callback) {
+        this.listeners.push(callback);
+    }
+}
+
+export const coverageStore = new CoverageStore();
+
+/**
+ * Markiert abgedeckte und nicht abgedeckte Zeilen der Datei path.
+ */
+export async function applyCoverage(view, path) {
+    if (!view) return;
+    let blocks = [];
+    if (path && coverageStore.visible && coverageStore.files.has(path)) {
+        blocks = await GetCoverage(path);
+    }
+
+    // Eine Zeile gilt als abgedeckt, wenn einer ihrer Blöcke ausgeführt wurde
+    const lines = new Map();
+    for (const b of blocks) {
+        for (let l = b.startLine; l <= b.endLine; l++) {
+            lines.set(l, lines.get(l) || b.covered);
+        }
+    }
+
+    const doc = view.state.doc;
+    const ranges = [];
+    for (const [lineNo, covered] of [...lines].sort((a, b) => a[0] - b[0])) {
+        if (lineNo < 1 || lineNo > doc.lines) continue;
+        const cls = covered ? 'cm-coverage-covered' : 'cm-coverage-uncovered';
+        ranges.push(Decoration.line({ attributes: { class: cls } }).range(doc.line(lineNo).from));
+    }
+    view.dispatch({ effects: setCoverageEffect.of(Decoration.set(ranges)) });
+}
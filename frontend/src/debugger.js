@@ -0,0 +1,223 @@
+// Debugger integration: breakpoint gutter, current line and the state of the Delve session
+import { Decoration, EditorView, gutter, GutterMarker } from '@codemirror/view';
+import { StateEffect, StateField, RangeSet } from '@codemirror/state';
+import { EventsOn } from "../wailsjs/runtime/runtime.js";
+import { DebugSetBreakpoints, DebugGetBreakpoints } from '../wailsjs/go/main/App.js';
+
+class BreakpointMarker extends GutterMarker {
+    constructor(verified = true) {
+        super();
+        this.verified = verified;
+    }
+    eq(other) {
+        return other.verified === this.verified;
+    }
+    toDOM() {
+        const el = document.createElement('span');
+        el.className = this.verified ? 'cm-breakpoint' : 'cm-breakpoint cm-breakpoint-unverified';
+        el.textContent = '●';
+        return el;
+    }
+}
+
+const breakpointMarker = new BreakpointMarker(true);
+const unverifiedMarker = new BreakpointMarker(false);
+
+// value: { path, markers } der Datei im Editor
+const setBreakpointsEffect = StateEffect.define();
+const setDebugLineEffect = StateEffect.define();
+
+// Breakpoints als RangeSet, damit sie beim Bearbeiten mit ihren Zeilen wandern
+const breakpointField = StateField.define({
+    create() {
+        return { path: null, markers: RangeSet.empty };
+    },
+    update(value, tr) {
+        let { path, markers } = value;
+        markers = markers.map(tr.changes);
+        for (let effect of tr.effects) {
+            if (effect.is(setBreakpointsEffect)) {
+                path = effect.value.path;
+                markers = effect.value.markers;
+            }
+        }
+        return { path, markers };
+    }
+});
+
+const debugLineField = StateField.define({
+    create() {
+        return Decoration.none;
+    },
+    update(decorations, tr) {
+        decorations = decorations.map(tr.changes);
+        for (let effect of tr.effects) {
+            if (effect.is(setDebugLineEffect)) {
+                decorations = effect.value;
+            }
+        }
+        return decorations;
+    },
+    provide: f => EditorView.decorations.from(f)
+});
+
+// Zeilen (1-basiert) der Breakpoint-Marker im aktuellen Dokument
+function markerLines(state) {
+    const lines = [];
+    const cursor = state.field(breakpointField).markers.iter();
+    for (; cursor.value; cursor.next()) {
+        lines.push(state.doc.lineAt(cursor.from).number);
+    }
+    return [...new Set(lines)];
+}
+
+class DebugStore {
+    constructor() {
+        this.breakpoints = new Map();   // path -> Map(line -> verified)
+        this.active = false;
+        this.stopped = null;            // { threadId, frames[] } beim letzten Halt
+        this.location = null;           // { path, line } aktuelle Zeile (1-basiert)
+        this.listeners = [];
+        this.syncTimers = new Map();
+
+        DebugGetBreakpoints().then(all => {
+            for (const [path, lines] of Object.entries(all || {})) {
+                this.breakpoints.set(path, new Map(lines.map(l => [l, true])));
+            }
+            this.notify('breakpoints');
+        }).catch(() => {});
+
+        EventsOn('debug-started', () => {
+            this.active = true;
+            this.notify('started');
+        });
+        EventsOn('debug-stopped', (data) => {
+            this.stopped = data;
+            const top = (data.frames || []).find(f => f.path);
+            this.location = top ? { path: top.path, line: top.line } : null;
+            this.notify('stopped');
+        });
+        EventsOn('debug-continued', () => {
+            this.stopped = null;
+            this.location = null;
+            this.notify('continued');
+        });
+        EventsOn('debug-terminated', () => {
+            this.active = false;
+            this.stopped = null;
+            this.location = null;
+            this.notify('terminated');
+        });
+        EventsOn('debug-breakpoints', ({ path, breakpoints }) => this.confirm(path, breakpoints));
+    }
+
+    lines(path) {
+        return [...(this.breakpoints.get(path)?.keys() || [])].sort((a, b) => a - b);
+    }
+
+    async set(path, lines) {
+        const map = new Map(lines.map(l => [l, !this.active]));
+        if (map.size) this.breakpoints.set(path, map);
+        else this.breakpoints.delete(path);
+        this.notify('breakpoints', path);
+        try {
+            this.confirm(path, await DebugSetBreakpoints(path, lines));
+        } catch (err) {
+            console.warn('DebugSetBreakpoints:', err);
+        }
+    }
+
+    // Übernimmt die vom Debugger bestätigten Breakpoints
+    confirm(path, breakpoints) {
+        if (!breakpoints?.length) return;
+        const map = new Map(breakpoints.map(bp => [bp.line, bp.verified || !this.active]));
+        this.breakpoints.set(path, map);
+        this.notify('breakpoints', path);
+    }
+
+    toggle(path, line) {
+        const lines = new Set(this.lines(path));
+        if (lines.has(line)) lines.delete(line);
+        else lines.add(line);
+        this.set(path, [...lines]);
+    }
+
+    // Nach Änderungen am Text verschobene Breakpoints (verzögert) übernehmen
+    scheduleSync(path, lines) {
+        clearTimeout(this.syncTimers.get(path));
+        this.syncTimers.set(path, setTimeout(() => {
+            this.syncTimers.delete(path);
+            const current = this.lines(path);
+            if (current.join() !== lines.join()) this.set(path, lines);
+        }, 500));
+    }
+
+    notify(kind, path = null) {
+        this.listeners.forEach(cb => cb(kind, path));
+    }
+
+    onChange(callback) {
+        this.listeners.push(callback);
+    }
+}
+
+export const debugStore = new DebugStore();
+
+const breakpointGutter = gutter({
+    class: 'cm-breakpoint-gutter',
+    markers: view => view.state.field(breakpointField).markers,
+    initialSpacer: () => breakpointMarker,
+    domEventHandlers: {
+        mousedown(view, line) {
+            const path = view.state.field(breakpointField).path;
+            if (!path) return false;
+            debugStore.toggle(path, view.state.doc.lineAt(line.from).number);
+            return true;
+        }
+    }
+});
+
+const breakpointSync = EditorView.updateListener.of(update => {
+    if (!update.docChanged) return;
+    const { path, markers } = update.state.field(breakpointField);
+    if (path && markers.size) debugStore.scheduleSync(path, markerLines(update.state));
+});
+
+export const debuggerExtensions = [breakpointField, breakpointGutter, breakpointSync, debugLineField];
+
+/**
+ * Zeigt die Breakpoints der Datei path und die aktuelle Zeile des Debuggers.
+ */
+export function applyDebugState(view, path) {
+    if (!view) return;
+    const doc = view.state.doc;
+
+    const markers = [];
+    for (const [line, verified] of [...(debugStore.breakpoints.get(path) || [])].sort((a, b) => a[0] - b[0])) {
+        if (line < 1 || line > doc.lines) continue;
+        markers.push((verified ? breakpointMarker : unverifiedMarker).range(doc.line(line).from));
+    }
+
+    const lineDecorations = [];
+    const loc = debugStore.location;
+    if (path && loc?.path === path && loc.line >= 1 && loc.line <= doc.lines) {
+        lineDecorations.push(Decoration.line({ attributes: { class: 'cm-debug-line' } }).range(doc.line(loc.line).from));
+    }
+
+    view.dispatch({
+        effects: [
+            setBreakpointsEffect.of({ path: path || null, markers: RangeSet.of(markers) }),
+            setDebugLineEffect.of(Decoration.set(lineDecorations))
+        ]
+    });
+}
+
+/**
+ * Schaltet den Breakpoint in der Zeile des Cursors um.
+ */
+export function toggleBreakpointAtCursor(view) {
+    const path = view?.state.field(breakpointField, false)?.path;
+    if (!path) return false;
+    debugStore.toggle(path, view.state.doc.lineAt(view.state.selection.main.head).number);
+    return true;
+}
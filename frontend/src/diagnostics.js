@@ -0,0 +1,147 @@
+// Diagnostics from linters and language servers, shown in the editor and the problems panel
+import { Decoration, EditorView } from '@codemirror/view';
+import { StateEffect, StateField } from '@codemirror/state';
+import { EventsOn } from "../wailsjs/runtime/runtime.js";
+import { lspClient } from './lspClient.js';
+
+const setDiagnosticsEffect = StateEffect.define();
+
+export const diagnosticsField = StateField.define({
+    create() {
+        return Decoration.none;
+    },
+    update(decorations, tr) {
+        decorations = decorations.map(tr.changes);
+        for (let effect of tr.effects) {
+            if (effect.is(setDiagnosticsEffect)) {
+                decorations = effect.value;
+            }
+        }
+        return decorations;
+    },
+    provide: f => EditorView.decorations.from(f)
+});
+
+// LSP DiagnosticSeverity: 1 = Error, 2 = Warning, 3 = Information, 4 = Hint
+const LSP_SEVERITY = { 1: 'error', 2: 'warning', 3: 'info', 4: 'info' };
+
+/**
+ * Einheitliche Form aller Diagnosen (0-basierte Zeilen/Spalten):
+ * { path, line, column, endLine, endColumn, severity, message, source }
+ */
+class DiagnosticsStore {
+    constructor() {
+        this.entries = new Map();   // path -> Map(source -> diagnostics[])
+        this.listeners = [];
+
+        // Linter-Ergebnisse (1-basiert, 0 = unbekannt)
+        EventsOn('lint-results', ({ path, linter, problems }) => {
+            this.set(path, `lint:${linter}`, (problems || []).map(p => ({
+                path,
+                line: Math.max(p.line - 1, 0),
+                column: Math.max(p.column - 1, 0),
+                endLine: null,
+                endColumn: null,
+                severity: p.severity,
+                message: p.message,
+                source: p.source
+            })));
+        });
+
+        lspClient.onDiagnostics((path, diagnostics) => {
+            this.set(path, 'lsp', (diagnostics || []).map(d => ({
+                path,
+                line: d.range.start.line,
+                column: d.range.start.character,
+                endLine: d.range.end.line,
+                endColumn: d.range.end.character,
+                severity: LSP_SEVERITY[d.severity] || 'error',
+                message: d.message,
+                source: d.source || 'lsp'
+            })));
+        });
+    }
+
+    set(path, source, diagnostics) {
+        if (!this.entries.has(path)) this.entries.set(path, new Map());
+        const sources = this.entries.get(path);
+        if (diagnostics.length > 0) {
+            sources.set(source, diagnostics);
+        } else {
+            sources.delete(source);
+            if (sources.size === 0) this.entries.delete(path);
+        }
+        this.listeners.forEach(cb => cb(path));
+    }
+
+    get(path) {
+        const sources = this.entries.get(path);
+        if (!sources) return [];
+        return [...sources.values()].flat()
+            .sort((a, b) => a.line - b.line || a.column - b.column);
+    }
+
+    // Alle Pfade mit Diagnosen, sortiert
+    paths() {
+        return [...this.entries.keys()].sort();
+    }
+
+    counts() {
+        let errors = 0, warnings = 0;
+        for (const path of this.entries.keys()) {
+            for (const d of this.get(path)) {
+                if (d.severity === 'error') errors++;
+                else if (d.severity === 'warning') warnings++;
+            }
+        }
+        return { errors, warnings };
+    }
+
+    onChange(callback) {
+        this.listeners.push(callback);
+    }
+}
+
+export const diagnosticsStore = new DiagnosticsStore();
+
+/**
+ * Setzt die Diagnose-Markierungen einer View für die Datei path.
+ */
+export function applyDiagnostics(view, path) {
+    if (!view) return;
+    const doc = view.state.doc;
+    const ranges = [];
+
+    for (const d of path ? diagnosticsStore.get(path) : []) {
+        if (d.line >= doc.lines) continue;
+        const line = doc.line(d.line + 1);
+        const from = Math.min(line.from + d.column, line.to);
+        let to = from;
+        if (d.endLine !== null && d.endLine < doc.lines) {
+            const endLine = doc.line(d.endLine + 1);
+            to = Math.min(endLine.from + d.endColumn, endLine.to);
+        }
+        const attributes = { title: `${d.source}: ${d.message}` };
+
+        ranges.push(Decoration.line({
+            attributes: { class: `cm-diagnostic-line cm-diagnostic-line-${d.severity}` }
+        }).range(line.from));
+        if (to > from) {
+            ranges.push(Decoration.mark({
+                class: `cm-diagnostic cm-diagnostic-${d.severity}`,
+                attributes
+            }).range(from, to));
+        } else {
+            // Ohne Bereich das Wort an der Position markieren
+            const word = view.state.wordAt(from);
+            if (word) {
+                ranges.push(Decoration.mark({
+                    class: `cm-diagnostic cm-diagnostic-${d.severity}`,
+                    attributes
+                }).range(word.from, word.to));
+            }
+        }
+    }
+
+    view.dispatch({ effects: setDiagnosticsEffect.of(Decoration.set(ranges, true)) });
+}
@@ -0,0 +1,66 @@
+// Passwortabfrage für verschlüsselte Dateien. Das Backend sendet
+// "passphrase-request" und wartet auf ProvidePassphrase; ein leeres
+// Passwort bricht ab.
+import { ProvidePassphrase } from '../../wailsjs/go/main/App.js';
+import { EventsOn } from '../../wailsjs/runtime/runtime.js';
+
+const queue = [];
+
+export function initPassphraseDialog() {
+    EventsOn('passphrase-request', (request) => {
+        queue.push(request);
+        if (queue.length === 1) show(request);
+    });
+}
+
+function next() {
+    queue.shift();
+    if (queue.length) show(queue[0]);
+}
+
+function show(request) {
+    const name = request.path.split(/[\\/]/).pop();
+    const modal = document.createElement('div');
+    modal.className = 'passphrase-modal';
+    modal.innerHTML = `
+        <form class="passphrase-content">
+            <h3>${request.confirm ? 'Datei verschlüsseln' : 'Verschlüsselte Datei'}</h3>
+            <p class="passphrase-path"></p>
+            ${request.retry ? '<p class="passphrase-error">Falsches Passwort.</p>' : ''}
+            <input type="password" name="passphrase" placeholder="Passwort" autocomplete="off">
+            ${request.confirm ? '<input type="password" name="repeat" placeholder="Passwort wiederholen" autocomplete="off">' : ''}
+            <p class="passphrase-error passphrase-mismatch" style="display: none;">Die Passwörter stimmen nicht überein.</p>
+            <div class="modal-buttons">
+                <button type="submit">OK</button>
+                <button type="button" data-action="cancel">Abbrechen</button>
+            </div>
+        </form>
+    `;
+    modal.querySelector('.passphrase-path').textContent = name;
+    modal.querySelector('.passphrase-path').title = request.path;
+
+    const form = modal.querySelector('form');
+    const answer = (passphrase) => {
+        modal.remove();
+        ProvidePassphrase(request.id, passphrase);
+        next();
+    };
+    form.addEventListener('submit', (e) => {
+        e.preventDefault();
+        const passphrase = form.passphrase.value;
+        if (!passphrase) return;
+        if (request.confirm && passphrase !== form.repeat.value) {
+            modal.querySelector('.passphrase-mismatch').style.display = '';
+            form.repeat.select();
+            return;
+        }
+        answer(passphrase);
+    });
+    form.querySelector('[data-action="cancel"]').addEventListener('click', () => answer(''));
+    form.addEventListener('keydown', (e) => {
+        if (e.key === 'Escape') answer('');
+    });
+
+    document.body.appendChild(modal);
+    form.passphrase.focus();
+}
@@ -0,0 +1,110 @@
+// Auswahlliste mit Suchfeld, z. B. für Referenzen und Workspace-Symbole.
+// Einträge: { label, detail, value }. Mit search wird die Liste bei jeder
+// Eingabe neu geholt, sonst werden die festen items gefiltert.
+const SEARCH_DEBOUNCE_MS = 150;
+
+export function showPicker({ title, placeholder = 'Suchen…', items = [], search = null, onSelect }) {
+    const modal = document.createElement('div');
+    modal.className = 'picker-modal';
+    modal.innerHTML = `
+        <div class="picker-content">
+            <h3></h3>
+            <input type="text" autocomplete="off" spellcheck="false">
+            <div class="picker-list"></div>
+        </div>
+    `;
+    modal.querySelector('h3').textContent = title;
+    const input = modal.querySelector('input');
+    input.placeholder = placeholder;
+    const list = modal.querySelector('.picker-list');
+
+    let shown = [];
+    let selected = 0;
+    let searchTimer = null;
+    let searchRun = 0;
+
+    const close = () => {
+        clearTimeout(searchTimer);
+        modal.remove();
+    };
+
+    const choose = (index) => {
+        const item = shown[index];
+        if (!item) return;
+        close();
+        onSelect(item.value);
+    };
+
+    const render = () => {
+        list.innerHTML = '';
+        if (shown.length === 0) {
+            const empty = document.createElement('div');
+            empty.className = 'picker-empty';
+            empty.textContent = search && !input.value.trim() ? 'Suchbegriff eingeben' : 'Keine Treffer';
+            list.appendChild(empty);
+            return;
+        }
+        shown.forEach((item, i) => {
+            const row = document.createElement('div');
+            row.className = 'picker-item' + (i === selected ? ' selected' : '');
+            const label = document.createElement('span');
+            label.className = 'picker-label';
+            label.textContent = item.label;
+            const detail = document.createElement('span');
+            detail.className = 'picker-detail';
+            detail.textContent = item.detail || '';
+            row.title = item.detail || item.label;
+            row.append(label, detail);
+            row.addEventListener('mousedown', (e) => {
+                e.preventDefault();
+                choose(i);
+            });
+            list.appendChild(row);
+        });
+        list.children[selected]?.scrollIntoView({ block: 'nearest' });
+    };
+
+    const update = () => {
+        const query = input.value.trim();
+        selected = 0;
+        if (!search) {
+            const q = query.toLowerCase();
+            shown = items.filter(item => !q || `${item.label} ${item.detail || ''}`.toLowerCase().includes(q));
+            render();
+            return;
+        }
+        clearTimeout(searchTimer);
+        searchTimer = setTimeout(async () => {
+            // Nur die Antwort auf die letzte Eingabe anzeigen
+            const run = ++searchRun;
+            const result = query ? await search(query) : [];
+            if (run !== searchRun || !modal.isConnected) return;
+            shown = result || [];
+            selected = 0;
+            render();
+        }, SEARCH_DEBOUNCE_MS);
+    };
+
+    input.addEventListener('input', update);
+    input.addEventListener('keydown', (e) => {
+        if (e.key === 'ArrowDown' || e.key === 'ArrowUp') {
+            e.preventDefault();
+            if (shown.length === 0) return;
+            selected = (selected + (e.key === 'ArrowDown' ? 1 : shown.length - 1)) % shown.length;
+            render();
+        } else if (e.key === 'Enter') {
+            e.preventDefault();
+            choose(selected);
+        } else if (e.key === 'Escape') {
+            e.preventDefault();
+            close();
+        }
+    });
+    modal.addEventListener('mousedown', (e) => {
+        if (e.target === modal) close();
+    });
+
+    document.body.appendChild(modal);
+    update();
+    input.focus();
+}
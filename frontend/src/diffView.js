@@ -0,0 +1,167 @@
+// Side-by-side diff tab: two files, a buffer against the disk or the clipboard
+import { Compare, SaveComparePatch } from '../wailsjs/go/main/App.js';
+import { Logger } from './logger.js';
+import { updateStatus } from './ui.js';
+
+export class DiffView {
+    /**
+     * @param {object} request - { left, right, options } mit DiffSource-Objekten
+     */
+    constructor(tabId, paneData, request) {
+        this.logger = new Logger('DiffView');
+        this.tabId = tabId;
+        this.left = request.left;
+        this.right = request.right;
+        this.options = { ignoreWhitespace: false, ignoreCase: false, patience: false, context: -1, ...request.options };
+        this.current = -1;   // Index der ausgewählten Änderung
+
+        this.panel = document.createElement('div');
+        this.panel.id = `diff-panel-${tabId}`;
+        this.panel.className = 'diff-panel';
+        paneData.dom.appendChild(this.panel);
+
+        this.createToolbar();
+        this.body = document.createElement('div');
+        this.body.className = 'diff-body';
+        this.panel.appendChild(this.body);
+
+        this.refresh();
+    }
+
+    createToolbar() {
+        const toolbar = document.createElement('div');
+        toolbar.className = 'test-toolbar diff-toolbar';
+        toolbar.innerHTML = `
+            <span class="diff-title"></span>
+            <button data-action="prev" title="Vorherige Änderung (Shift+F7)">↑</button>
+            <button data-action="next" title="Nächste Änderung (F7)">↓</button>
+            <label><input type="checkbox" data-option="ignoreWhitespace"> Leerzeichen ignorieren</label>
+            <label><input type="checkbox" data-option="ignoreCase"> Groß/klein ignorieren</label>
+            <label title="Patience-Diff statt Myers"><input type="checkbox" data-option="patience"> Patience</label>
+            <label><input type="checkbox" data-option="changesOnly"> Nur Änderungen</label>
+            <button data-action="swap" title="Seiten tauschen">⇄</button>
+            <button data-action="refresh" title="Neu vergleichen">Aktualisieren</button>
+            <button data-action="patch" title="Als Unified-Diff speichern">Patch speichern</button>
+        `;
+        toolbar.querySelectorAll('input[data-option]').forEach(input => {
+            const option = input.dataset.option;
+            input.checked = option === 'changesOnly' ? this.options.context >= 0 : !!this.options[option];
+            input.addEventListener('change', () => {
+                if (option === 'changesOnly') this.options.context = input.checked ? 3 : -1;
+                else this.options[option] = input.checked;
+                this.refresh();
+            });
+        });
+        toolbar.querySelectorAll('button[data-action]').forEach(btn => {
+            btn.addEventListener('click', () => this.action(btn.dataset.action));
+        });
+        this.panel.addEventListener('keydown', (e) => {
+            if (e.key === 'F7') {
+                e.preventDefault();
+                this.action(e.shiftKey ? 'prev' : 'next');
+            }
+        });
+        this.panel.tabIndex = 0;
+        this.panel.appendChild(toolbar);
+        this.title = toolbar.querySelector('.diff-title');
+    }
+
+    async action(action) {
+        switch (action) {
+            case 'prev': this.jump(-1); break;
+            case 'next': this.jump(1); break;
+            case 'refresh': this.refresh(); break;
+            case 'swap':
+                [this.left, this.right] = [this.right, this.left];
+                this.refresh();
+                break;
+            case 'patch':
+                try {
+                    const path = await SaveComparePatch(this.left, this.right, this.options);
+                    if (path) updateStatus(`Patch gespeichert: ${path}`);
+                } catch (err) {
+                    updateStatus(`${err}`, "error");
+                }
+                break;
+        }
+    }
+
+    async refresh() {
+        try {
+            this.render(await Compare(this.left, this.right, this.options));
+        } catch (err) {
+            this.body.innerHTML = '';
+            this.title.textContent = `${err}`;
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    render(result) {
+        this.title.textContent = result.identical
+            ? `${result.leftName} ⇄ ${result.rightName}: identisch`
+            : `${result.leftName} ⇄ ${result.rightName}: +${result.added} −${result.removed} ~${result.changed}`;
+        this.title.title = `${result.leftName}\n${result.rightName}`;
+
+        const table = document.createElement('table');
+        table.className = 'diff-table';
+        result.hunks.forEach(hunk => {
+            if (this.options.context >= 0) {
+                const sep = table.insertRow();
+                sep.className = 'diff-hunk';
+                const cell = sep.insertCell();
+                cell.colSpan = 4;
+                cell.textContent = `@@ -${hunk.leftStart},${hunk.leftCount} +${hunk.rightStart},${hunk.rightCount} @@`;
+            }
+            let previous = 'equal';
+            for (const row of hunk.rows) {
+                const tr = table.insertRow();
+                tr.className = `diff-row diff-${row.kind}`;
+                // Erste Zeile jedes Änderungsblocks als Sprungziel
+                if (row.kind !== 'equal' && previous === 'equal') tr.classList.add('diff-block-start');
+                previous = row.kind;
+                this.addSide(tr, row.leftLine, row.left);
+                this.addSide(tr, row.rightLine, row.right);
+            }
+        });
+
+        this.body.innerHTML = '';
+        this.body.appendChild(table);
+        this.blocks = [...table.querySelectorAll('.diff-block-start')];
+        this.current = -1;
+    }
+
+    addSide(tr, line, segments) {
+        const num = tr.insertCell();
+        num.className = 'diff-num';
+        num.textContent = line || '';
+        const text = tr.insertCell();
+        text.className = line ? 'diff-text' : 'diff-text diff-empty';
+        for (const seg of segments || []) {
+            const span = document.createElement('span');
+            if (seg.changed) span.className = 'diff-word';
+            span.textContent = seg.text;
+            text.appendChild(span);
+        }
+    }
+
+    jump(direction) {
+        if (!this.blocks?.length) return;
+        this.blocks[this.current]?.classList.remove('diff-current');
+        const count = this.blocks.length;
+        this.current = this.current < 0
+            ? (direction > 0 ? 0 : count - 1)
+            : (this.current + direction + count) % count;
+        const block = this.blocks[this.current];
+        block.classList.add('diff-current');
+        block.scrollIntoView({ block: 'center' });
+    }
+
+    show() {
+        this.panel.style.display = 'flex';
+        this.panel.focus();
+    }
+
+    dispose() {
+        this.panel.remove();
+    }
+}
@@ -0,0 +1,239 @@
+// Directory comparison tab: two folder trees side by side with copy across
+import { CompareDirectories, CopyCompareEntry } from '../wailsjs/go/main/App.js';
+import { createNewTab } from './tabManager.js';
+import { Logger } from './logger.js';
+import { updateStatus } from './ui.js';
+
+const STATUS_MARK = { added: '+', removed: '−', changed: '~', identical: '=' };
+
+export class DirCompareView {
+    /**
+     * @param {object} request - { dirs: { left, right }, options }
+     */
+    constructor(tabId, paneData, request) {
+        this.logger = new Logger('DirCompareView');
+        this.tabId = tabId;
+        this.left = request.dirs.left;
+        this.right = request.dirs.right;
+        this.options = { ignore: null, thorough: false, ...request.options };
+        this.hideIdentical = false;
+        this.collapsed = new Set();   // Zugeklappte Ordner (relative Pfade)
+
+        this.panel = document.createElement('div');
+        this.panel.id = `diff-panel-${tabId}`;
+        this.panel.className = 'diff-panel';
+        paneData.dom.appendChild(this.panel);
+
+        this.createToolbar();
+        this.body = document.createElement('div');
+        this.body.className = 'diff-body';
+        this.panel.appendChild(this.body);
+
+        this.refresh();
+    }
+
+    createToolbar() {
+        const toolbar = document.createElement('div');
+        toolbar.className = 'test-toolbar diff-toolbar';
+        toolbar.innerHTML = `
+            <span class="diff-title"></span>
+            <label><input type="checkbox" data-option="hideIdentical"> Gleiche ausblenden</label>
+            <label title="Auch Dateien mit gleichem Änderungsdatum inhaltlich vergleichen"><input type="checkbox" data-option="thorough"> Gründlich</label>
+            <input type="text" class="dircmp-ignore" placeholder="Ignorieren: .git, node_modules" title="Kommagetrennte Muster, Enter zum Anwenden">
+            <button data-action="swap" title="Seiten tauschen">⇄</button>
+            <button data-action="refresh" title="Neu vergleichen">Aktualisieren</button>
+        `;
+        toolbar.querySelector('[data-option="hideIdentical"]').addEventListener('change', (e) => {
+            this.hideIdentical = e.target.checked;
+            this.render(this.result);
+        });
+        toolbar.querySelector('[data-option="thorough"]').addEventListener('change', (e) => {
+            this.options.thorough = e.target.checked;
+            this.refresh();
+        });
+        const ignore = toolbar.querySelector('.dircmp-ignore');
+        if (this.options.ignore) ignore.value = this.options.ignore.join(', ');
+        ignore.addEventListener('keydown', (e) => {
+            if (e.key !== 'Enter') return;
+            const value = ignore.value.trim();
+            // Leeres Feld: Standardmuster des Backends
+            this.options.ignore = value ? value.split(',').map(s => s.trim()).filter(Boolean) : null;
+            this.refresh();
+        });
+        toolbar.querySelectorAll('button[data-action]').forEach(btn => {
+            btn.addEventListener('click', () => this.action(btn.dataset.action));
+        });
+        this.panel.tabIndex = 0;
+        this.panel.appendChild(toolbar);
+        this.title = toolbar.querySelector('.diff-title');
+    }
+
+    action(action) {
+        switch (action) {
+            case 'refresh': this.refresh(); break;
+            case 'swap':
+                [this.left, this.right] = [this.right, this.left];
+                this.refresh();
+                break;
+        }
+    }
+
+    async refresh() {
+        try {
+            this.result = await CompareDirectories(this.left, this.right, this.options);
+            this.render(this.result);
+        } catch (err) {
+            this.result = null;
+            this.body.innerHTML = '';
+            this.title.textContent = `${err}`;
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    render(result) {
+        if (!result) return;
+        const name = (p) => p.split(/[/\\]/).pop();
+        this.title.textContent = `${name(result.left)} ⇄ ${name(result.right)}: ` +
+            `+${result.added} −${result.removed} ~${result.changed} =${result.identical}`;
+        this.title.title = `${result.left}\n${result.right}`;
+
+        const table = document.createElement('table');
+        table.className = 'diff-table dircmp-table';
+        const head = table.createTHead().insertRow();
+        ['', 'Pfad', 'Links', 'Geändert', '', '', 'Rechts', 'Geändert'].forEach(label => {
+            const th = document.createElement('th');
+            th.textContent = label;
+            head.appendChild(th);
+        });
+
+        const tbody = table.createTBody();
+        let hiddenBelow = null;   // Pfad eines zugeklappten Ordners
+        for (const entry of result.entries) {
+            if (hiddenBelow && entry.path.startsWith(hiddenBelow + '/')) continue;
+            hiddenBelow = null;
+            if (this.hideIdentical && entry.status === 'identical') continue;
+            if (entry.isDir && this.collapsed.has(entry.path)) hiddenBelow = entry.path;
+            this.addRow(tbody, entry);
+        }
+
+        this.body.innerHTML = '';
+        this.body.appendChild(table);
+    }
+
+    addRow(tbody, entry) {
+        const tr = tbody.insertRow();
+        tr.className = `dircmp-row dircmp-${entry.status}`;
+
+        const status = tr.insertCell();
+        status.className = 'diff-num';
+        status.textContent = STATUS_MARK[entry.status] || '';
+        if (entry.error) {
+            // Ordner konnte nicht gelesen werden
+            status.textContent = '⚠';
+            status.title = entry.error;
+        }
+
+        const path = tr.insertCell();
+        path.className = 'dircmp-name';
+        path.style.paddingLeft = `${entry.depth * 16 + 4}px`;
+        if (entry.isDir) {
+            path.textContent = `${this.collapsed.has(entry.path) ? '▸' : '▾'} ${entry.name}/`;
+            path.addEventListener('click', () => {
+                if (!this.collapsed.delete(entry.path)) this.collapsed.add(entry.path);
+                this.render(this.result);
+            });
+        } else {
+            path.textContent = entry.name;
+        }
+        path.title = entry.path;
+
+        this.addSide(tr, entry.leftSize, entry.leftModified, entry.isDir);
+        this.addCopy(tr, entry, 'right', '→', 'Nach rechts kopieren');
+        this.addCopy(tr, entry, 'left', '←', 'Nach links kopieren');
+        this.addSide(tr, entry.rightSize, entry.rightModified, entry.isDir);
+
+        // Geänderte Dateien im Dateivergleich öffnen
+        if (!entry.isDir && entry.status === 'changed') {
+            tr.title = 'Doppelklick: Dateien vergleichen';
+            tr.addEventListener('dblclick', () => this.openDiff(entry));
+        }
+    }
+
+    addSide(tr, size, modified, isDir) {
+        const sizeCell = tr.insertCell();
+        sizeCell.className = 'dircmp-size';
+        const timeCell = tr.insertCell();
+        timeCell.className = 'dircmp-time';
+        if (size < 0) {
+            sizeCell.classList.add('diff-empty');
+            timeCell.classList.add('diff-empty');
+            return;
+        }
+        sizeCell.textContent = isDir ? '' : formatSize(size);
+        timeCell.textContent = new Date(modified).toLocaleString();
+    }
+
+    addCopy(tr, entry, to, label, title) {
+        const cell = tr.insertCell();
+        cell.className = 'dircmp-copy';
+        const present = to === 'right' ? entry.leftSize >= 0 : entry.rightSize >= 0;
+        if (!present || entry.status === 'identical') return;
+        const btn = document.createElement('button');
+        btn.textContent = label;
+        btn.title = title;
+        btn.addEventListener('click', (e) => {
+            e.stopPropagation();
+            this.copy(entry, to);
+        });
+        cell.appendChild(btn);
+    }
+
+    async copy(entry, to) {
+        const [from, target] = to === 'right' ? [this.left, this.right] : [this.right, this.left];
+        const existing = to === 'right' ? entry.rightSize >= 0 : entry.leftSize >= 0;
+        if (existing && !confirm(`${entry.path} auf der ${to === 'right' ? 'rechten' : 'linken'} Seite ersetzen? Der bisherige Stand kommt in den Papierkorb.`)) {
+            return;
+        }
+        try {
+            let result = await CopyCompareEntry(from, target, entry.path, false);
+            // Ohne Papierkorb wird nur nach ausdrücklicher Bestätigung endgültig gelöscht
+            if (result.error?.op === 'trash' &&
+                confirm(`${result.error.message}\n\n${entry.path} kann nicht in den Papierkorb verschoben werden. Endgültig löschen und ersetzen?`)) {
+                result = await CopyCompareEntry(from, target, entry.path, true);
+            }
+            if (result.error) {
+                updateStatus(result.error.message, "error");
+                return;
+            }
+            updateStatus(`${entry.path} kopiert`);
+            this.refresh();
+        } catch (err) {
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    openDiff(entry) {
+        const join = (root, rel) => `${root}/${rel}`;
+        createNewTab(`${entry.name} ⇄`, 'StarteDiff', null, {
+            diff: {
+                left: { kind: 'file', path: join(this.left, entry.path) },
+                right: { kind: 'file', path: join(this.right, entry.path) }
+            }
+        });
+    }
+
+    show() {
+        this.panel.style.display = 'flex';
+        this.panel.focus();
+    }
+
+    dispose() {
+        this.panel.remove();
+    }
+}
+
+function formatSize(bytes) {
+    if (bytes < 1024) return `${bytes} B`;
+    if (bytes < 1024 * 1024) return `${(bytes / 1024).toFixed(1)} KB`;
+    return `${(bytes / 1024 / 1024).toFixed(1)} MB`;
+}
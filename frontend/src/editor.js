@@ -45,7 +45,19 @@ import { appState } from './state.js';
 import { createNewTab } from './tabManager.js';
 import { setAppTitle } from './ui.js';
 import { AiPanel } from './aipanel.js';
-import { SetUnsavedChanges, MarkFileAsUnsaved } from "../wailsjs/go/main/App.js";
+import { TerminalPanel } from './terminalPanel.js';
+import { DiffView } from './diffView.js';
+import { DirCompareView } from './dirCompareView.js';
+import { LargeFileView } from './largeFileView.js';
+import { LogView } from './logView.js';
+import { HexView } from './hexView.js';
+import { lspClient } from './lspClient.js';
+import { lspExtensions } from './lspEditor.js';
+import { diagnosticsField, diagnosticsStore, applyDiagnostics } from './diagnostics.js';
+import { coverageField, coverageStore, applyCoverage } from './coverage.js';
+import { debuggerExtensions, debugStore, applyDebugState } from './debugger.js';
+import { settingsStore } from './settings.js';
+import { SetUnsavedChanges, MarkFileAsUnsaved, GetEditorConfig } from "../wailsjs/go/main/App.js";
 import { formatWithCursor } from 'prettier';
 import * as prettierPluginBabel from 'prettier/plugins/babel';
 import * as prettierPluginEstree from 'prettier/plugins/estree';
@@ -302,6 +314,26 @@ const formatOnSave = keymap.of([
     },
 ]);
 
+// Einrückung nach EditorConfig (indent_style, indent_size, tab_width), sonst nach den Einstellungen
+function indentationExtensions(ec) {
+    const defaults = settingsStore.editor;
+    const tabWidth = ec?.tabWidth || ec?.indentSize || defaults.tab_size || 4;
+    const size = ec?.indentSize || tabWidth;
+    const style = ec?.indentStyle || defaults.indent_style;
+    const unit = style === 'tab' ? '\t' : ' '.repeat(size);
+    return [indentUnit.of(unit), EditorState.tabSize.of(tabWidth)];
+}
+
+// Schriftgröße, Zeilenumbruch und Zeilennummern aus den Einstellungen
+function viewSettingsExtensions() {
+    const { font_size = 14, word_wrap = true, line_numbers = true } = settingsStore.editor;
+    return [
+        word_wrap ? EditorView.lineWrapping : [],
+        line_numbers ? lineNumbers() : [],
+        EditorView.theme({ "&": { fontSize: `${font_size}px` } })
+    ];
+}
+
 // === EditorManager ===
 export class EditorManager {
     constructor() {
@@ -311,11 +343,15 @@ export class EditorManager {
         this.hasSelection = false;
         this.selectedText = '';
         this.languageCompartment = new Compartment();
+        this.indentCompartment = new Compartment();
+        this.viewSettingsCompartment = new Compartment();
 
         this.baseExtensions = [
-            EditorView.lineWrapping,
-            lineNumbers(),
+            ...debuggerExtensions,
+            this.viewSettingsCompartment.of(viewSettingsExtensions()),
             highlightLineField,
+            diagnosticsField,
+            coverageField,
             history(),
             indentOnInput(),
             bracketMatching(),
@@ -324,6 +360,7 @@ export class EditorManager {
             search(),
             highlightSelectionMatches(),
             this.languageCompartment.of([]),
+            lspExtensions((view) => appState.openTabs.get(this.panes.get(view.paneId)?.activeTabId)?.filePath),
             keymap.of([
                 indentWithTab,
                 { key: "Mod-f", run: openSearchPanel },
@@ -333,9 +370,9 @@ export class EditorManager {
                 { key: "Enter", run: insertNewlineAndIndent },
                 { key: "Mod-Enter", run: insertNewlineAndIndent },
             ]),
-            indentUnit.of("    "),
+            this.indentCompartment.of(indentationExtensions(null)),
             EditorView.theme({
-                "&": { fontSize: "14px", height: "100%" },
+                "&": { height: "100%" },
                 ".cm-content": { caretColor: "black" },
                 ".cm-scroller": { overflow: "auto" }
             }),
@@ -433,6 +470,44 @@ export class EditorManager {
             },
         ]);
 
+        // Neue Diagnosen in den Panes anzeigen, die die Datei gerade zeigen
+        diagnosticsStore.onChange((path) => {
+            for (const paneData of this.panes.values()) {
+                const tab = appState.openTabs.get(paneData.activeTabId);
+                if (tab?.filePath === path) {
+                    applyDiagnostics(paneData.view, path);
+                }
+            }
+        });
+
+        coverageStore.onChange((paths) => {
+            for (const paneData of this.panes.values()) {
+                const tab = appState.openTabs.get(paneData.activeTabId);
+                if (tab?.filePath && paths.includes(tab.filePath)) {
+                    applyCoverage(paneData.view, tab.filePath);
+                }
+            }
+        });
+
+        // Breakpoints und aktuelle Zeile des Debuggers
+        debugStore.onChange((kind, path) => {
+            for (const paneData of this.panes.values()) {
+                const tab = appState.openTabs.get(paneData.activeTabId);
+                if (tab?.type === 'editor' && (!path || tab.filePath === path)) {
+                    applyDebugState(paneData.view, tab.filePath);
+                }
+            }
+        });
+
+        // Geänderte Einstellungen sofort in allen Editoren übernehmen
+        settingsStore.onChange(() => {
+            for (const paneData of this.panes.values()) {
+                const tab = appState.openTabs.get(paneData.activeTabId);
+                if (paneData.view && tab?.type === 'editor') {
+                    this.applySettings(paneData.view, tab);
+                }
+            }
+        });
     }
 
     // Hilfsmethode für Fokus
@@ -578,19 +653,24 @@ export class EditorManager {
             if (existingPaneData.activeTabId === tabId) {
                 console.log(`Tab ${tabId} is already active in pane ${existingPaneId}, switching to that pane`);
                 // Fokus auf den bereits aktiven Tab setzen
-                this.updateUIFocus(tabId, existingPaneId, tabInfo.type === 'web', tabInfo.type === 'ai');
+                this.updateUIFocus(tabId, existingPaneId, tabInfo.type === 'web', tabInfo.type === 'ai', tabInfo.type === 'terminal');
                 return;
             }
         }
 
         const isWeb = tabInfo.type === 'web';
         const isAi = tabInfo.type === 'ai';
+        const isTerminal = tabInfo.type === 'terminal';
+        const isDiff = tabInfo.type === 'diff';
+        const isLarge = tabInfo.type === 'large';
+        const isLog = tabInfo.type === 'log';
+        const isHex = tabInfo.type === 'hex';
         const currentTabId = paneData.activeTabId;
 
         // --- 1. STATE DER VORHERIGEN TAB SPEICHERN ---
         if (currentTabId && currentTabId !== tabId) {
             const prevTabInfo = appState.openTabs.get(currentTabId);
-            if (prevTabInfo?.type !== 'web' && prevTabInfo?.type !== 'ai') {
+            if (prevTabInfo?.type === 'editor') {
                 // Nur für Standard-Editor-Tabs den CM6 State speichern
                 this.tabStates.set(currentTabId, {
                     state: paneData.view.state,
@@ -615,12 +695,94 @@ export class EditorManager {
             this.handleWebTab(tabId, tabInfo, paneData);
         } else if (isAi) {
             this.handleAiTab(tabId, tabInfo, paneData);
+        } else if (isTerminal) {
+            this.handleTerminalTab(tabId, tabInfo, paneData);
+        } else if (isDiff) {
+            this.handleDiffTab(tabId, tabInfo, paneData);
+        } else if (isLarge) {
+            this.handleLargeTab(tabId, tabInfo, paneData);
+        } else if (isLog) {
+            this.handleLogTab(tabId, tabInfo, paneData);
+        } else if (isHex) {
+            this.handleHexTab(tabId, tabInfo, paneData);
         } else {
             this.handleEditorTab(tabId, tabInfo, paneData, paneId);
         }
 
         // --- 4. SHARED UI UPDATES ---
-        this.updateUIFocus(tabId, paneId, isWeb, isAi);
+        this.updateUIFocus(tabId, paneId, isWeb, isAi, isTerminal);
+    }
+
+    handleDiffTab(tabId, tabInfo, paneData) {
+        if (paneData.view) {
+            paneData.view.dom.style.display = 'none';
+        }
+
+        if (!this.diffViews) this.diffViews = new Map();
+        const diffView = this.diffViews.get(tabId);
+        if (diffView) {
+            diffView.show();
+        } else {
+            // Ordnervergleiche nutzen denselben Tab-Typ
+            const View = tabInfo.diff.dirs ? DirCompareView : DiffView;
+            this.diffViews.set(tabId, new View(tabId, paneData, tabInfo.diff));
+        }
+    }
+
+    handleLargeTab(tabId, tabInfo, paneData) {
+        if (paneData.view) {
+            paneData.view.dom.style.display = 'none';
+        }
+
+        if (!this.largeViews) this.largeViews = new Map();
+        const largeView = this.largeViews.get(tabId);
+        if (largeView) {
+            largeView.show();
+        } else {
+            this.largeViews.set(tabId, new LargeFileView(tabId, paneData, tabInfo.large));
+        }
+    }
+
+    handleLogTab(tabId, tabInfo, paneData) {
+        if (paneData.view) {
+            paneData.view.dom.style.display = 'none';
+        }
+
+        if (!this.logViews) this.logViews = new Map();
+        const logView = this.logViews.get(tabId);
+        if (logView) {
+            logView.show();
+        } else {
+            this.logViews.set(tabId, new LogView(tabId, paneData, tabInfo.log));
+        }
+    }
+
+    handleHexTab(tabId, tabInfo, paneData) {
+        if (paneData.view) {
+            paneData.view.dom.style.display = 'none';
+        }
+
+        if (!this.hexViews) this.hexViews = new Map();
+        const hexView = this.hexViews.get(tabId);
+        if (hexView) {
+            hexView.show();
+        } else {
+            this.hexViews.set(tabId, new HexView(tabId, paneData, tabInfo.hex));
+        }
+    }
+
+    handleTerminalTab(tabId, tabInfo, paneData) {
+        if (paneData.view) {
+            paneData.view.dom.style.display = 'none';
+        }
+
+        if (!this.terminalPanels) this.terminalPanels = new Map();
+        const terminal = this.terminalPanels.get(tabId);
+        if (terminal) {
+            terminal.show();
+        } else {
+            this.terminalPanels.set(tabId, new TerminalPanel(tabId, paneData, tabInfo.cwd));
+        }
     }
 
     async handleAiTab(tabId, tabInfo, paneData) {
@@ -700,6 +862,39 @@ export class EditorManager {
         }
 
         paneData.view.setState(editorState);
+        // Gespeicherte Zustände können ältere Einstellungen enthalten
+        if (savedData?.state) this.applySettings(paneData.view, tabInfo);
+        else this.loadEditorConfig(tabId, paneData);
+        lspClient.documentOpened(tabInfo.filePath, editorState.doc.toString());
+        applyDiagnostics(paneData.view, tabInfo.filePath);
+        applyCoverage(paneData.view, tabInfo.filePath);
+        applyDebugState(paneData.view, tabInfo.filePath);
+    }
+
+    // Übernimmt Einrückung und Tabbreite aus der .editorconfig der Datei und die Einstellungen
+    async loadEditorConfig(tabId, paneData) {
+        // Der Pfad wird meist erst nach createNewTab gesetzt
+        await Promise.resolve();
+        const tab = appState.openTabs.get(tabId);
+        if (!tab) return;
+        if (tab.filePath) {
+            try {
+                tab.editorConfig ??= await GetEditorConfig(tab.filePath);
+            } catch (err) {
+                console.warn('GetEditorConfig:', err);
+            }
+        }
+        if (paneData.activeTabId !== tabId) return;
+        this.applySettings(paneData.view, tab);
+    }
+
+    applySettings(view, tab) {
+        view.dispatch({
+            effects: [
+                this.indentCompartment.reconfigure(indentationExtensions(tab?.editorConfig)),
+                this.viewSettingsCompartment.reconfigure(viewSettingsExtensions())
+            ]
+        });
     }
 
     hideIframes() {
@@ -715,6 +910,11 @@ export class EditorManager {
             panel.style.display = 'none';
         });
 
+        // Verstecke alle Terminals, Vergleiche, große Dateien, Logs und Hex-Ansichten
+        document.querySelectorAll('.terminal-panel, .diff-panel, .large-panel, .log-panel, .hex-panel').forEach(panel => {
+            panel.style.display = 'none';
+        });
+
         // Editor wieder anzeigen (wenn vorhanden)
         const activePane = this.getActivePane();
         if (activePane && activePane.view) {
@@ -722,7 +922,7 @@ export class EditorManager {
         }
     }
 
-    updateUIFocus(tabId, paneId, isWeb, isAi) {
+    updateUIFocus(tabId, paneId, isWeb, isAi, isTerminal = false) {
         const tabInfo = appState.openTabs.get(tabId);
         const paneData = this.panes.get(paneId);
 
@@ -753,6 +953,16 @@ export class EditorManager {
             if (aiInput) {
                 aiInput.focus();
             }
+        } else if (isTerminal) {
+            this.terminalPanels?.get(tabId)?.term.focus();
+        } else if (tabInfo.type === 'diff') {
+            this.diffViews?.get(tabId)?.panel.focus();
+        } else if (tabInfo.type === 'large') {
+            this.largeViews?.get(tabId)?.panel.focus();
+        } else if (tabInfo.type === 'log') {
+            this.logViews?.get(tabId)?.panel.focus();
+        } else if (tabInfo.type === 'hex') {
+            this.hexViews?.get(tabId)?.panel.focus();
         } else if (paneData.view) {
             // Fokus auf den CodeMirror 6 Editor
             // Wir nutzen ein minimales Timeout, um sicherzustellen, dass das DOM bereit ist
@@ -787,7 +997,10 @@ export class EditorManager {
 
     handleContentChange(content) {
         const tab = appState.getActiveTab();
-        if (tab) tab.lastContent = content;
+        if (tab) {
+            tab.lastContent = content;
+            lspClient.documentChanged(tab.filePath, content);
+        }
     }
 
     // ✅ Unified, debounced selection handling already in updateListener
@@ -1,8 +1,9 @@
 // File operations - depends only on state and editor
-import { SaveFile, LoadFile, SaveFileUnder, ReadFile } from "../wailsjs/go/main/App.js";
+import { SaveFile, LoadFile, SaveFileUnder, ReadFile, FormatBuffer, OpenMode } from "../wailsjs/go/main/App.js";
 import { APP_CONFIG } from './constants.js';
 import { appState, updateCurrentTabOnSave } from './state.js';
 import { editorManager } from './editor.js';
+import { openFileInMode } from './tabManager.js';
 import { updateStatus, setAppTitle } from './ui.js';
 
 // Konstanten für Standardwerte
@@ -16,6 +17,10 @@ const ERROR_MESSAGES = {
 export async function openFile() {
     try {
         const result = await LoadFile();
+        if (result.mode) {
+            openFileInMode(result.filename, result.mode);
+            return null;
+        }
         if (!result.filename || result.content === undefined) {
             updateStatus("Fehler: Keine Datei ausgewählt", "error");
             return null;
@@ -29,7 +34,9 @@ export async function openFile() {
         return {
             name: result.filename.split(/[/\\]/).pop() || APP_CONFIG.DEFAULT_TAB_NAME,
             path: result.filename,
-            content: result.content
+            content: result.content,
+            editorConfig: result.editorConfig,
+            charset: result.charset
         };
     } catch (e) {
         console.error('OpenFile error:', e);
@@ -59,6 +66,12 @@ function validateFilePaths(fname, fnamepath) {
 // Hauptfunktion zum Speichern der Datei
 export async function saveFile() {
   try {
+    const active = appState.getActiveTab();
+    if (active?.type === 'large' || active?.type === 'hex') {
+      const views = active.type === 'hex' ? editorManager.hexViews : editorManager.largeViews;
+      return views?.get(appState.activeTabId)?.save() ?? false;
+    }
+
     const content = editorManager.getValue();
 
     // Validierung des Inhalts
@@ -119,8 +132,14 @@ export async function saveFileUnder() {
     }
 }
 
+// Große und binäre Dateien bekommen einen eigenen Tab; dann wird null zurückgegeben
 export async function loadFileFromPath(path) {
     try {
+        const mode = await OpenMode(path);
+        if (mode) {
+            openFileInMode(path, mode);
+            return null;
+        }
         const content = await ReadFile(path);
         if (typeof content !== 'string') {
             updateStatus("Fehler: Ungültiger Dateiinhalt", "error");
@@ -136,4 +155,40 @@ export async function loadFileFromPath(path) {
         updateStatus(`Fehler beim Lesen: ${e.message || e}`, "error");
         return null;
     }
-}
\ No newline at end of file
+}
+
+// Formatiert den aktiven Puffer über das Backend (gofmt, black, prettier, ...)
+export async function formatActiveBuffer() {
+    const tab = appState.getActiveTab();
+    const view = editorManager.getActiveView();
+    if (!tab || !view) return false;
+
+    const path = tab.filePath || tab.fileName || DEFAULT_TAB_NAME;
+    const result = await FormatBuffer(path, view.state.doc.toString());
+    if (result.errors && result.errors.length > 0) {
+        showFormatErrors(result.errors);
+        return false;
+    }
+    if (result.changed) {
+        replaceBufferContent(view, result.content);
+    }
+    updateStatus("Formatiert", "success");
+    return true;
+}
+
+// Ersetzt den Inhalt eines Editors und behält die Cursorzeile möglichst bei
+export function replaceBufferContent(view, content) {
+    const head = view.state.selection.main.head;
+    const line = view.state.doc.lineAt(head).number;
+    view.dispatch({
+        changes: { from: 0, to: view.state.doc.length, insert: content }
+    });
+    const newLine = view.state.doc.line(Math.min(line, view.state.doc.lines));
+    view.dispatch({ selection: { anchor: newLine.from } });
+}
+
+export function showFormatErrors(errors) {
+    const first = errors[0];
+    const pos = first.line > 0 ? ` (Zeile ${first.line}, Spalte ${first.column})` : '';
+    updateStatus(`Formatierfehler${pos}: ${first.message}`, "error");
+}
@@ -0,0 +1,383 @@
+// Hex tab for binary files: offset, hex and ASCII columns, paged from the
+// backend. Edits go to the piece table in Go, so saving writes the exact bytes.
+import { OpenHexFile, HexRead, HexWrite, HexDelete, HexSearch, LargeFileSave, CloseLargeFile } from '../wailsjs/go/main/App.js';
+import { appState } from './state.js';
+import { updateTabTitle } from './tabManager.js';
+import { Logger } from './logger.js';
+import { updateStatus } from './ui.js';
+
+const BYTES_PER_ROW = 16;
+const ROW_HEIGHT = 18;   // Muss zu .hex-row passen
+
+const hex2 = (b) => b.toString(16).padStart(2, '0').toUpperCase();
+
+export class HexView {
+    /**
+     * @param {object} request - { path }
+     */
+    constructor(tabId, paneData, request) {
+        this.logger = new Logger('HexView');
+        this.tabId = tabId;
+        this.path = request.path;
+        this.info = null;
+        this.topRow = 0;
+        this.cursor = 0;          // Byte-Offset; info.size ist die Position hinter dem letzten Byte
+        this.column = 'hex';      // 'hex' oder 'ascii'
+        this.nibble = null;       // Erste getippte Hex-Ziffer
+        this.insert = false;
+        this.match = null;        // { offset, length } des letzten Suchtreffers
+        this.bytes = new Uint8Array(0);
+
+        this.panel = document.createElement('div');
+        this.panel.id = `hex-panel-${tabId}`;
+        this.panel.className = 'hex-panel';
+        paneData.dom.appendChild(this.panel);
+
+        this.createToolbar();
+        this.body = document.createElement('div');
+        this.body.className = 'large-body';
+        this.rows = document.createElement('div');
+        this.rows.className = 'hex-rows';
+        this.scroll = document.createElement('input');
+        this.scroll.type = 'range';
+        this.scroll.className = 'large-scroll';
+        this.scroll.min = 0;
+        this.scroll.value = 0;
+        this.body.append(this.rows, this.scroll);
+        this.status = document.createElement('div');
+        this.status.className = 'hex-status';
+        this.panel.append(this.body, this.status);
+
+        this.attachEvents();
+        this.resizeObserver = new ResizeObserver(() => this.render());
+        this.resizeObserver.observe(this.rows);
+        this.open();
+    }
+
+    createToolbar() {
+        const toolbar = document.createElement('div');
+        toolbar.className = 'test-toolbar hex-toolbar';
+        toolbar.innerHTML = `
+            <span class="diff-title"></span>
+            <input type="text" class="hex-goto" placeholder="Offset" title="Gehe zu Offset, dezimal oder 0x… (Enter)">
+            <input type="text" class="hex-query" placeholder="Bytes suchen, z. B. DE AD BE EF" title="Nächster Treffer (Enter)">
+            <label><input type="checkbox" data-option="text"> Text</label>
+            <button data-action="mode" title="Überschreiben/Einfügen (Einfg)">Überschreiben</button>
+            <button data-action="save" title="Änderungen speichern (Strg+S)" disabled>Speichern</button>
+        `;
+        this.panel.tabIndex = 0;
+        this.panel.appendChild(toolbar);
+        this.title = toolbar.querySelector('.diff-title');
+        this.saveButton = toolbar.querySelector('[data-action="save"]');
+        this.modeButton = toolbar.querySelector('[data-action="mode"]');
+        this.textSearch = toolbar.querySelector('[data-option="text"]');
+
+        const goto = toolbar.querySelector('.hex-goto');
+        goto.addEventListener('keydown', (e) => {
+            if (e.key !== 'Enter') return;
+            const value = goto.value.trim();
+            const offset = /^0x/i.test(value) ? parseInt(value.slice(2), 16) : parseInt(value, 10);
+            if (Number.isNaN(offset)) {
+                updateStatus("Ungültiger Offset", "error");
+                return;
+            }
+            this.moveTo(offset);
+            this.panel.focus();
+        });
+        const query = toolbar.querySelector('.hex-query');
+        query.addEventListener('keydown', (e) => {
+            if (e.key === 'Enter') this.search(query.value);
+        });
+        this.modeButton.addEventListener('click', () => this.toggleMode());
+        this.saveButton.addEventListener('click', () => this.save());
+    }
+
+    attachEvents() {
+        this.rows.addEventListener('wheel', (e) => {
+            e.preventDefault();
+            this.scrollTo(this.topRow + Math.sign(e.deltaY) * 3);
+        }, { passive: false });
+        this.scroll.addEventListener('input', () => this.scrollTo(Number(this.scroll.value)));
+        this.rows.addEventListener('mousedown', (e) => {
+            const cell = e.target.closest('[data-offset]');
+            if (!cell) return;
+            e.preventDefault();
+            this.column = cell.classList.contains('hex-char') ? 'ascii' : 'hex';
+            this.moveTo(Number(cell.dataset.offset));
+            this.panel.focus();
+        });
+        this.panel.addEventListener('keydown', (e) => {
+            if (e.target === this.panel) this.onKey(e);
+        });
+    }
+
+    async open() {
+        this.title.textContent = this.path;
+        this.title.title = this.path;
+        try {
+            this.setInfo(await OpenHexFile(this.path));
+            this.render();
+        } catch (err) {
+            this.logger.error('Open failed:', err);
+            this.rows.textContent = String(err);
+        }
+    }
+
+    setInfo(info) {
+        this.info = info;
+        this.title.textContent = `${info.path} – ${info.size.toLocaleString()} Bytes`;
+        this.scroll.max = Math.max(Math.floor(info.size / BYTES_PER_ROW), 0);
+        this.saveButton.disabled = !info.modified;
+        this.cursor = Math.min(this.cursor, info.size);
+
+        const tab = appState.openTabs.get(this.tabId);
+        if (tab && tab.dirty !== info.modified) {
+            tab.dirty = info.modified;
+            updateTabTitle(this.tabId);
+        }
+    }
+
+    visibleRows() {
+        return Math.max(Math.floor(this.rows.clientHeight / ROW_HEIGHT), 1);
+    }
+
+    scrollTo(row) {
+        if (!this.info) return;
+        const last = Math.max(Math.floor(this.info.size / BYTES_PER_ROW) - this.visibleRows() + 1, 0);
+        this.topRow = Math.min(Math.max(row, 0), last);
+        this.render();
+    }
+
+    // Setzt den Cursor und scrollt ihn in den sichtbaren Bereich
+    moveTo(offset) {
+        if (!this.info) return;
+        this.cursor = Math.min(Math.max(offset, 0), this.info.size);
+        this.nibble = null;
+        const row = Math.floor(this.cursor / BYTES_PER_ROW);
+        if (row < this.topRow) {
+            this.scrollTo(row);
+        } else if (row >= this.topRow + this.visibleRows()) {
+            this.scrollTo(row - this.visibleRows() + 1);
+        } else {
+            this.render();
+        }
+    }
+
+    async render() {
+        if (!this.info || this.rows.clientHeight === 0) return;
+        const top = this.topRow;
+        const start = top * BYTES_PER_ROW;
+        let data;
+        try {
+            data = await HexRead(this.info.id, start, this.visibleRows() * BYTES_PER_ROW);
+        } catch (err) {
+            updateStatus(`Fehler beim Lesen: ${err}`, "error");
+            return;
+        }
+        // Eine spätere Anfrage hat schon gezeichnet
+        if (top !== this.topRow) return;
+        this.bytes = Uint8Array.from(data.match(/../g) || [], h => parseInt(h, 16));
+        this.scroll.value = top;
+
+        const digits = Math.max(this.info.size.toString(16).length, 8);
+        const rows = [];
+        for (let r = 0; r < this.visibleRows(); r++) {
+            const rowStart = start + r * BYTES_PER_ROW;
+            if (rowStart > this.info.size) break;
+            rows.push(this.renderRow(rowStart, start, digits));
+        }
+        this.rows.replaceChildren(...rows);
+        this.updateStatusLine();
+    }
+
+    renderRow(rowStart, windowStart, digits) {
+        const row = document.createElement('div');
+        row.className = 'hex-row';
+        const offset = document.createElement('span');
+        offset.className = 'hex-offset';
+        offset.textContent = rowStart.toString(16).toUpperCase().padStart(digits, '0');
+        const hexCol = document.createElement('span');
+        hexCol.className = 'hex-bytes';
+        const asciiCol = document.createElement('span');
+        asciiCol.className = 'hex-ascii';
+
+        for (let i = 0; i < BYTES_PER_ROW; i++) {
+            const pos = rowStart + i;
+            // Die Position hinter dem letzten Byte ist zum Anhängen wählbar
+            if (pos > this.info.size) break;
+            const b = this.bytes[pos - windowStart];
+            const cell = document.createElement('span');
+            cell.className = 'hex-byte';
+            cell.dataset.offset = pos;
+            const char = document.createElement('span');
+            char.className = 'hex-char';
+            char.dataset.offset = pos;
+            if (pos === this.info.size) {
+                cell.textContent = '__';
+                char.textContent = ' ';
+                cell.classList.add('hex-end');
+            } else {
+                cell.textContent = hex2(b);
+                char.textContent = b >= 0x20 && b < 0x7f ? String.fromCharCode(b) : '.';
+            }
+            if (pos === this.cursor) {
+                cell.classList.add('hex-cursor', ...(this.column === 'hex' ? ['hex-cursor-active'] : []));
+                char.classList.add('hex-cursor', ...(this.column === 'ascii' ? ['hex-cursor-active'] : []));
+                if (this.nibble !== null) cell.textContent = this.nibble.toString(16).toUpperCase() + '_';
+            }
+            if (this.match && pos >= this.match.offset && pos < this.match.offset + this.match.length) {
+                cell.classList.add('hex-match');
+                char.classList.add('hex-match');
+            }
+            hexCol.appendChild(cell);
+            asciiCol.appendChild(char);
+        }
+        row.append(offset, hexCol, asciiCol);
+        return row;
+    }
+
+    updateStatusLine() {
+        const pos = this.cursor;
+        let text = `Offset 0x${pos.toString(16).toUpperCase()} (${pos})`;
+        const windowStart = this.topRow * BYTES_PER_ROW;
+        const b = this.bytes[pos - windowStart];
+        if (pos < this.info.size && b !== undefined) {
+            text += ` · Wert 0x${hex2(b)} (${b})`;
+        }
+        text += ` · ${this.insert ? 'Einfügen' : 'Überschreiben'}`;
+        this.status.textContent = text;
+    }
+
+    toggleMode() {
+        this.insert = !this.insert;
+        this.modeButton.textContent = this.insert ? 'Einfügen' : 'Überschreiben';
+        this.updateStatusLine();
+    }
+
+    onKey(e) {
+        if (!this.info) return;
+        const page = this.visibleRows() * BYTES_PER_ROW;
+        const rowStart = this.cursor - this.cursor % BYTES_PER_ROW;
+        const moves = {
+            ArrowLeft: this.cursor - 1,
+            ArrowRight: this.cursor + 1,
+            ArrowUp: this.cursor - BYTES_PER_ROW,
+            ArrowDown: this.cursor + BYTES_PER_ROW,
+            PageUp: this.cursor - page,
+            PageDown: this.cursor + page,
+            Home: e.ctrlKey ? 0 : rowStart,
+            End: e.ctrlKey ? this.info.size : rowStart + BYTES_PER_ROW - 1
+        };
+        if (e.key in moves) {
+            e.preventDefault();
+            this.moveTo(moves[e.key]);
+            return;
+        }
+        if (e.ctrlKey || e.metaKey || e.altKey) return;
+
+        switch (e.key) {
+            case 'Tab':
+                e.preventDefault();
+                this.column = this.column === 'hex' ? 'ascii' : 'hex';
+                this.nibble = null;
+                this.render();
+                return;
+            case 'Insert':
+                e.preventDefault();
+                this.toggleMode();
+                return;
+            case 'Delete':
+                e.preventDefault();
+                if (this.cursor < this.info.size) this.remove(this.cursor);
+                return;
+            case 'Backspace':
+                e.preventDefault();
+                if (this.cursor > 0) this.remove(this.cursor - 1);
+                return;
+            case 'Escape':
+                this.nibble = null;
+                this.render();
+                return;
+        }
+
+        if (e.key.length !== 1) return;
+        e.preventDefault();
+        if (this.column === 'ascii') {
+            const bytes = new TextEncoder().encode(e.key);
+            this.write(Array.from(bytes, hex2).join(''), bytes.length);
+            return;
+        }
+        const digit = parseInt(e.key, 16);
+        if (Number.isNaN(digit)) return;
+        if (this.nibble === null) {
+            this.nibble = digit;
+            this.render();
+        } else {
+            const value = this.nibble << 4 | digit;
+            this.nibble = null;
+            this.write(hex2(value), 1);
+        }
+    }
+
+    async write(hex, length) {
+        try {
+            this.setInfo(await HexWrite(this.info.id, this.cursor, hex, this.insert));
+            this.moveTo(this.cursor + length);
+        } catch (err) {
+            updateStatus(String(err), "error");
+        }
+    }
+
+    async remove(offset) {
+        try {
+            this.setInfo(await HexDelete(this.info.id, offset, 1));
+            this.moveTo(offset);
+        } catch (err) {
+            updateStatus(String(err), "error");
+        }
+    }
+
+    async search(pattern) {
+        if (!this.info || !pattern.trim()) return;
+        const text = this.textSearch.checked;
+        try {
+            const found = await HexSearch(this.info.id, pattern, { text, from: this.cursor + 1 });
+            if (found < 0) {
+                this.match = null;
+                updateStatus("Nicht gefunden", "error");
+                this.render();
+                return;
+            }
+            const length = text ? new TextEncoder().encode(pattern).length : pattern.replace(/\s+|^0x/gi, '').length / 2;
+            this.match = { offset: found, length };
+            if (found <= this.cursor) updateStatus("Suche am Dateianfang fortgesetzt");
+            this.moveTo(found);
+        } catch (err) {
+            updateStatus(String(err), "error");
+        }
+    }
+
+    async save() {
+        if (!this.info?.modified) return true;
+        try {
+            this.setInfo(await LargeFileSave(this.info.id));
+            updateStatus("Datei erfolgreich gespeichert!", "success");
+            this.render();
+            return true;
+        } catch (err) {
+            updateStatus(String(err), "error");
+            return false;
+        }
+    }
+
+    show() {
+        this.panel.style.display = 'flex';
+        this.panel.focus();
+    }
+
+    dispose() {
+        this.resizeObserver.disconnect();
+        if (this.info) CloseLargeFile(this.info.id);
+        this.panel.remove();
+    }
+}
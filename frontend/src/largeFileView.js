@@ -0,0 +1,298 @@
+// Large file tab: shows a window of lines fetched from the backend, so the
+// file is never loaded into the editor. Editing works line by line.
+import { OpenLargeFile, LargeFileLines, LargeFileSearch, LargeFileEdit, LargeFileSave, CloseLargeFile } from '../wailsjs/go/main/App.js';
+import { appState } from './state.js';
+import { updateTabTitle } from './tabManager.js';
+import { Logger } from './logger.js';
+import { updateStatus } from './ui.js';
+
+const LINE_HEIGHT = 18;   // Muss zu .large-line passen
+const MAX_RESULTS = 1000;
+
+export class LargeFileView {
+    /**
+     * @param {object} request - { path }
+     */
+    constructor(tabId, paneData, request) {
+        this.logger = new Logger('LargeFileView');
+        this.tabId = tabId;
+        this.path = request.path;
+        this.info = null;
+        this.top = 0;          // Erste sichtbare Zeile (0-basiert)
+        this.marked = -1;      // Zeile des ausgewählten Treffers
+        this.editing = false;
+        this.window = null;
+
+        this.panel = document.createElement('div');
+        this.panel.id = `large-panel-${tabId}`;
+        this.panel.className = 'large-panel';
+        paneData.dom.appendChild(this.panel);
+
+        this.createToolbar();
+        this.body = document.createElement('div');
+        this.body.className = 'large-body';
+        this.lines = document.createElement('div');
+        this.lines.className = 'large-lines';
+        this.scroll = document.createElement('input');
+        this.scroll.type = 'range';
+        this.scroll.className = 'large-scroll';
+        this.scroll.min = 0;
+        this.scroll.value = 0;
+        this.body.append(this.lines, this.scroll);
+        this.results = document.createElement('div');
+        this.results.className = 'large-results hidden';
+        this.panel.append(this.body, this.results);
+
+        this.attachEvents();
+        this.resizeObserver = new ResizeObserver(() => this.render());
+        this.resizeObserver.observe(this.lines);
+        this.open();
+    }
+
+    createToolbar() {
+        const toolbar = document.createElement('div');
+        toolbar.className = 'test-toolbar large-toolbar';
+        toolbar.innerHTML = `
+            <span class="diff-title"></span>
+            <input type="number" class="large-goto" min="1" placeholder="Zeile" title="Gehe zu Zeile (Enter)">
+            <input type="text" class="large-query" placeholder="Suchen" title="In der ganzen Datei suchen (Enter)">
+            <label><input type="checkbox" data-option="regex"> Regex</label>
+            <label><input type="checkbox" data-option="caseSensitive"> Groß/klein</label>
+            <label title="Zeilen per Doppelklick bearbeiten"><input type="checkbox" data-option="edit"> Bearbeiten</label>
+            <button data-action="save" title="Änderungen speichern (Strg+S)" disabled>Speichern</button>
+        `;
+        this.panel.tabIndex = 0;
+        this.panel.appendChild(toolbar);
+        this.title = toolbar.querySelector('.diff-title');
+        this.saveButton = toolbar.querySelector('[data-action="save"]');
+        this.regex = toolbar.querySelector('[data-option="regex"]');
+        this.caseSensitive = toolbar.querySelector('[data-option="caseSensitive"]');
+
+        const goto = toolbar.querySelector('.large-goto');
+        goto.addEventListener('keydown', (e) => {
+            if (e.key !== 'Enter') return;
+            const line = parseInt(goto.value, 10);
+            if (line > 0) this.goTo(line - 1);
+        });
+        const query = toolbar.querySelector('.large-query');
+        query.addEventListener('keydown', (e) => {
+            if (e.key === 'Enter') this.search(query.value);
+        });
+        toolbar.querySelector('[data-option="edit"]').addEventListener('change', (e) => {
+            this.editing = e.target.checked;
+            this.panel.classList.toggle('large-editing', this.editing);
+        });
+        this.saveButton.addEventListener('click', () => this.save());
+    }
+
+    attachEvents() {
+        this.lines.addEventListener('wheel', (e) => {
+            e.preventDefault();
+            this.scrollTo(this.top + Math.sign(e.deltaY) * 3);
+        }, { passive: false });
+        this.scroll.addEventListener('input', () => this.scrollTo(Number(this.scroll.value)));
+        this.panel.addEventListener('keydown', (e) => {
+            if (e.target !== this.panel) return;
+            const page = Math.max(this.visibleLines() - 1, 1);
+            const moves = {
+                ArrowUp: -1, ArrowDown: 1, PageUp: -page, PageDown: page
+            };
+            if (e.key in moves) {
+                e.preventDefault();
+                this.scrollTo(this.top + moves[e.key]);
+            } else if (e.ctrlKey && e.key === 'Home') {
+                e.preventDefault();
+                this.scrollTo(0);
+            } else if (e.ctrlKey && e.key === 'End') {
+                e.preventDefault();
+                this.scrollTo(this.info.lines);
+            }
+        });
+        this.lines.addEventListener('dblclick', (e) => {
+            const row = e.target.closest('.large-line');
+            if (row && this.editing) this.editLine(Number(row.dataset.line), row);
+        });
+    }
+
+    async open() {
+        this.title.textContent = `${this.path} (wird indiziert …)`;
+        try {
+            this.setInfo(await OpenLargeFile(this.path));
+            this.render();
+        } catch (err) {
+            this.logger.error('Open failed:', err);
+            this.title.textContent = this.path;
+            this.lines.textContent = String(err);
+        }
+    }
+
+    setInfo(info) {
+        this.info = info;
+        const mb = (info.size / (1024 * 1024)).toFixed(1);
+        this.title.textContent = `${info.path} – ${info.lines.toLocaleString()} Zeilen, ${mb} MB`;
+        this.title.title = info.path;
+        this.scroll.max = Math.max(info.lines - 1, 0);
+        this.saveButton.disabled = !info.modified;
+
+        const tab = appState.openTabs.get(this.tabId);
+        if (tab && tab.dirty !== info.modified) {
+            tab.dirty = info.modified;
+            updateTabTitle(this.tabId);
+        }
+    }
+
+    visibleLines() {
+        return Math.max(Math.floor(this.lines.clientHeight / LINE_HEIGHT), 1);
+    }
+
+    scrollTo(line) {
+        if (!this.info) return;
+        const last = Math.max(this.info.lines - this.visibleLines(), 0);
+        const top = Math.min(Math.max(line, 0), last);
+        if (top === this.top && this.window) return;
+        this.top = top;
+        this.render();
+    }
+
+    goTo(line) {
+        if (!this.info) return;
+        this.marked = Math.min(line, this.info.lines - 1);
+        // Zeile mittig anzeigen
+        this.top = -1;
+        this.scrollTo(this.marked - Math.floor(this.visibleLines() / 2));
+    }
+
+    async render() {
+        if (!this.info || this.lines.clientHeight === 0) return;
+        const top = this.top;
+        let win;
+        try {
+            win = await LargeFileLines(this.info.id, top, this.visibleLines());
+        } catch (err) {
+            updateStatus(`Fehler beim Lesen: ${err}`, "error");
+            return;
+        }
+        // Eine spätere Anfrage hat schon gezeichnet
+        if (top !== this.top) return;
+        this.window = win;
+        this.scroll.value = top;
+
+        const digits = String(win.total).length;
+        const truncated = new Set(win.truncated || []);
+        this.lines.replaceChildren(...win.lines.map((text, i) => {
+            const line = win.start + i;
+            const row = document.createElement('div');
+            row.className = 'large-line';
+            row.dataset.line = line;
+            row.classList.toggle('large-marked', line === this.marked);
+            row.classList.toggle('large-truncated', truncated.has(line));
+
+            const num = document.createElement('span');
+            num.className = 'large-num';
+            num.textContent = String(line + 1).padStart(digits);
+            const content = document.createElement('span');
+            content.className = 'large-text';
+            content.textContent = text;
+            row.append(num, content);
+            return row;
+        }));
+    }
+
+    editLine(line, row) {
+        if (this.window?.truncated?.includes(line)) {
+            updateStatus("Die Zeile ist zu lang zum Bearbeiten", "error");
+            return;
+        }
+        const text = row.querySelector('.large-text');
+        const input = document.createElement('textarea');
+        input.className = 'large-input';
+        input.value = text.textContent;
+        input.rows = 1;
+        text.replaceWith(input);
+        input.focus();
+
+        let done = false;
+        const finish = async (commit) => {
+            if (done) return;
+            done = true;
+            if (commit && input.value !== text.textContent) {
+                try {
+                    // Umbrüche im Feld werden zu neuen Zeilen
+                    this.setInfo(await LargeFileEdit(this.info.id, line, 1, input.value));
+                } catch (err) {
+                    updateStatus(`Fehler beim Bearbeiten: ${err}`, "error");
+                }
+            }
+            this.window = null;
+            this.render();
+            this.panel.focus();
+        };
+        input.addEventListener('keydown', (e) => {
+            if (e.key === 'Enter' && !e.shiftKey) {
+                e.preventDefault();
+                finish(true);
+            } else if (e.key === 'Escape') {
+                finish(false);
+            }
+        });
+        input.addEventListener('blur', () => finish(true));
+    }
+
+    async search(query) {
+        if (!this.info || !query) return;
+        updateStatus("Suche …");
+        let matches;
+        try {
+            matches = await LargeFileSearch(this.info.id, query, {
+                regex: this.regex.checked,
+                caseSensitive: this.caseSensitive.checked,
+                maxResults: MAX_RESULTS
+            });
+        } catch (err) {
+            updateStatus(String(err), "error");
+            return;
+        }
+        const more = matches.length === MAX_RESULTS ? '+' : '';
+        updateStatus(`${matches.length}${more} Treffer`);
+
+        this.results.classList.remove('hidden');
+        this.results.replaceChildren(...matches.map(match => {
+            const item = document.createElement('div');
+            item.className = 'large-result';
+            const num = document.createElement('span');
+            num.className = 'large-num';
+            num.textContent = match.line + 1;
+            const text = document.createElement('span');
+            text.textContent = match.text;
+            item.append(num, text);
+            item.addEventListener('click', () => this.goTo(match.line));
+            return item;
+        }));
+        if (matches.length > 0) this.goTo(matches[0].line);
+    }
+
+    async save() {
+        if (!this.info?.modified) return true;
+        try {
+            this.setInfo(await LargeFileSave(this.info.id));
+            updateStatus("Datei erfolgreich gespeichert!", "success");
+            this.window = null;
+            this.render();
+            return true;
+        } catch (err) {
+            updateStatus(String(err), "error");
+            return false;
+        }
+    }
+
+    show() {
+        this.panel.style.display = 'flex';
+        this.panel.focus();
+    }
+
+    dispose() {
+        this.resizeObserver.disconnect();
+        if (this.info) CloseLargeFile(this.info.id);
+        this.panel.remove();
+    }
+}
@@ -34,6 +34,16 @@ import {
     SquareFunction,
     Pyramid,
     Minimize2,
+    TriangleAlert,
+    SquareTerminal,
+    Play,
+    FlaskConical,
+    Bug,
+    History,
+    Pause,
+    Redo,
+    ArrowDownToLine,
+    ArrowUpFromLine,
     createElement
 } from '../../node_modules/lucide/dist/esm/lucide.js';
 
@@ -72,7 +82,17 @@ export function renderIcon(iconName, options = {}) {
         Hash,
         SquareFunction,
         Pyramid,
-        Minimize2
+        Minimize2,
+        TriangleAlert,
+        SquareTerminal,
+        Play,
+        FlaskConical,
+        Bug,
+        History,
+        Pause,
+        Redo,
+        ArrowDownToLine,
+        ArrowUpFromLine
     };
 
     const iconDef = iconMap[iconName];
@@ -0,0 +1,175 @@
+// Log tab: follows a log file; filtering happens in the backend, the view
+// only receives matching lines
+import { OpenLog, PauseLog, CloseLog } from '../wailsjs/go/main/App.js';
+import { EventsOn } from '../wailsjs/runtime/runtime.js';
+import { Logger } from './logger.js';
+import { updateStatus } from './ui.js';
+
+const LEVELS = ['FATAL', 'ERROR', 'WARN', 'INFO', 'DEBUG', 'TRACE'];
+const MAX_ROWS = 20000;   // Ältere Zeilen werden verworfen
+
+export class LogView {
+    /**
+     * @param {object} request - { path }
+     */
+    constructor(tabId, paneData, request) {
+        this.logger = new Logger('LogView');
+        this.tabId = tabId;
+        this.path = request.path;
+        this.sessionId = null;
+        this.following = true;
+        this.filter = { pattern: '', caseSensitive: false, levels: [] };
+
+        this.panel = document.createElement('div');
+        this.panel.id = `log-panel-${tabId}`;
+        this.panel.className = 'log-panel';
+        paneData.dom.appendChild(this.panel);
+
+        this.createToolbar();
+        this.body = document.createElement('div');
+        this.body.className = 'log-body';
+        this.panel.appendChild(this.body);
+
+        this.offLines = EventsOn('log-lines', (batch) => this.onBatch(batch));
+        this.open();
+    }
+
+    createToolbar() {
+        const toolbar = document.createElement('div');
+        toolbar.className = 'test-toolbar log-toolbar';
+        toolbar.innerHTML = `
+            <span class="diff-title"></span>
+            <input type="text" class="log-pattern" placeholder="Filter (Regex)" title="Enter zum Anwenden">
+            <label><input type="checkbox" data-option="caseSensitive"> Groß/klein</label>
+            ${LEVELS.map(l => `<button class="log-level-toggle log-${l.toLowerCase()}" data-level="${l}" title="Nur ausgewählte Level anzeigen">${l}</button>`).join('')}
+            <label title="Neue Zeilen anhängen und ans Ende scrollen"><input type="checkbox" data-option="follow" checked> Folgen</label>
+            <button data-action="clear" title="Angezeigte Zeilen entfernen">Leeren</button>
+            <span class="log-count"></span>
+        `;
+        this.panel.tabIndex = 0;
+        this.panel.appendChild(toolbar);
+        this.title = toolbar.querySelector('.diff-title');
+        this.count = toolbar.querySelector('.log-count');
+
+        const pattern = toolbar.querySelector('.log-pattern');
+        pattern.addEventListener('keydown', (e) => {
+            if (e.key !== 'Enter') return;
+            this.filter.pattern = pattern.value;
+            this.open();
+        });
+        toolbar.querySelector('[data-option="caseSensitive"]').addEventListener('change', (e) => {
+            this.filter.caseSensitive = e.target.checked;
+            if (this.filter.pattern) this.open();
+        });
+        toolbar.querySelectorAll('[data-level]').forEach(btn => {
+            btn.addEventListener('click', () => {
+                btn.classList.toggle('active');
+                this.filter.levels = [...toolbar.querySelectorAll('[data-level].active')].map(b => b.dataset.level);
+                this.open();
+            });
+        });
+        toolbar.querySelector('[data-option="follow"]').addEventListener('change', (e) => {
+            this.following = e.target.checked;
+            if (this.sessionId) PauseLog(this.sessionId, !this.following);
+            if (this.following) this.scrollToEnd();
+        });
+        toolbar.querySelector('[data-action="clear"]').addEventListener('click', () => {
+            this.body.replaceChildren();
+            this.updateCount();
+        });
+    }
+
+    // Startet die Sitzung neu, z. B. nach einer Filteränderung
+    async open() {
+        this.close();
+        this.title.textContent = this.path;
+        this.title.title = this.path;
+        try {
+            const session = await OpenLog(this.path, this.filter);
+            this.sessionId = session.id;
+            if (!this.following) PauseLog(session.id, true);
+            this.body.replaceChildren();
+            this.append(session.lines);
+        } catch (err) {
+            this.logger.error('Open failed:', err);
+            updateStatus(String(err), "error");
+        }
+    }
+
+    close() {
+        if (this.sessionId) CloseLog(this.sessionId);
+        this.sessionId = null;
+    }
+
+    onBatch(batch) {
+        if (batch.id !== this.sessionId) return;
+        if (batch.error) {
+            updateStatus(`${this.path}: ${batch.error}`, "error");
+        }
+        if (batch.reset) {
+            const marker = document.createElement('div');
+            marker.className = 'log-reset';
+            marker.textContent = batch.reset === 'rotated' ? '— Datei wurde rotiert —' : '— Datei wurde gekürzt —';
+            this.body.appendChild(marker);
+        }
+        this.append(batch.lines);
+    }
+
+    append(lines) {
+        if (!lines?.length) return;
+        const fragment = document.createDocumentFragment();
+        for (const line of lines) {
+            fragment.appendChild(this.renderLine(line));
+        }
+        this.body.appendChild(fragment);
+        const excess = this.body.childElementCount - MAX_ROWS;
+        for (let i = 0; i < excess; i++) {
+            this.body.firstElementChild.remove();
+        }
+        this.updateCount();
+        if (this.following) this.scrollToEnd();
+    }
+
+    renderLine(line) {
+        const row = document.createElement('div');
+        row.className = 'log-line';
+        if (line.level) row.classList.add(`log-${line.level.toLowerCase()}`);
+
+        const num = document.createElement('span');
+        num.className = 'log-num';
+        num.textContent = line.number;
+        row.appendChild(num);
+
+        // Zeitstempel hervorheben
+        const at = line.time ? line.text.indexOf(line.time) : -1;
+        if (at >= 0) {
+            const time = document.createElement('span');
+            time.className = 'log-time';
+            time.textContent = line.time;
+            row.append(line.text.slice(0, at), time, line.text.slice(at + line.time.length));
+        } else {
+            row.append(line.text);
+        }
+        return row;
+    }
+
+    updateCount() {
+        this.count.textContent = `${this.body.querySelectorAll('.log-line').length} Zeilen`;
+    }
+
+    scrollToEnd() {
+        this.body.scrollTop = this.body.scrollHeight;
+    }
+
+    show() {
+        this.panel.style.display = 'flex';
+        this.panel.focus();
+        if (this.following) this.scrollToEnd();
+    }
+
+    dispose() {
+        this.offLines();
+        this.close();
+        this.panel.remove();
+    }
+}
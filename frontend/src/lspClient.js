@@ -0,0 +1,131 @@
+// Keeps open buffers in sync with the language servers managed by the Go backend
+import { EventsOn } from "../wailsjs/runtime/runtime.js";
+import {
+    LSPOpenDocument,
+    LSPChangeDocument,
+    LSPCloseDocument,
+    LSPCompletion,
+    LSPHover,
+    LSPDefinition,
+    LSPReferences,
+    LSPRename
+} from "../wailsjs/go/main/App.js";
+import { updateStatus } from './ui.js';
+
+// Extensions the backend has a language server for (see lspServers in lsp.go)
+const LSP_EXTENSIONS = ['go', 'js', 'mjs', 'cjs', 'jsx', 'ts', 'tsx', 'py'];
+const CHANGE_DEBOUNCE_MS = 300;
+// Fehler beim Start des Servers; andere Fehler betreffen nur eine Anfrage
+const START_ERROR = /nicht gefunden|konnte nicht gestartet werden|Initialisierung von .* fehlgeschlagen|Kein Language Server/;
+
+class LspClient {
+    constructor() {
+        this.openDocuments = new Set();
+        this.unavailable = new Set();   // Extensions whose server could not be started
+        this.changeTimers = new Map();  // path -> { timer, content } der noch nicht gesendeten Änderung
+        this.diagnostics = new Map();   // path -> LSPDiagnostic[]
+        this.listeners = [];
+
+        EventsOn('lsp-diagnostics', ({ path, diagnostics }) => {
+            this.diagnostics.set(path, diagnostics);
+            this.listeners.forEach(cb => cb(path, diagnostics));
+
+            const errors = diagnostics.filter(d => d.severity === 1).length;
+            const warnings = diagnostics.filter(d => d.severity === 2).length;
+            if (errors || warnings) {
+                updateStatus(`${path.split(/[/\\]/).pop()}: ${errors} Fehler, ${warnings} Warnungen`, errors ? "error" : "success");
+            }
+        });
+    }
+
+    isSupported(path) {
+        if (!path) return false;
+        // Remote-Dateien, Archiveinträge und verschlüsselte Dateien kennt der Server nicht
+        if (path.startsWith('sftp://') || path.includes('!/') || path.toLowerCase().endsWith('.age')) return false;
+        const ext = path.split('.').pop().toLowerCase();
+        return LSP_EXTENSIONS.includes(ext) && !this.unavailable.has(ext);
+    }
+
+    handleError(path, error) {
+        console.warn('LSP:', error);
+        if (START_ERROR.test(`${error}`)) {
+            this.unavailable.add(path.split('.').pop().toLowerCase());
+        }
+    }
+
+    async documentOpened(path, content) {
+        if (!this.isSupported(path) || this.openDocuments.has(path)) return;
+        this.openDocuments.add(path);
+        try {
+            await LSPOpenDocument(path, content);
+        } catch (e) {
+            this.openDocuments.delete(path);
+            this.handleError(path, e);
+        }
+    }
+
+    documentChanged(path, content) {
+        if (!this.isSupported(path)) return;
+        clearTimeout(this.changeTimers.get(path)?.timer);
+        const timer = setTimeout(() => this.flush(path), CHANGE_DEBOUNCE_MS);
+        this.changeTimers.set(path, { timer, content });
+    }
+
+    // Sendet eine noch wartende Änderung sofort, damit Anfragen den aktuellen Text sehen
+    async flush(path) {
+        const pending = this.changeTimers.get(path);
+        if (!pending) return;
+        clearTimeout(pending.timer);
+        this.changeTimers.delete(path);
+        this.openDocuments.add(path);
+        try {
+            await LSPChangeDocument(path, pending.content);
+        } catch (e) {
+            this.handleError(path, e);
+        }
+    }
+
+    documentClosed(path) {
+        if (!this.openDocuments.has(path)) return;
+        this.openDocuments.delete(path);
+        this.diagnostics.delete(path);
+        clearTimeout(this.changeTimers.get(path)?.timer);
+        this.changeTimers.delete(path);
+        LSPCloseDocument(path).catch(e => console.warn('LSP:', e));
+    }
+
+    onDiagnostics(callback) {
+        this.listeners.push(callback);
+    }
+
+    getDiagnostics(path) {
+        return this.diagnostics.get(path) || [];
+    }
+
+    async completion(path, line, character) {
+        await this.flush(path);
+        return LSPCompletion(path, line, character);
+    }
+
+    async hover(path, line, character) {
+        await this.flush(path);
+        return LSPHover(path, line, character);
+    }
+
+    async definition(path, line, character) {
+        await this.flush(path);
+        return LSPDefinition(path, line, character);
+    }
+
+    async references(path, line, character) {
+        await this.flush(path);
+        return LSPReferences(path, line, character);
+    }
+
+    async rename(path, line, character, newName) {
+        await this.flush(path);
+        return LSPRename(path, line, character, newName);
+    }
+}
+
+export const lspClient = new LspClient();
@@ -0,0 +1,80 @@
+// Completion and hover from the language server in the CodeMirror editor
+import { hoverTooltip } from '@codemirror/view';
+import { autocompletion } from '@codemirror/autocomplete';
+import { lspClient } from './lspClient.js';
+
+// LSP CompletionItemKind -> CodeMirror completion type (Icon in der Liste)
+const COMPLETION_TYPES = {
+    2: 'method', 3: 'function', 4: 'function', 5: 'property', 6: 'variable',
+    7: 'class', 8: 'interface', 9: 'namespace', 10: 'property', 13: 'enum',
+    14: 'keyword', 20: 'constant', 21: 'constant', 22: 'class', 25: 'type'
+};
+
+// Zeile und Spalte (0-basiert, UTF-16 wie im LSP) einer Position im Dokument
+export function lspPosition(state, pos) {
+    const line = state.doc.lineAt(pos);
+    return { line: line.number - 1, character: pos - line.from };
+}
+
+/**
+ * Extensions für Vervollständigung und Hover. getPath liefert den Dateipfad
+ * des Tabs, der in einer View angezeigt wird.
+ */
+export function lspExtensions(getPath) {
+    const completionSource = async (context) => {
+        const path = getPath(context.view);
+        if (!lspClient.isSupported(path)) return null;
+        const word = context.matchBefore(/[\w$]*/);
+        const afterDot = context.state.sliceDoc(word.from - 1, word.from) === '.';
+        if (word.from === word.to && !afterDot && !context.explicit) return null;
+
+        const { line, character } = lspPosition(context.state, context.pos);
+        let items;
+        try {
+            items = await lspClient.completion(path, line, character);
+        } catch (e) {
+            lspClient.handleError(path, e);
+            return null;
+        }
+        if (context.aborted || !items?.length) return null;
+        return {
+            from: word.from,
+            options: items.map(item => ({
+                label: item.label,
+                apply: item.insertText || item.label,
+                type: COMPLETION_TYPES[item.kind],
+                detail: item.detail || undefined,
+                info: item.documentation || undefined
+            })),
+            validFor: /^[\w$]*$/
+        };
+    };
+
+    const hover = hoverTooltip(async (view, pos) => {
+        const path = getPath(view);
+        if (!lspClient.isSupported(path)) return null;
+        const { line, character } = lspPosition(view.state, pos);
+        let text;
+        try {
+            text = await lspClient.hover(path, line, character);
+        } catch (e) {
+            lspClient.handleError(path, e);
+            return null;
+        }
+        if (!text?.trim()) return null;
+        const word = view.state.wordAt(pos);
+        return {
+            pos: word ? word.from : pos,
+            end: word ? word.to : pos,
+            above: true,
+            create() {
+                const dom = document.createElement('pre');
+                dom.className = 'cm-lsp-hover';
+                dom.textContent = text.trim();
+                return { dom };
+            }
+        };
+    });
+
+    return [autocompletion({ override: [completionSource] }), hover];
+}
@@ -1,14 +1,22 @@
 import { APP_CONFIG } from './constants.js';
 import { EventsOn } from "../wailsjs/runtime/runtime.js";
-import { GetOpenedFilePath, QueryOpenRouter, CloseApp, ReadFileContent } from '../wailsjs/go/main/App.js';
-import { createNewTab } from './tabManager.js';
+import { TakeOpenRequests, QueryOpenRouter, CloseApp, ReadFileContent, IndexWorkspace, OpenMode } from '../wailsjs/go/main/App.js';
+import { createNewTab, openFileInMode, openLogTab } from './tabManager.js';
 import { editorManager } from './editor.js';
 import { initMenu } from './menu.js';
-import { appState } from './state.js';
+import { appState, updateTabsOnRename } from './state.js';
 import { updateStatus } from './ui.js';
-import { loadFileFromPath } from './fileOperations.js';
+import { loadFileFromPath, replaceBufferContent, showFormatErrors } from './fileOperations.js';
+import { goToDefinition, findReferences, renameSymbol, goToWorkspaceSymbol, openFileAtPosition } from './navigation.js';
 import { FileExplorer } from './clsFileExplorer.js';
 import { CodeMirrorOutliner } from './clsOutliner.js';
+import { ProblemsPanel } from './clsProblemsPanel.js';
+import { TestPanel } from './clsTestPanel.js';
+import { DebugPanel } from './clsDebugPanel.js';
+import { HistoryPanel } from './clsHistoryPanel.js';
+import { initPassphraseDialog } from './dialogs/passphraseDialog.js';
+import { outputPanel } from './outputPanel.js';
+import { settingsStore } from './settings.js';
 import { UnsavedChangesModal } from './dialogs/clsUnsavedModal.js';
 import "./assets/css/style.css";
 import "./assets/css/app.css";
@@ -40,6 +48,12 @@ window.runtime.EventsOn('show-unsaved-modal', () => {
 document.addEventListener('DOMContentLoaded', async () => {
     try {
         const outliner = new CodeMirrorOutliner('outliner');
+        new ProblemsPanel();
+        new TestPanel();
+        new DebugPanel();
+        new HistoryPanel();
+        initPassphraseDialog();
+        outputPanel.init();
         initMenu();
 
         // await editorManager.initializePane('left');
@@ -64,12 +78,18 @@ document.addEventListener('DOMContentLoaded', async () => {
         //     }
         // });
 
-        const fileExplorer = new FileExplorer('folderlist', (filePath) => {
+        const fileExplorer = new FileExplorer('folderlist', async (filePath) => {
             console.log('User double-clicked file:', filePath);
 
             let fileData = null;
             let fileName = fileExplorer.getFilenameFromPath(filePath);
 
+            const mode = await OpenMode(filePath);
+            if (mode) {
+                openFileInMode(filePath, mode);
+                return;
+            }
+
             // Call your Go function to read file:
             ReadFileContent(filePath).then(content => {
                 fileData = {
@@ -96,8 +116,13 @@ document.addEventListener('DOMContentLoaded', async () => {
                     }
                 }
             });
+        }, (folderPath) => {
+            appState.currentDirectory = folderPath;
+            // Symbolindex für das Projekt des geöffneten Ordners aufbauen
+            IndexWorkspace(folderPath).catch(err => console.warn('Indexing failed:', err));
         });
 
+        fileExplorer.onOpenLog = (path) => openLogTab(path);
         fileExplorer.attachKeyboardShortcuts();
 
         // Initialize outliner with active editor
@@ -107,6 +132,10 @@ document.addEventListener('DOMContentLoaded', async () => {
                 const view = editorManager.getActiveView();
                 return view ? view.state.doc.toString() : '';
             },
+            getFilePath: () => {
+                const tab = appState.getActiveTab();
+                return tab?.filePath || tab?.fileName || '';
+            },
             view: editorManager.getActiveView()
         });
 
@@ -123,6 +152,10 @@ document.addEventListener('DOMContentLoaded', async () => {
                         const view = editorManager.getActiveView();
                         return view ? view.state.doc.toString() : '';
                     },
+                    getFilePath: () => {
+                        const tab = appState.getActiveTab();
+                        return tab?.filePath || tab?.fileName || '';
+                    },
                     view: editorManager.getActiveView()
                 });
                 outliner.refreshOutline();
@@ -149,6 +182,23 @@ document.addEventListener('DOMContentLoaded', async () => {
             }
         });
 
+        // F12: Gehe zu Definition, Umschalt+F12: Referenzen, F2: Umbenennen, Strg+T: Symbol im Workspace
+        document.addEventListener('keydown', (e) => {
+            if (e.key === 'F12' && !e.ctrlKey && !e.shiftKey) {
+                e.preventDefault();
+                goToDefinition();
+            } else if (e.key === 'F12' && e.shiftKey && !e.ctrlKey) {
+                e.preventDefault();
+                findReferences();
+            } else if (e.key === 'F2' && !e.ctrlKey && !e.shiftKey && document.activeElement?.closest('.cm-editor')) {
+                e.preventDefault();
+                renameSymbol();
+            } else if ((e.ctrlKey || e.metaKey) && !e.shiftKey && (e.key === 't' || e.key === 'T')) {
+                e.preventDefault();
+                goToWorkspaceSymbol();
+            }
+        });
+
         // ✅ DRAG & DROP Handler zuweisen
         // In main.js innerhalb von DOMContentLoaded
         editorManager.setFileDropHandler(async (filePath, fileObject) => {
@@ -189,26 +239,13 @@ document.addEventListener('DOMContentLoaded', async () => {
             }
         });
 
-        // On startup, check for an opened file path from backend
-        const targetPaneDefault = appState.activePane || 'left';
-        const initialPath = await GetOpenedFilePath();
-        if (initialPath && initialPath !== "") {
-            const fileData = await loadFileFromPath(initialPath);
-            if (fileData) {
-
-                const tabId = createNewTab(fileData.name, fileData.content, targetPaneDefault);
-                const tab = appState.openTabs.get(tabId);
-
-                tab.filePath = initialPath;
-                tab.savedContent = fileData.content;
-                tab.lastContent = fileData.content;
-                updateStatus(`${fileData.name} geladen (via Startup)!`);
-
-                editorManager.switchToTabInPane(tabId, targetPaneDefault);
-                return; // Datei wurde geladen
-            } else {
-                createNewTab(APP_CONFIG.DEFAULT_TAB_NAME, '', 'left');
-            }
+        // On startup, open the files from the command line; later launches
+        // forward theirs to this instance ("open-files")
+        EventsOn('open-files', () => openRequestedFiles());
+        const requested = await openRequestedFiles();
+        if (requested && appState.openTabs.size === 0) {
+            // Große und binäre Dateien haben schon einen eigenen Tab
+            createNewTab(APP_CONFIG.DEFAULT_TAB_NAME, '', 'left');
         }
 
     } catch (error) {
@@ -219,6 +256,22 @@ document.addEventListener('DOMContentLoaded', async () => {
 EventsOn("file-opened", (filePath) => {
 });
 
+// Öffnet die Dateien aus TakeOpenRequests; gibt zurück, ob es welche gab
+async function openRequestedFiles() {
+    const requests = await TakeOpenRequests();
+    for (const req of requests) {
+        const existing = Array.from(appState.openTabs.entries()).find(([, tab]) => tab.filePath === req.path);
+        if (existing && !req.line) {
+            await editorManager.switchToTabInPane(existing[0], existing[1].pane || 'left');
+            continue;
+        }
+        if (await openFileAtPosition(req.path, (req.line || 1) - 1, (req.column || 1) - 1)) {
+            updateStatus(`${req.path.split(/[\\/]/).pop()} geladen!`);
+        }
+    }
+    return requests.length > 0;
+}
+
 // HMR support
 if (import.meta.hot) {
     import.meta.hot.dispose(() => {
@@ -226,6 +279,30 @@ if (import.meta.hot) {
     });
 }
 
+// Format-on-save: Backend hat den gespeicherten Inhalt formatiert
+EventsOn("buffer-formatted", ({ path, content }) => {
+    const tab = appState.getActiveTab();
+    const view = editorManager.getActiveView();
+    if (tab && view && tab.filePath === path) {
+        replaceBufferContent(view, content);
+        tab.savedContent = content;
+        tab.lastContent = content;
+        appState.setDirty(false);
+    }
+});
+
+EventsOn("format-errors", ({ errors }) => showFormatErrors(errors));
+
+EventsOn("path-renamed", ({ from, to }) => updateTabsOnRename(from, to));
+
+// Fehler in settings.json oder den Workspace-Einstellungen melden, die übrigen Werte gelten weiter
+settingsStore.onChange((settings, errors) => {
+    if (errors.length === 0) return;
+    errors.forEach(err => console.warn("Einstellungen:", err));
+    const more = errors.length > 1 ? ` (+${errors.length - 1} weitere)` : '';
+    updateStatus(`Einstellungen: ${errors[0]}${more}`, "error");
+});
+
 // Wails error events
 EventsOn("error", (msg) => {
     console.error("Backend error:", msg);
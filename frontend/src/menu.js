@@ -1,14 +1,22 @@
 // Menu and tab management
-import { CloseApp, SetUnsavedChanges, HasUnsavedChanges, RequestClose } from "../wailsjs/go/main/App.js";
+import {
+    CloseApp, SetUnsavedChanges, HasUnsavedChanges, RequestClose,
+    OpenFolderDialog, OpenWorkspaceDialog, SaveWorkspaceAs, CloseWorkspace, AddWorkspaceFolderDialog,
+    OpenSettingsFile, SelectCompareFile, SelectCompareDirectory, OpenRemote, AddRecentFile
+} from "../wailsjs/go/main/App.js";
 import { renderIcon } from './lib/icons.js';
-import { closeActiveTab, closeAllTabs, closeTab, createNewTab, resetSplitWindow, closeSplitWindow } from './tabManager.js';
+import { closeActiveTab, closeAllTabs, closeTab, createNewTab, resetSplitWindow, closeSplitWindow, openLogTab } from './tabManager.js';
 import { appState, updateCurrentTabOnSave } from './state.js';
 import { editorManager, editorCommands } from './editor.js';
-import { openFile, saveFile, saveFileUnder, loadFileFromPath } from './fileOperations.js';
+import { openFile, saveFile, saveFileUnder, loadFileFromPath, formatActiveBuffer } from './fileOperations.js';
 import { updateStatus, setAppTitle, SidepanelCloser } from './ui.js';
 import { APP_CONFIG } from './constants.js';
 import { showAboutDialog } from './dialogs/aboutDialog.js';
 import { LeftToolbar } from './clsLefttoolbar.js';
+import { outputPanel } from './outputPanel.js';
+import { debugStore, toggleBreakpointAtCursor } from './debugger.js';
+import { openFileAtPosition, goToWorkspaceSymbol } from './navigation.js';
+import { DebugContinue, DebugNext, DebugStepIn, DebugStepOut } from "../wailsjs/go/main/App.js";
 
 // Initialize left toolbar
 const verticalToolbar = new LeftToolbar('asideToolbar');
@@ -59,6 +67,12 @@ const menuActions = {
     },
     'menu-copy': () => editorCommands.copy(editorManager.view),
     'menu-paste': () => editorCommands.paste(editorManager.view),
+    'menu-format': () => formatActiveBuffer(),
+    'menu-workspace-symbol': () => goToWorkspaceSymbol(),
+    'menu-compare-disk': () => compareActiveBuffer('file'),
+    'menu-compare-clipboard': () => compareActiveBuffer('clipboard'),
+    'menu-compare-files': () => compareFiles(),
+    'menu-compare-dirs': () => compareDirectories(),
     'menu-select-all': () => {
         const view = editorManager.view;
         if (view) {
@@ -73,6 +87,42 @@ const menuActions = {
     'menu-ai-panel': () => {
         createNewTab('openrouter.ai', 'StarteAI');
     },
+    'menu-terminal': () => {
+        createNewTab('Terminal', 'StarteTerminal');
+    },
+    'menu-log-view': () => {
+        const tab = appState.getActiveTab();
+        if (!tab?.filePath) {
+            updateStatus("Die Datei wurde noch nicht gespeichert", "error");
+            return;
+        }
+        openLogTab(tab.filePath);
+    },
+    'menu-open-folder': () => workspaceAction(OpenFolderDialog),
+    'menu-open-remote': () => openRemote(),
+    'menu-open-workspace': () => workspaceAction(OpenWorkspaceDialog),
+    'menu-add-workspace-folder': () => workspaceAction(AddWorkspaceFolderDialog),
+    'menu-save-workspace': () => workspaceAction(SaveWorkspaceAs),
+    'menu-close-workspace': () => CloseWorkspace(),
+    'menu-settings': async () => {
+        try {
+            await openFileAtPosition(await OpenSettingsFile());
+        } catch (err) {
+            updateStatus(`${err}`, "error");
+        }
+    },
+    'menu-run-file': () => outputPanel.runActiveFile(),
+    'menu-run-task': () => {
+        outputPanel.show();
+        document.querySelector('#output-panel .output-tasks')?.focus();
+    },
+    'menu-output': () => outputPanel.toggle(),
+    'menu-debug-start': () => {
+        SidepanelCloser('Debugger');
+        document.getElementById('debug-panel')?.classList.remove('hidden');
+        document.querySelector('#debug-panel [data-mode="debug"]')?.click();
+    },
+    'menu-toggle-breakpoint': () => toggleBreakpointAtCursor(editorManager.getActiveView()),
     'menu-split-horizontal': () => {
         console.log("Split Horizontal ausgewählt (noch nicht implementiert)");
         alert("Split Horizontal ist noch nicht implementiert.");
@@ -88,6 +138,16 @@ const menuActions = {
 
 };
 
+// Workspace dialogs; cancelling is not an error
+async function workspaceAction(action) {
+    try {
+        const ws = await action();
+        updateStatus(`Workspace ${ws.name} geöffnet`);
+    } catch (err) {
+        if (`${err}` !== 'Abgebrochen') updateStatus(`${err}`, "error");
+    }
+}
+
 export function initMenu() {
     const menubar = document.getElementById('menubar');
     if (!menubar) return;
@@ -122,6 +182,22 @@ export function initMenu() {
         } else if ((e.ctrlKey || e.metaKey) && e.shiftKey && e.key === 's') {
             e.preventDefault();
             saveFileUnder();
+        } else if ((e.ctrlKey || e.metaKey) && e.shiftKey && (e.key === 'F' || e.key === 'f')) {
+            e.preventDefault();
+            formatActiveBuffer();
+        } else if (e.ctrlKey && e.shiftKey && e.code === 'Backquote') {
+            e.preventDefault();
+            createNewTab('Terminal', 'StarteTerminal');
+        } else if (e.key === 'F5' && !e.ctrlKey && !e.shiftKey) {
+            e.preventDefault();
+            outputPanel.runActiveFile();
+        } else if (e.key === 'F9' && !e.ctrlKey && !e.shiftKey) {
+            e.preventDefault();
+            toggleBreakpointAtCursor(editorManager.getActiveView());
+        } else if (debugStore.stopped && ['F8', 'F10', 'F11'].includes(e.key) && !e.ctrlKey) {
+            e.preventDefault();
+            const step = e.key === 'F8' ? DebugContinue : e.key === 'F10' ? DebugNext : e.shiftKey ? DebugStepOut : DebugStepIn;
+            step().catch(err => updateStatus(`${err}`, "error"));
         } else if (e.ctrlKey && e.key === 'q') {
             e.preventDefault();
             confirmUnsavedChangesBeforeQuit();
@@ -230,9 +306,12 @@ async function handleOpenFile() {
         tab.savedContent = fileData.content;
         tab.lastContent = fileData.content;
         tab.dirty = false;
+        tab.editorConfig = fileData.editorConfig;
+        tab.charset = fileData.charset;
         console.log(); ("Lade Datei in bestehenden rechten Tab:", tabId);
         // Editor-Inhalt aktualisieren
         editorManager.setValue(fileData.content, 'right');
+        editorManager.loadEditorConfig(tabId, editorManager.panes.get('right'));
 
         // Tab-Titel aktualisieren
         const tabElement = document.querySelector(`[data-tab-id="${tabId}"] .tab-title`);
@@ -249,6 +328,8 @@ async function handleOpenFile() {
     const tabId = createNewTab(fileData.name, fileData.content);
     const tab = appState.openTabs.get(tabId);
     tab.filePath = fileData.path;
+    tab.editorConfig = fileData.editorConfig;
+    tab.charset = fileData.charset;
     tab.savedContent = fileData.content;
     tab.dirty = false;
     appState.setDirty(false);
@@ -256,6 +337,75 @@ async function handleOpenFile() {
     updateStatus(`${fileData.name} geladen!`);
 }
 
+// Vergleicht den aktiven Puffer mit der gespeicherten Datei oder der Zwischenablage
+function compareActiveBuffer(kind) {
+    const tab = appState.getActiveTab();
+    const view = editorManager.getActiveView();
+    if (tab?.type !== 'editor' || !view) {
+        updateStatus("Kein Editor aktiv", "error");
+        return;
+    }
+    const buffer = { kind: 'text', name: tab.fileName, text: view.state.doc.toString() };
+    if (kind === 'file') {
+        if (!tab.filePath) {
+            updateStatus("Die Datei wurde noch nicht gespeichert", "error");
+            return;
+        }
+        const saved = { kind: 'file', name: `${tab.fileName} (gespeichert)`, path: tab.filePath };
+        createNewTab(`⇄ ${tab.fileName}`, 'StarteDiff', null, { diff: { left: saved, right: buffer } });
+    } else {
+        createNewTab(`⇄ ${tab.fileName}`, 'StarteDiff', null, { diff: { left: buffer, right: { kind: 'clipboard' } } });
+    }
+}
+
+async function compareFiles() {
+    try {
+        const left = await SelectCompareFile("Erste Datei (links)");
+        if (!left) return;
+        const right = await SelectCompareFile("Zweite Datei (rechts)");
+        if (!right) return;
+        const name = (p) => p.split(/[/\\]/).pop();
+        createNewTab(`${name(left)} ⇄ ${name(right)}`, 'StarteDiff', null, {
+            diff: { left: { kind: 'file', path: left }, right: { kind: 'file', path: right } }
+        });
+    } catch (err) {
+        updateStatus(`${err}`, "error");
+    }
+}
+
+// Öffnet eine Datei oder einen Ordner per SFTP; Ordner zeigt der Explorer an
+async function openRemote() {
+    const address = prompt("SFTP-Adresse (sftp://benutzer@host/pfad)", "sftp://");
+    if (!address || address.trim() === 'sftp://') return;
+    updateStatus("Verbinde…");
+    try {
+        const path = await OpenRemote(address);
+        if (!path) {
+            updateStatus("Remote-Ordner geöffnet");
+            return;
+        }
+        await AddRecentFile(path);
+        if (await openFileAtPosition(path)) updateStatus(`${path} geladen`);
+    } catch (err) {
+        updateStatus(`${err}`, "error");
+    }
+}
+
+async function compareDirectories() {
+    try {
+        const left = await SelectCompareDirectory("Erster Ordner (links)");
+        if (!left) return;
+        const right = await SelectCompareDirectory("Zweiter Ordner (rechts)");
+        if (!right) return;
+        const name = (p) => p.split(/[/\\]/).pop();
+        createNewTab(`${name(left)}/ ⇄ ${name(right)}/`, 'StarteDiff', null, {
+            diff: { dirs: { left, right } }
+        });
+    } catch (err) {
+        updateStatus(`${err}`, "error");
+    }
+}
+
 function initToolbarLeft() {
 
     // Register actions
@@ -277,6 +427,29 @@ function initToolbarLeft() {
         document.getElementById('recent-files-panel').classList.toggle('hidden');
     });
 
+    verticalToolbar.registerAction('Probleme', () => {
+        SidepanelCloser('Probleme');
+        document.getElementById('problems-panel')?.classList.toggle('hidden');
+    });
+
+    verticalToolbar.registerAction('Tests', () => {
+        SidepanelCloser('Tests');
+        document.getElementById('test-panel')?.classList.toggle('hidden');
+    });
+
+    verticalToolbar.registerAction('Debugger', () => {
+        SidepanelCloser('Debugger');
+        document.getElementById('debug-panel')?.classList.toggle('hidden');
+    });
+
+    verticalToolbar.registerAction('Verlauf', () => {
+        SidepanelCloser('Verlauf');
+        const panel = document.getElementById('history-panel');
+        if (panel?.classList.toggle('hidden') === false) {
+            panel.querySelector('.btn-history-refresh')?.click();
+        }
+    });
+
     verticalToolbar.registerAction('AI Fenster', () => {
         createNewTab("Openrouter.ai", "StarteAI");
     });
@@ -0,0 +1,201 @@
+// Navigation helpers: open files at a position, go to definition
+import { EditorView } from "@codemirror/view";
+import { FindDefinition, WorkspaceSymbols } from "../wailsjs/go/main/App.js";
+import { appState } from './state.js';
+import { editorManager } from './editor.js';
+import { createNewTab } from './tabManager.js';
+import { loadFileFromPath } from './fileOperations.js';
+import { lspClient } from './lspClient.js';
+import { lspPosition } from './lspEditor.js';
+import { showPicker } from './dialogs/pickerDialog.js';
+import { updateStatus } from './ui.js';
+
+/**
+ * Öffnet eine Datei (oder wechselt zum offenen Tab) und setzt den Cursor.
+ * @param {string} path - Absoluter Pfad
+ * @param {number} line - Zeile (0-basiert)
+ * @param {number} character - Spalte (0-basiert)
+ */
+export async function openFileAtPosition(path, line = 0, character = 0) {
+    const existing = Array.from(appState.openTabs.entries()).find(([, tab]) => tab.filePath === path);
+    let tabId = existing?.[0];
+
+    if (!tabId) {
+        const fileData = await loadFileFromPath(path);
+        if (!fileData) return false;
+
+        tabId = createNewTab(fileData.name, fileData.content, appState.activePane || 'left');
+        const tab = appState.openTabs.get(tabId);
+        if (tab) {
+            tab.filePath = path;
+            tab.savedContent = fileData.content;
+            tab.lastContent = fileData.content;
+            tab.dirty = false;
+        }
+    } else {
+        await editorManager.switchToTabInPane(tabId, existing[1].pane || 'left');
+    }
+
+    const view = editorManager.getActiveView();
+    if (!view) return false;
+
+    const lineNo = Math.min(Math.max(line + 1, 1), view.state.doc.lines);
+    const docLine = view.state.doc.line(lineNo);
+    const pos = Math.min(docLine.from + character, docLine.to);
+    view.dispatch({
+        selection: { anchor: pos },
+        effects: EditorView.scrollIntoView(pos, { y: 'center' })
+    });
+    view.focus();
+    return true;
+}
+
+/**
+ * Springt zur Definition des Wortes unter dem Cursor.
+ * Nutzt den Language Server, sonst den Symbolindex des Backends.
+ */
+export async function goToDefinition() {
+    const view = editorManager.getActiveView();
+    const tab = appState.getActiveTab();
+    if (!view || !tab) return;
+
+    const pos = view.state.selection.main.head;
+    const word = view.state.wordAt(pos);
+    if (!word) return;
+    const name = view.state.sliceDoc(word.from, word.to);
+
+    if (tab.filePath && lspClient.isSupported(tab.filePath)) {
+        try {
+            const line = view.state.doc.lineAt(pos);
+            const locations = await lspClient.definition(tab.filePath, line.number - 1, pos - line.from);
+            if (locations && locations.length > 0) {
+                const loc = locations[0];
+                await openFileAtPosition(loc.path, loc.range.start.line, loc.range.start.character);
+                return;
+            }
+        } catch (e) {
+            console.warn('LSP definition failed, falling back to index:', e);
+        }
+    }
+
+    const symbols = await FindDefinition(name, tab.filePath || '');
+    if (!symbols || symbols.length === 0) {
+        updateStatus(`Keine Definition für "${name}" gefunden`, "error");
+        return;
+    }
+    const sym = symbols[0];
+    await openFileAtPosition(sym.path, sym.line, sym.character);
+    if (symbols.length > 1) {
+        updateStatus(`${symbols.length} Definitionen für "${name}" gefunden`);
+    }
+}
+
+const WORKSPACE_SYMBOL_LIMIT = 200;
+
+/**
+ * Sucht Symbole im Symbolindex des Workspace und springt zum gewählten.
+ */
+export function goToWorkspaceSymbol() {
+    showPicker({
+        title: 'Gehe zu Symbol im Workspace',
+        placeholder: 'Symbolname…',
+        search: async (query) => {
+            const symbols = await WorkspaceSymbols(query, WORKSPACE_SYMBOL_LIMIT);
+            return (symbols || []).map(sym => ({
+                label: sym.container ? `${sym.container}.${sym.name}` : sym.name,
+                detail: `${sym.kind} – ${sym.path}:${sym.line + 1}`,
+                value: sym
+            }));
+        },
+        onSelect: (sym) => openFileAtPosition(sym.path, sym.line, sym.character)
+    });
+}
+
+// Datei und Cursorposition des aktiven Editors für Language-Server-Anfragen
+function lspTarget() {
+    const view = editorManager.getActiveView();
+    const tab = appState.getActiveTab();
+    if (!view || !tab?.filePath) return null;
+    if (!lspClient.isSupported(tab.filePath)) {
+        updateStatus("Kein Language Server für diese Datei", "error");
+        return null;
+    }
+    const pos = view.state.selection.main.head;
+    return { view, path: tab.filePath, pos, ...lspPosition(view.state, pos) };
+}
+
+/**
+ * Zeigt alle Referenzen des Symbols unter dem Cursor in einer Auswahlliste.
+ */
+export async function findReferences() {
+    const target = lspTarget();
+    if (!target) return;
+    let locations;
+    try {
+        locations = await lspClient.references(target.path, target.line, target.character);
+    } catch (e) {
+        lspClient.handleError(target.path, e);
+        updateStatus(`Referenzen: ${e}`, "error");
+        return;
+    }
+    if (!locations?.length) {
+        updateStatus("Keine Referenzen gefunden", "error");
+        return;
+    }
+    const word = target.view.state.wordAt(target.pos);
+    const name = word ? target.view.state.sliceDoc(word.from, word.to) : '';
+    showPicker({
+        title: `${locations.length} Referenzen${name ? ` auf "${name}"` : ''}`,
+        placeholder: 'Filtern…',
+        items: locations.map(loc => ({
+            label: `${loc.path.split(/[/\\]/).pop()}:${loc.range.start.line + 1}:${loc.range.start.character + 1}`,
+            detail: loc.path,
+            value: loc
+        })),
+        onSelect: (loc) => openFileAtPosition(loc.path, loc.range.start.line, loc.range.start.character)
+    });
+}
+
+/**
+ * Benennt das Symbol unter dem Cursor mit dem Language Server um. Die
+ * Änderungen landen in den Editor-Tabs und werden nicht gespeichert.
+ */
+export async function renameSymbol() {
+    const target = lspTarget();
+    if (!target) return;
+    const word = target.view.state.wordAt(target.pos);
+    if (!word) return;
+    const oldName = target.view.state.sliceDoc(word.from, word.to);
+    const newName = prompt('Neuer Name', oldName)?.trim();
+    if (!newName || newName === oldName) return;
+
+    let files;
+    try {
+        files = await lspClient.rename(target.path, target.line, target.character, newName);
+    } catch (e) {
+        lspClient.handleError(target.path, e);
+        updateStatus(`Umbenennen: ${e}`, "error");
+        return;
+    }
+    if (!files?.length) {
+        updateStatus(`"${oldName}" kann nicht umbenannt werden`, "error");
+        return;
+    }
+
+    let count = 0;
+    for (const file of files) {
+        if (!await openFileAtPosition(file.path)) continue;
+        const view = editorManager.getActiveView();
+        const doc = view.state.doc;
+        const offset = (p) => {
+            const line = doc.line(Math.min(p.line + 1, doc.lines));
+            return Math.min(line.from + p.character, line.to);
+        };
+        view.dispatch({
+            changes: file.edits.map(e => ({ from: offset(e.range.start), to: offset(e.range.end), insert: e.newText }))
+        });
+        count += file.edits.length;
+    }
+    await openFileAtPosition(target.path, target.line, target.character);
+    updateStatus(`"${oldName}" in ${files.length} Dateien umbenannt (${count} Stellen), noch nicht gespeichert`, "success");
+}
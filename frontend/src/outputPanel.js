@@ -0,0 +1,236 @@
+// Output panel below the editor: output of tasks and other processes started by the backend
+import { EventsOn } from "../wailsjs/runtime/runtime.js";
+import { GetTasks, RunTask, RunFile, StopProcess, ListProcesses } from '../wailsjs/go/main/App.js';
+import { renderIcon } from './lib/icons.js';
+import { appState } from './state.js';
+import { updateStatus } from './ui.js';
+import { openFileAtPosition } from './navigation.js';
+import { saveFile } from './fileOperations.js';
+
+// Lines kept per process
+const MAX_LINES = 5000;
+
+class OutputPanel {
+    constructor() {
+        this.outputs = new Map();   // process id -> { info, lines[] }
+        this.selectedId = null;
+        this.panel = null;
+
+        EventsOn('process-output', (line) => this.appendLine(line));
+        EventsOn('process-exit', ({ id, info, error }) => this.processExited(id, info, error));
+    }
+
+    init(panelId = 'output-panel') {
+        this.panel = document.getElementById(panelId);
+        if (!this.panel) return;
+
+        this.panel.innerHTML = `
+            <div class="output-header">
+                <span class="output-title">Ausgabe</span>
+                <select class="output-processes" title="Prozesse"></select>
+                <button class="output-btn output-stop" title="Prozess stoppen"></button>
+                <button class="output-btn output-clear" title="Ausgabe leeren"></button>
+                <span class="output-spacer"></span>
+                <select class="output-tasks" title="Tasks aus .leoedit/tasks.json"></select>
+                <button class="output-btn output-run" title="Task ausführen"></button>
+                <button class="output-btn output-close" title="Schließen"></button>
+            </div>
+            <div class="output-lines"></div>
+        `;
+
+        this.processSelect = this.panel.querySelector('.output-processes');
+        this.taskSelect = this.panel.querySelector('.output-tasks');
+        this.linesEl = this.panel.querySelector('.output-lines');
+
+        const buttons = { 'output-stop': 'SquareX', 'output-clear': 'Trash2', 'output-run': 'Play', 'output-close': 'X' };
+        for (const [cls, icon] of Object.entries(buttons)) {
+            this.panel.querySelector(`.${cls}`).appendChild(renderIcon(icon, { width: 14, height: 14 }));
+        }
+
+        this.processSelect.addEventListener('change', () => this.select(this.processSelect.value));
+        this.panel.querySelector('.output-stop').addEventListener('click', () => this.stop());
+        this.panel.querySelector('.output-clear').addEventListener('click', () => this.clear());
+        this.panel.querySelector('.output-run').addEventListener('click', () => this.runTask(this.taskSelect.value));
+        this.panel.querySelector('.output-close').addEventListener('click', () => this.hide());
+    }
+
+    // Verzeichnis, dessen Workspace die Tasks liefert
+    currentDir() {
+        const tab = appState.getActiveTab();
+        if (tab?.filePath) return tab.filePath.replace(/[/\\][^/\\]*$/, '');
+        return appState.currentDirectory || '';
+    }
+
+    show() {
+        this.panel?.classList.remove('hidden');
+        this.loadTasks();
+    }
+
+    hide() {
+        this.panel?.classList.add('hidden');
+    }
+
+    toggle() {
+        if (this.panel?.classList.contains('hidden')) this.show();
+        else this.hide();
+    }
+
+    async loadTasks() {
+        this.taskSelect.innerHTML = '';
+        try {
+            const list = await GetTasks(this.currentDir());
+            if (!list.tasks.length) {
+                this.taskSelect.appendChild(new Option('Keine Tasks', ''));
+                this.taskSelect.disabled = true;
+                return;
+            }
+            this.taskSelect.disabled = false;
+            this.taskDir = list.root;
+            list.tasks.forEach(t => this.taskSelect.appendChild(new Option(t.name, t.name)));
+        } catch (err) {
+            this.taskSelect.appendChild(new Option('Fehler in tasks.json', ''));
+            this.taskSelect.disabled = true;
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    async runTask(name) {
+        if (!name) return;
+        try {
+            const info = await RunTask(this.taskDir || this.currentDir(), name);
+            this.attach(info);
+        } catch (err) {
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    // Führt die Datei des aktiven Tabs aus, ungespeicherte Änderungen werden vorher gespeichert
+    async runActiveFile() {
+        const tab = appState.getActiveTab();
+        if (!tab || tab.type !== 'editor') return;
+        const path = tab.filePath;
+        if (!path) {
+            updateStatus("Die Datei muss zuerst gespeichert werden", "error");
+            return;
+        }
+        if (tab.dirty && !(await saveFile())) return;
+
+        try {
+            const info = await RunFile(path);
+            this.attach(info);
+        } catch (err) {
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    /**
+     * Zeigt die Ausgabe eines gestarteten Prozesses an.
+     * @param {object} info - ProcessInfo aus dem Backend
+     */
+    attach(info) {
+        const output = this.outputs.get(info.id);
+        if (!output) {
+            this.outputs.set(info.id, { info, lines: [] });
+        } else if (output.info.running) {
+            // Die ersten Zeilen können vor der Antwort von RunTask/RunFile ankommen
+            output.info = info;
+        }
+        this.panel?.classList.remove('hidden');
+        this.renderProcessList();
+        this.select(info.id);
+        if (this.outputs.get(info.id).info.running) updateStatus(`${info.name} gestartet`);
+    }
+
+    appendLine(line) {
+        let output = this.outputs.get(line.processId);
+        if (!output) {
+            // Ausgabe eines Prozesses, der nicht über das Panel gestartet wurde
+            output = { info: { id: line.processId, name: line.processId, running: true }, lines: [] };
+            this.outputs.set(line.processId, output);
+            this.renderProcessList();
+        }
+        output.lines.push(line);
+        if (output.lines.length > MAX_LINES) output.lines.shift();
+
+        if (this.selectedId === line.processId && this.linesEl) {
+            this.linesEl.appendChild(this.renderLine(line));
+            if (this.linesEl.childElementCount > MAX_LINES) this.linesEl.firstChild.remove();
+            this.linesEl.scrollTop = this.linesEl.scrollHeight;
+        }
+    }
+
+    processExited(id, info, error) {
+        // Ein Prozess ohne Ausgabe kann vor der Antwort von RunTask/RunFile enden;
+        // attach() behält dann den beendeten Zustand
+        const output = this.outputs.get(id);
+        if (output) {
+            output.info = info;
+        } else {
+            this.outputs.set(id, { info, lines: [] });
+        }
+        const text = `[${info.name} beendet mit Code ${info.exitCode} nach ${info.duration.toFixed(1)}s]`;
+        this.appendLine({ processId: id, stream: 'status', text });
+        this.renderProcessList();
+        updateStatus(text, info.exitCode === 0 ? "success" : "error");
+        if (error) console.warn(`${info.name}:`, error);
+    }
+
+    renderProcessList() {
+        if (!this.processSelect) return;
+        this.processSelect.innerHTML = '';
+        for (const { info } of this.outputs.values()) {
+            const label = `${info.running ? '● ' : ''}${info.name}`;
+            this.processSelect.appendChild(new Option(label, info.id, false, info.id === this.selectedId));
+        }
+    }
+
+    select(id) {
+        this.selectedId = id;
+        this.processSelect.value = id;
+        this.linesEl.innerHTML = '';
+        const output = this.outputs.get(id);
+        if (!output) return;
+        const fragment = document.createDocumentFragment();
+        output.lines.forEach(line => fragment.appendChild(this.renderLine(line)));
+        this.linesEl.appendChild(fragment);
+        this.linesEl.scrollTop = this.linesEl.scrollHeight;
+    }
+
+    renderLine(line) {
+        const el = document.createElement('div');
+        el.className = `output-line output-${line.stream}`;
+        el.textContent = line.text;
+        if (line.path) {
+            el.classList.add('output-link');
+            el.title = `${line.path}:${line.line}:${line.column || 1}`;
+            el.addEventListener('click', () => {
+                openFileAtPosition(line.path, line.line - 1, Math.max((line.column || 1) - 1, 0));
+            });
+        }
+        return el;
+    }
+
+    async stop() {
+        if (!this.selectedId) return;
+        try {
+            await StopProcess(this.selectedId);
+        } catch (err) {
+            updateStatus(`${err}`, "error");
+        }
+    }
+
+    // Entfernt beendete Prozesse und leert die Ausgabe
+    async clear() {
+        const running = new Set((await ListProcesses()).filter(p => p.running).map(p => p.id));
+        for (const [id, output] of this.outputs) {
+            if (running.has(id)) output.lines = [];
+            else this.outputs.delete(id);
+        }
+        if (!this.outputs.has(this.selectedId)) this.selectedId = this.outputs.keys().next().value || null;
+        this.renderProcessList();
+        if (this.selectedId) this.select(this.selectedId);
+        else this.linesEl.innerHTML = '';
+    }
+}
+
+export const outputPanel = new OutputPanel();
@@ -0,0 +1,32 @@
+// Settings from settings.json and the workspace, resolved by the backend
+import { EventsOn } from "../wailsjs/runtime/runtime.js";
+import { GetSettings } from '../wailsjs/go/main/App.js';
+
+class SettingsStore {
+    constructor() {
+        this.settings = null;   // null bis zur ersten Antwort: Voreinstellungen verwenden
+        this.errors = [];
+        this.path = '';
+        this.listeners = [];
+
+        GetSettings().then(res => this.update(res)).catch(err => console.warn('GetSettings:', err));
+        EventsOn('settings-changed', (res) => this.update(res));
+    }
+
+    update(res) {
+        this.settings = res.settings;
+        this.errors = res.errors || [];
+        this.path = res.path;
+        this.listeners.forEach(cb => cb(this.settings, this.errors));
+    }
+
+    get editor() {
+        return this.settings?.editor || {};
+    }
+
+    onChange(callback) {
+        this.listeners.push(callback);
+    }
+}
+
+export const settingsStore = new SettingsStore();
@@ -18,6 +18,7 @@ class AppState {
         this.isVSplitActive = false;
         this.isHSplitActive = false;
         this.activePane = 'left'; // 'left' oder 'right'
+        this.currentDirectory = ''; // Ordner des Explorers, Startverzeichnis für Terminals
     }
 
     setSplitActive(active) {
@@ -333,4 +334,20 @@ export function updateCurrentTabOnSave(filePath, newContent) {
         updateStatus(`${tab.fileName} gespeichert`);
         setAppTitle(`${APP_CONFIG.NAME} - ${tab.fileName}`);
     }
-}
\ No newline at end of file
+}
+// Folgt einer Umbenennung im Explorer: Tabs der Datei oder von Dateien darunter
+export function updateTabsOnRename(from, to) {
+    for (const [tabId, tab] of appState.openTabs) {
+        if (!tab.filePath) continue;
+        let path = null;
+        if (tab.filePath === from) path = to;
+        else if (tab.filePath.startsWith(from + '/') || tab.filePath.startsWith(from + '\\')) {
+            path = to + tab.filePath.slice(from.length);
+        }
+        if (!path) continue;
+        tab.filePath = path;
+        tab.fileName = path.split(/[\\/]/).pop();
+        updateTabTitle(tabId);
+        if (tabId === appState.activeTabId) setAppTitle(`${APP_CONFIG.NAME} - ${tab.fileName}`);
+    }
+}
@@ -4,14 +4,15 @@ import { renderIcon } from './lib/icons.js';
 import { updateStatus, setAppTitle } from './ui.js';
 import { editorManager, editorCommands, detectLanguage } from './editor.js';
 import { EditorState } from "@codemirror/state";
-import { MarkFileAsSaved } from '../wailsjs/go/main/App.js';
+import { MarkFileAsSaved, ForgetPassphrase } from '../wailsjs/go/main/App.js';
+import { lspClient } from './lspClient.js';
 
-export function closeTab(tabId) {
+export function closeTab(tabId, skipConfirm = false) {
     const tabInfo = appState.openTabs.get(tabId);
     if (!tabInfo) return;
 
     // Check if tab has unsaved changes
-    if (tabInfo.dirty && tabInfo.type === 'editor') {
+    if (!skipConfirm && tabInfo.dirty && ['editor', 'large', 'hex'].includes(tabInfo.type)) {
         const confirmed = confirm("Datei hat ungespeicherte Änderungen. Trotzdem schließen?");
         if (!confirmed) {
             return;
@@ -28,6 +29,11 @@ export function closeTab(tabId) {
             // Only mark as saved for editor files
             if (tabInfo.filePath) {
                 MarkFileAsSaved(tabInfo.filePath);
+                lspClient.documentClosed(tabInfo.filePath);
+                // Das Passwort einer verschlüsselten Datei nur im Speicher halten, solange sie offen ist
+                const stillOpen = [...appState.openTabs.values()]
+                    .some(t => t !== tabInfo && t.filePath === tabInfo.filePath);
+                if (!stillOpen) ForgetPassphrase(tabInfo.filePath);
             }
             break;
 
@@ -42,6 +48,33 @@ export function closeTab(tabId) {
         case 'ai':
             // AI tab specific cleanup if needed
             break;
+
+        case 'terminal':
+            // Shell beenden und xterm entfernen
+            editorManager.terminalPanels?.get(tabId)?.dispose();
+            editorManager.terminalPanels?.delete(tabId);
+            break;
+
+        case 'diff':
+            editorManager.diffViews?.get(tabId)?.dispose();
+            editorManager.diffViews?.delete(tabId);
+            break;
+
+        case 'large':
+            // Gibt auch die Datei im Backend frei
+            editorManager.largeViews?.get(tabId)?.dispose();
+            editorManager.largeViews?.delete(tabId);
+            break;
+
+        case 'hex':
+            editorManager.hexViews?.get(tabId)?.dispose();
+            editorManager.hexViews?.delete(tabId);
+            break;
+
+        case 'log':
+            editorManager.logViews?.get(tabId)?.dispose();
+            editorManager.logViews?.delete(tabId);
+            break;
     }
 
     // Remove tab from DOM
@@ -116,11 +149,8 @@ export function closeAllTabs() {
 }
 
 function closeTabWithoutConfirmation(tabId) {
-    // Simplified version of closeTab that skips confirmation
-    const tabInfo = appState.openTabs.get(tabId);
-    if (!tabInfo) return;
-
-    // ... rest of cleanup logic without confirm dialog
+    // Same cleanup as closeTab (LSP, passphrases, views), without the confirm dialog
+    closeTab(tabId, true);
 }
 
 function cleanupEmptyPane(pane) {
@@ -169,7 +199,7 @@ function cleanupEmptyPane(pane) {
     }
 }
 
-export function createNewTab(filename = APP_CONFIG.DEFAULT_TAB_NAME, initialContent = '', paneId = null) {
+export function createNewTab(filename = APP_CONFIG.DEFAULT_TAB_NAME, initialContent = '', paneId = null, tabData = {}) {
     // Check if file is already open (exclude default untitled file)
     if (filename !== APP_CONFIG.DEFAULT_TAB_NAME) {
         const existingTab = Array.from(appState.openTabs.entries()).find(([id, tab]) =>
@@ -220,18 +250,33 @@ export function createNewTab(filename = APP_CONFIG.DEFAULT_TAB_NAME, initialCont
     const tabId = `tab-${Date.now()}-${targetPane}`;
     const isWeb = initialContent.startsWith('http://') || initialContent.startsWith('https://');
     const isAi = initialContent.startsWith('StarteAI');
+    const isTerminal = initialContent.startsWith('StarteTerminal');
+    const isDiff = initialContent.startsWith('StarteDiff');
+    const isLarge = initialContent.startsWith('StarteLarge');
+    const isLog = initialContent.startsWith('StarteLog');
+    const isHex = initialContent.startsWith('StarteHex');
 
     // Create tab based on type
     if (isWeb) {
         createWebTab(tabId, filename, initialContent, targetPane);
     } else if (isAi) {
         createAiTab(tabId, filename, targetPane);
+    } else if (isTerminal) {
+        createTerminalTab(tabId, filename, targetPane);
+    } else if (isDiff) {
+        createDiffTab(tabId, filename, targetPane, tabData.diff);
+    } else if (isLarge) {
+        createLargeTab(tabId, filename, targetPane, tabData.large);
+    } else if (isLog) {
+        createLogTab(tabId, filename, targetPane, tabData.log);
+    } else if (isHex) {
+        createHexTab(tabId, filename, targetPane, tabData.hex);
     } else {
         createEditorTab(tabId, filename, initialContent, targetPane);
     }
 
     // Create and setup tab element
-    setupTabElement(tabId, filename, targetPane, !isWeb && !isAi);
+    setupTabElement(tabId, filename, targetPane, !isWeb && !isAi && !isTerminal && !isDiff && !isLarge && !isLog && !isHex);
 
     // Activate the new tab
     editorManager.switchToTabInPane(tabId, targetPane);
@@ -270,6 +315,103 @@ function createAiTab(tabId, filename, pane) {
     });
 }
 
+function createTerminalTab(tabId, filename, pane) {
+    appState.openTabs.set(tabId, {
+        fileName: filename,
+        type: 'terminal',
+        cwd: appState.currentDirectory,
+        dirty: false,
+        filePath: null,
+        savedContent: '',
+        lastContent: '',
+        pane: pane
+    });
+}
+
+// tabData.diff: { left, right, options } für die DiffView
+function createDiffTab(tabId, filename, pane, request) {
+    appState.openTabs.set(tabId, {
+        fileName: filename,
+        type: 'diff',
+        diff: request,
+        dirty: false,
+        filePath: null,
+        savedContent: '',
+        lastContent: '',
+        pane: pane
+    });
+}
+
+// tabData.large: { path } für die LargeFileView
+function createLargeTab(tabId, filename, pane, request) {
+    appState.openTabs.set(tabId, {
+        fileName: filename,
+        type: 'large',
+        large: request,
+        dirty: false,
+        filePath: request.path,
+        savedContent: '',
+        lastContent: '',
+        pane: pane
+    });
+}
+
+// tabData.hex: { path } für die HexView
+function createHexTab(tabId, filename, pane, request) {
+    appState.openTabs.set(tabId, {
+        fileName: filename,
+        type: 'hex',
+        hex: request,
+        dirty: false,
+        filePath: request.path,
+        savedContent: '',
+        lastContent: '',
+        pane: pane
+    });
+}
+
+// Öffnet Dateien, die nicht in den Editor geladen werden (siehe OpenMode):
+// 'large' seitenweise, 'hex' im Hex-Editor
+export function openFileInMode(path, mode, pane = appState.activePane || 'left') {
+    const existing = Array.from(appState.openTabs.entries()).find(([, tab]) => tab.type === mode && tab.filePath === path);
+    if (existing) {
+        editorManager.switchToTabInPane(existing[0], existing[1].pane);
+        return existing[0];
+    }
+    const name = path.split(/[/\\]/).pop() || path;
+    if (mode === 'hex') {
+        updateStatus(`${name} ist eine Binärdatei und wird im Hex-Editor geöffnet`);
+        return createNewTab(name, 'StarteHex', pane, { hex: { path } });
+    }
+    updateStatus(`${name} ist groß und wird seitenweise angezeigt`);
+    return createNewTab(name, 'StarteLarge', pane, { large: { path } });
+}
+
+// tabData.log: { path } für die LogView
+function createLogTab(tabId, filename, pane, request) {
+    appState.openTabs.set(tabId, {
+        fileName: filename,
+        type: 'log',
+        log: request,
+        dirty: false,
+        filePath: null,
+        savedContent: '',
+        lastContent: '',
+        pane: pane
+    });
+}
+
+// Öffnet eine Datei in der Log-Ansicht, die neue Zeilen laufend anhängt
+export function openLogTab(path, pane = appState.activePane || 'left') {
+    const existing = Array.from(appState.openTabs.entries()).find(([, tab]) => tab.type === 'log' && tab.log.path === path);
+    if (existing) {
+        editorManager.switchToTabInPane(existing[0], existing[1].pane);
+        return existing[0];
+    }
+    const name = path.split(/[/\\]/).pop() || path;
+    return createNewTab(`${name} (Log)`, 'StarteLog', pane, { log: { path } });
+}
+
 function createEditorTab(tabId, filename, content, pane) {
     const language = detectLanguage(filename);
     const langExtension = editorManager.getLanguageExtension(language);
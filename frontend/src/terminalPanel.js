@@ -0,0 +1,117 @@
+// Integrated terminal tab: xterm.js in the frontend, shell in a PTY of the Go backend
+import { Terminal } from '@xterm/xterm';
+import { FitAddon } from '@xterm/addon-fit';
+import '@xterm/xterm/css/xterm.css';
+import { EventsOn } from "../wailsjs/runtime/runtime.js";
+import { StartTerminal, WriteTerminal, ResizeTerminal, CloseTerminal } from '../wailsjs/go/main/App.js';
+import { Logger } from './logger.js';
+
+// Sessions by backend id, so the output events can be routed
+const sessions = new Map();
+
+// The shell can write (or exit) before StartTerminal has returned the id;
+// while a start is pending such events wait here by id
+const early = new Map();
+let starting = 0;
+
+function route(id, apply) {
+    const panel = sessions.get(id);
+    if (panel) {
+        apply(panel);
+    } else if (starting > 0) {
+        if (!early.has(id)) early.set(id, []);
+        early.get(id).push(apply);
+    }
+}
+
+EventsOn('terminal-output', ({ id, data }) => {
+    route(id, panel => panel.term.write(data));
+});
+
+EventsOn('terminal-exit', ({ id, code }) => {
+    route(id, panel => {
+        sessions.delete(id);
+        panel.sessionId = null;
+        panel.term.write(`\r\n\x1b[2m[Prozess beendet mit Code ${code}]\x1b[0m\r\n`);
+    });
+});
+
+export class TerminalPanel {
+    constructor(tabId, paneData, cwd = '') {
+        this.logger = new Logger('Terminal');
+        this.tabId = tabId;
+        this.paneData = paneData;
+        this.sessionId = null;
+
+        this.panel = document.createElement('div');
+        this.panel.id = `terminal-panel-${tabId}`;
+        this.panel.className = 'terminal-panel';
+        this.paneData.dom.appendChild(this.panel);
+
+        this.term = new Terminal({
+            cursorBlink: true,
+            fontFamily: 'Consolas, "DejaVu Sans Mono", monospace',
+            fontSize: 14,
+            scrollback: 5000
+        });
+        this.fitAddon = new FitAddon();
+        this.term.loadAddon(this.fitAddon);
+        this.term.open(this.panel);
+        this.fitAddon.fit();
+
+        this.term.onData(data => {
+            if (this.sessionId) WriteTerminal(this.sessionId, data);
+        });
+        this.term.onResize(({ cols, rows }) => {
+            if (this.sessionId) ResizeTerminal(this.sessionId, cols, rows);
+        });
+
+        this.resizeObserver = new ResizeObserver(() => this.fit());
+        this.resizeObserver.observe(this.panel);
+
+        this.start(cwd);
+    }
+
+    async start(cwd) {
+        starting++;
+        try {
+            const id = await StartTerminal(cwd, this.term.cols, this.term.rows);
+            this.sessionId = id;
+            sessions.set(id, this);
+            for (const apply of early.get(id) ?? []) apply(this);
+            early.delete(id);
+            this.term.focus();
+        } catch (err) {
+            this.logger.error('Failed to start terminal:', err);
+            this.term.write(`\x1b[31m${err}\x1b[0m\r\n`);
+        } finally {
+            if (--starting === 0) early.clear();
+        }
+    }
+
+    fit() {
+        // Versteckte Panels haben keine Größe
+        if (this.panel.offsetParent === null) return;
+        try {
+            this.fitAddon.fit();
+        } catch (e) {
+            this.logger.warn('Fit failed:', e);
+        }
+    }
+
+    show() {
+        this.panel.style.display = 'block';
+        this.fit();
+        this.term.focus();
+    }
+
+    dispose() {
+        this.resizeObserver.disconnect();
+        if (this.sessionId) {
+            sessions.delete(this.sessionId);
+            CloseTerminal(this.sessionId);
+        }
+        this.term.dispose();
+        this.panel.remove();
+    }
+}
@@ -42,6 +42,10 @@ export function SidepanelCloser(excludeMe = '') {
     const explorer = document.getElementById('folderlist');
     const outliner = document.getElementById('outliner');
     const recentFilesPanel = document.getElementById('recent-files-panel');
+    const problemsPanel = document.getElementById('problems-panel');
+    const testPanel = document.getElementById('test-panel');
+    const debugPanel = document.getElementById('debug-panel');
+    const historyPanel = document.getElementById('history-panel');
 
     if (explorer && excludeMe !== 'Explorer') {
         explorer.classList.add('hidden');
@@ -52,6 +56,18 @@ export function SidepanelCloser(excludeMe = '') {
     if (recentFilesPanel && excludeMe !== 'Recent Files') {
         recentFilesPanel.classList.add('hidden');
     }
+    if (problemsPanel && excludeMe !== 'Probleme') {
+        problemsPanel.classList.add('hidden');
+    }
+    if (testPanel && excludeMe !== 'Tests') {
+        testPanel.classList.add('hidden');
+    }
+    if (debugPanel && excludeMe !== 'Debugger') {
+        debugPanel.classList.add('hidden');
+    }
+    if (historyPanel && excludeMe !== 'Verlauf') {
+        historyPanel.classList.add('hidden');
+    }
 }
 
 
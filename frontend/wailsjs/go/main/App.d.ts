@@ -4,35 +4,173 @@ import {main} from '../models';
 
 export function AddRecentFile(arg1:string):Promise<string>;
 
+export function AddWorkspaceFolder(arg1:string):Promise<main.Workspace>;
+
+export function AddWorkspaceFolderDialog():Promise<main.Workspace>;
+
+export function CancelListDir(arg1:string):Promise<void>;
+
+export function ClearCoverage():Promise<void>;
+
 export function ClearRecentFiles():Promise<string>;
 
 export function CloseApp():Promise<void>;
 
+export function CloseLargeFile(arg1:string):Promise<void>;
+
+export function CloseLog(arg1:string):Promise<void>;
+
+export function CloseTerminal(arg1:string):Promise<void>;
+
+export function CloseWorkspace():Promise<void>;
+
+export function Compare(arg1:main.DiffSource,arg2:main.DiffSource,arg3:main.DiffOptions):Promise<main.DiffResult>;
+
+export function CompareDirectories(arg1:string,arg2:string,arg3:main.DirCompareOptions):Promise<main.DirCompareResult>;
+
+export function ComparePatch(arg1:main.DiffSource,arg2:main.DiffSource,arg3:main.DiffOptions):Promise<string>;
+
 export function CopyAction():Promise<void>;
 
+export function CopyCompareEntry(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<main.FileOpResult>;
+
+export function CopyPath(arg1:string,arg2:string):Promise<main.FileOpResult>;
+
+export function CreateFile(arg1:string):Promise<main.FileOpResult>;
+
+export function CreateFolder(arg1:string):Promise<main.FileOpResult>;
+
 export function CutAction():Promise<void>;
 
+export function DebugContinue():Promise<void>;
+
+export function DebugEvaluate(arg1:string,arg2:number):Promise<main.DebugVariable>;
+
+export function DebugGetBreakpoints():Promise<Record<string, Array<number>>>;
+
+export function DebugNext():Promise<void>;
+
+export function DebugPause():Promise<void>;
+
+export function DebugScopes(arg1:number):Promise<Array<main.DebugScope>>;
+
+export function DebugSetBreakpoints(arg1:string,arg2:Array<number>):Promise<Array<main.DebugBreakpoint>>;
+
+export function DebugStackTrace(arg1:number):Promise<Array<main.DebugStackFrame>>;
+
+export function DebugStart(arg1:string,arg2:string,arg3:number):Promise<void>;
+
+export function DebugStepIn():Promise<void>;
+
+export function DebugStepOut():Promise<void>;
+
+export function DebugStop():Promise<void>;
+
+export function DebugThreads():Promise<Array<main.DebugThread>>;
+
+export function DebugVariables(arg1:number):Promise<Array<main.DebugVariable>>;
+
+export function DuplicatePath(arg1:string):Promise<main.FileOpResult>;
+
 export function ExtractFilePath(arg1:string):Promise<string>;
 
 export function ExtractFilePaths(arg1:string):Promise<Array<string>>;
 
+export function FindDefinition(arg1:string,arg2:string):Promise<Array<main.IndexedSymbol>>;
+
+export function ForgetPassphrase(arg1:string):Promise<void>;
+
+export function FormatBuffer(arg1:string,arg2:string):Promise<main.FormatResult>;
+
 export function GetAppTitle():Promise<string>;
 
+export function GetCoverage(arg1:string):Promise<Array<main.CoverageBlock>>;
+
+export function GetEditorConfig(arg1:string):Promise<main.EditorConfig>;
+
+export function GetFormatOnSave():Promise<boolean>;
+
 export function GetLastDirectory():Promise<string>;
 
 export function GetOpenedFilePath():Promise<string>;
 
+export function GetOutline(arg1:string,arg2:string):Promise<Array<main.OutlineSymbol>>;
+
+export function GetProblems():Promise<Array<main.Problem>>;
+
 export function GetRecentFiles():Promise<Array<string>>;
 
+export function GetRecentWorkspaces():Promise<Array<string>>;
+
+export function GetRunners():Promise<Record<string, main.RunnerConfig>>;
+
+export function GetSettings():Promise<main.SettingsResult>;
+
+export function GetSettingsSchema():Promise<Array<main.SettingDef>>;
+
 export function GetStaticHTML():Promise<string>;
 
+export function GetTasks(arg1:string):Promise<main.TaskList>;
+
+export function GetWorkspace():Promise<main.Workspace>;
+
 export function HandleFileDrop(arg1:number,arg2:number,arg3:Array<string>):Promise<void>;
 
 export function HasUnsavedChanges():Promise<boolean>;
 
+export function HexDelete(arg1:string,arg2:number,arg3:number):Promise<main.LargeFileInfo>;
+
+export function HexRead(arg1:string,arg2:number,arg3:number):Promise<string>;
+
+export function HexSearch(arg1:string,arg2:string,arg3:main.HexSearchOptions):Promise<number>;
+
+export function HexWrite(arg1:string,arg2:number,arg3:string,arg4:boolean):Promise<main.LargeFileInfo>;
+
+export function HistoryDiff(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function HistoryGet(arg1:string,arg2:string):Promise<string>;
+
+export function HistoryList(arg1:string):Promise<Array<main.HistoryRevision>>;
+
+export function HistoryRestore(arg1:string,arg2:string):Promise<string>;
+
 export function HomeDir():Promise<string>;
 
-export function ListDir(arg1:string):Promise<Array<Record<string, any>>>;
+export function IndexWorkspace(arg1:string):Promise<string>;
+
+export function LSPChangeDocument(arg1:string,arg2:string):Promise<void>;
+
+export function LSPCloseDocument(arg1:string):Promise<void>;
+
+export function LSPCompletion(arg1:string,arg2:number,arg3:number):Promise<Array<main.LSPCompletionItem>>;
+
+export function LSPDefinition(arg1:string,arg2:number,arg3:number):Promise<Array<main.LSPLocation>>;
+
+export function LSPHover(arg1:string,arg2:number,arg3:number):Promise<string>;
+
+export function LSPOpenDocument(arg1:string,arg2:string):Promise<void>;
+
+export function LSPReferences(arg1:string,arg2:number,arg3:number):Promise<Array<main.LSPLocation>>;
+
+export function LSPRename(arg1:string,arg2:number,arg3:number,arg4:string):Promise<Array<main.LSPFileEdits>>;
+
+export function LSPSaveDocument(arg1:string):Promise<void>;
+
+export function LargeFileEdit(arg1:string,arg2:number,arg3:number,arg4:string):Promise<main.LargeFileInfo>;
+
+export function LargeFileLines(arg1:string,arg2:number,arg3:number):Promise<main.LargeFileWindow>;
+
+export function LargeFileSave(arg1:string):Promise<main.LargeFileInfo>;
+
+export function LargeFileSearch(arg1:string,arg2:string,arg3:main.LargeFileSearchOptions):Promise<Array<main.LargeFileMatch>>;
+
+export function ListDir(arg1:string,arg2:main.ListDirOptions):Promise<Array<main.DirEntry>>;
+
+export function ListDirStream(arg1:string,arg2:main.ListDirOptions,arg3:number):Promise<string>;
+
+export function ListProcesses():Promise<Array<main.ProcessInfo>>;
+
+export function ListTrash():Promise<Array<main.TrashItem>>;
 
 export function LoadFile():Promise<main.FileResult>;
 
@@ -42,12 +180,36 @@ export function MarkFileAsSaved(arg1:string):Promise<void>;
 
 export function MarkFileAsUnsaved(arg1:string):Promise<void>;
 
+export function MovePath(arg1:string,arg2:string):Promise<main.FileOpResult>;
+
 export function OpenFileDialog(arg1:string):Promise<string>;
 
+export function OpenFolderDialog():Promise<main.Workspace>;
+
+export function OpenHexFile(arg1:string):Promise<main.LargeFileInfo>;
+
+export function OpenLargeFile(arg1:string):Promise<main.LargeFileInfo>;
+
+export function OpenLog(arg1:string,arg2:main.LogFilter):Promise<main.LogSession>;
+
+export function OpenMode(arg1:string):Promise<string>;
+
+export function OpenRemote(arg1:string):Promise<string>;
+
+export function OpenSettingsFile():Promise<string>;
+
+export function OpenWorkspace(arg1:string):Promise<main.Workspace>;
+
+export function OpenWorkspaceDialog():Promise<main.Workspace>;
+
 export function PasteAction():Promise<void>;
 
+export function PauseLog(arg1:string,arg2:boolean):Promise<void>;
+
 export function Ping(arg1:string):Promise<string>;
 
+export function ProvidePassphrase(arg1:string,arg2:string):Promise<void>;
+
 export function ProxyURL(arg1:string):Promise<string>;
 
 export function QueryOpenRouter(arg1:string,arg2:string):Promise<void>;
@@ -60,14 +222,60 @@ export function RedoAction():Promise<void>;
 
 export function RemoveRecentFile(arg1:string):Promise<string>;
 
+export function RemoveRecentWorkspace(arg1:string):Promise<Array<string>>;
+
+export function RemoveWorkspaceFolder(arg1:string):Promise<main.Workspace>;
+
+export function RenamePath(arg1:string,arg2:string):Promise<main.FileOpResult>;
+
 export function RequestClose():Promise<void>;
 
+export function ResizeTerminal(arg1:string,arg2:number,arg3:number):Promise<void>;
+
+export function RestoreTrash(arg1:string):Promise<main.FileOpResult>;
+
+export function RunFile(arg1:string):Promise<main.ProcessInfo>;
+
+export function RunGoTests(arg1:string,arg2:string,arg3:number,arg4:boolean):Promise<main.ProcessInfo>;
+
+export function RunLinters(arg1:string):Promise<void>;
+
+export function RunTask(arg1:string,arg2:string):Promise<main.ProcessInfo>;
+
+export function SaveComparePatch(arg1:main.DiffSource,arg2:main.DiffSource,arg3:main.DiffOptions):Promise<string>;
+
 export function SaveFile(arg1:string,arg2:string,arg3:boolean):Promise<boolean>;
 
 export function SaveFileUnder(arg1:string,arg2:string):Promise<string>;
 
+export function SaveWorkspaceAs():Promise<main.Workspace>;
+
+export function SelectCompareDirectory(arg1:string):Promise<string>;
+
+export function SelectCompareFile(arg1:string):Promise<string>;
+
 export function SetAppTitle(arg1:string):Promise<void>;
 
+export function SetExecutable(arg1:string,arg2:boolean):Promise<main.FileOpResult>;
+
+export function SetFormatOnSave(arg1:boolean):Promise<string>;
+
+export function SetRunner(arg1:string,arg2:main.RunnerConfig):Promise<string>;
+
+export function SetSetting(arg1:string,arg2:any):Promise<void>;
+
 export function SetUnsavedChanges(arg1:boolean):Promise<void>;
 
+export function StartTerminal(arg1:string,arg2:number,arg3:number):Promise<string>;
+
+export function StopProcess(arg1:string):Promise<void>;
+
+export function TakeOpenRequests():Promise<Array<main.OpenRequest>>;
+
+export function TrashPath(arg1:string):Promise<main.FileOpResult>;
+
 export function UndoAction():Promise<void>;
+
+export function WorkspaceSymbols(arg1:string,arg2:number):Promise<Array<main.IndexedSymbol>>;
+
+export function WriteTerminal(arg1:string,arg2:string):Promise<void>;
@@ -6,6 +6,22 @@ export function AddRecentFile(arg1) {
   return window['go']['main']['App']['AddRecentFile'](arg1);
 }
 
+export function AddWorkspaceFolder(arg1) {
+  return window['go']['main']['App']['AddWorkspaceFolder'](arg1);
+}
+
+export function AddWorkspaceFolderDialog() {
+  return window['go']['main']['App']['AddWorkspaceFolderDialog']();
+}
+
+export function CancelListDir(arg1) {
+  return window['go']['main']['App']['CancelListDir'](arg1);
+}
+
+export function ClearCoverage() {
+  return window['go']['main']['App']['ClearCoverage']();
+}
+
 export function ClearRecentFiles() {
   return window['go']['main']['App']['ClearRecentFiles']();
 }
@@ -14,14 +30,118 @@ export function CloseApp() {
   return window['go']['main']['App']['CloseApp']();
 }
 
+export function CloseLargeFile(arg1) {
+  return window['go']['main']['App']['CloseLargeFile'](arg1);
+}
+
+export function CloseLog(arg1) {
+  return window['go']['main']['App']['CloseLog'](arg1);
+}
+
+export function CloseTerminal(arg1) {
+  return window['go']['main']['App']['CloseTerminal'](arg1);
+}
+
+export function CloseWorkspace() {
+  return window['go']['main']['App']['CloseWorkspace']();
+}
+
+export function Compare(arg1, arg2, arg3) {
+  return window['go']['main']['App']['Compare'](arg1, arg2, arg3);
+}
+
+export function CompareDirectories(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CompareDirectories'](arg1, arg2, arg3);
+}
+
+export function ComparePatch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ComparePatch'](arg1, arg2, arg3);
+}
+
 export function CopyAction() {
   return window['go']['main']['App']['CopyAction']();
 }
 
+export function CopyCompareEntry(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CopyCompareEntry'](arg1, arg2, arg3, arg4);
+}
+
+export function CopyPath(arg1, arg2) {
+  return window['go']['main']['App']['CopyPath'](arg1, arg2);
+}
+
+export function CreateFile(arg1) {
+  return window['go']['main']['App']['CreateFile'](arg1);
+}
+
+export function CreateFolder(arg1) {
+  return window['go']['main']['App']['CreateFolder'](arg1);
+}
+
 export function CutAction() {
   return window['go']['main']['App']['CutAction']();
 }
 
+export function DebugContinue() {
+  return window['go']['main']['App']['DebugContinue']();
+}
+
+export function DebugEvaluate(arg1, arg2) {
+  return window['go']['main']['App']['DebugEvaluate'](arg1, arg2);
+}
+
+export function DebugGetBreakpoints() {
+  return window['go']['main']['App']['DebugGetBreakpoints']();
+}
+
+export function DebugNext() {
+  return window['go']['main']['App']['DebugNext']();
+}
+
+export function DebugPause() {
+  return window['go']['main']['App']['DebugPause']();
+}
+
+export function DebugScopes(arg1) {
+  return window['go']['main']['App']['DebugScopes'](arg1);
+}
+
+export function DebugSetBreakpoints(arg1, arg2) {
+  return window['go']['main']['App']['DebugSetBreakpoints'](arg1, arg2);
+}
+
+export function DebugStackTrace(arg1) {
+  return window['go']['main']['App']['DebugStackTrace'](arg1);
+}
+
+export function DebugStart(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DebugStart'](arg1, arg2, arg3);
+}
+
+export function DebugStepIn() {
+  return window['go']['main']['App']['DebugStepIn']();
+}
+
+export function DebugStepOut() {
+  return window['go']['main']['App']['DebugStepOut']();
+}
+
+export function DebugStop() {
+  return window['go']['main']['App']['DebugStop']();
+}
+
+export function DebugThreads() {
+  return window['go']['main']['App']['DebugThreads']();
+}
+
+export function DebugVariables(arg1) {
+  return window['go']['main']['App']['DebugVariables'](arg1);
+}
+
+export function DuplicatePath(arg1) {
+  return window['go']['main']['App']['DuplicatePath'](arg1);
+}
+
 export function ExtractFilePath(arg1) {
   return window['go']['main']['App']['ExtractFilePath'](arg1);
 }
@@ -30,10 +150,34 @@ export function ExtractFilePaths(arg1) {
   return window['go']['main']['App']['ExtractFilePaths'](arg1);
 }
 
+export function FindDefinition(arg1, arg2) {
+  return window['go']['main']['App']['FindDefinition'](arg1, arg2);
+}
+
+export function ForgetPassphrase(arg1) {
+  return window['go']['main']['App']['ForgetPassphrase'](arg1);
+}
+
+export function FormatBuffer(arg1, arg2) {
+  return window['go']['main']['App']['FormatBuffer'](arg1, arg2);
+}
+
 export function GetAppTitle() {
   return window['go']['main']['App']['GetAppTitle']();
 }
 
+export function GetCoverage(arg1) {
+  return window['go']['main']['App']['GetCoverage'](arg1);
+}
+
+export function GetEditorConfig(arg1) {
+  return window['go']['main']['App']['GetEditorConfig'](arg1);
+}
+
+export function GetFormatOnSave() {
+  return window['go']['main']['App']['GetFormatOnSave']();
+}
+
 export function GetLastDirectory() {
   return window['go']['main']['App']['GetLastDirectory']();
 }
@@ -42,14 +186,46 @@ export function GetOpenedFilePath() {
   return window['go']['main']['App']['GetOpenedFilePath']();
 }
 
+export function GetOutline(arg1, arg2) {
+  return window['go']['main']['App']['GetOutline'](arg1, arg2);
+}
+
+export function GetProblems() {
+  return window['go']['main']['App']['GetProblems']();
+}
+
 export function GetRecentFiles() {
   return window['go']['main']['App']['GetRecentFiles']();
 }
 
+export function GetRecentWorkspaces() {
+  return window['go']['main']['App']['GetRecentWorkspaces']();
+}
+
+export function GetRunners() {
+  return window['go']['main']['App']['GetRunners']();
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function GetSettingsSchema() {
+  return window['go']['main']['App']['GetSettingsSchema']();
+}
+
 export function GetStaticHTML() {
   return window['go']['main']['App']['GetStaticHTML']();
 }
 
+export function GetTasks(arg1) {
+  return window['go']['main']['App']['GetTasks'](arg1);
+}
+
+export function GetWorkspace() {
+  return window['go']['main']['App']['GetWorkspace']();
+}
+
 export function HandleFileDrop(arg1, arg2, arg3) {
   return window['go']['main']['App']['HandleFileDrop'](arg1, arg2, arg3);
 }
@@ -58,12 +234,112 @@ export function HasUnsavedChanges() {
   return window['go']['main']['App']['HasUnsavedChanges']();
 }
 
+export function HexDelete(arg1, arg2, arg3) {
+  return window['go']['main']['App']['HexDelete'](arg1, arg2, arg3);
+}
+
+export function HexRead(arg1, arg2, arg3) {
+  return window['go']['main']['App']['HexRead'](arg1, arg2, arg3);
+}
+
+export function HexSearch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['HexSearch'](arg1, arg2, arg3);
+}
+
+export function HexWrite(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['HexWrite'](arg1, arg2, arg3, arg4);
+}
+
+export function HistoryDiff(arg1, arg2, arg3) {
+  return window['go']['main']['App']['HistoryDiff'](arg1, arg2, arg3);
+}
+
+export function HistoryGet(arg1, arg2) {
+  return window['go']['main']['App']['HistoryGet'](arg1, arg2);
+}
+
+export function HistoryList(arg1) {
+  return window['go']['main']['App']['HistoryList'](arg1);
+}
+
+export function HistoryRestore(arg1, arg2) {
+  return window['go']['main']['App']['HistoryRestore'](arg1, arg2);
+}
+
 export function HomeDir() {
   return window['go']['main']['App']['HomeDir']();
 }
 
-export function ListDir(arg1) {
-  return window['go']['main']['App']['ListDir'](arg1);
+export function IndexWorkspace(arg1) {
+  return window['go']['main']['App']['IndexWorkspace'](arg1);
+}
+
+export function LSPChangeDocument(arg1, arg2) {
+  return window['go']['main']['App']['LSPChangeDocument'](arg1, arg2);
+}
+
+export function LSPCloseDocument(arg1) {
+  return window['go']['main']['App']['LSPCloseDocument'](arg1);
+}
+
+export function LSPCompletion(arg1, arg2, arg3) {
+  return window['go']['main']['App']['LSPCompletion'](arg1, arg2, arg3);
+}
+
+export function LSPDefinition(arg1, arg2, arg3) {
+  return window['go']['main']['App']['LSPDefinition'](arg1, arg2, arg3);
+}
+
+export function LSPHover(arg1, arg2, arg3) {
+  return window['go']['main']['App']['LSPHover'](arg1, arg2, arg3);
+}
+
+export function LSPOpenDocument(arg1, arg2) {
+  return window['go']['main']['App']['LSPOpenDocument'](arg1, arg2);
+}
+
+export function LSPReferences(arg1, arg2, arg3) {
+  return window['go']['main']['App']['LSPReferences'](arg1, arg2, arg3);
+}
+
+export function LSPRename(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['LSPRename'](arg1, arg2, arg3, arg4);
+}
+
+export function LSPSaveDocument(arg1) {
+  return window['go']['main']['App']['LSPSaveDocument'](arg1);
+}
+
+export function LargeFileEdit(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['LargeFileEdit'](arg1, arg2, arg3, arg4);
+}
+
+export function LargeFileLines(arg1, arg2, arg3) {
+  return window['go']['main']['App']['LargeFileLines'](arg1, arg2, arg3);
+}
+
+export function LargeFileSave(arg1) {
+  return window['go']['main']['App']['LargeFileSave'](arg1);
+}
+
+export function LargeFileSearch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['LargeFileSearch'](arg1, arg2, arg3);
+}
+
+export function ListDir(arg1, arg2) {
+  return window['go']['main']['App']['ListDir'](arg1, arg2);
+}
+
+export function ListDirStream(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ListDirStream'](arg1, arg2, arg3);
+}
+
+export function ListProcesses() {
+  return window['go']['main']['App']['ListProcesses']();
+}
+
+export function ListTrash() {
+  return window['go']['main']['App']['ListTrash']();
 }
 
 export function LoadFile() {
@@ -82,18 +358,66 @@ export function MarkFileAsUnsaved(arg1) {
   return window['go']['main']['App']['MarkFileAsUnsaved'](arg1);
 }
 
+export function MovePath(arg1, arg2) {
+  return window['go']['main']['App']['MovePath'](arg1, arg2);
+}
+
 export function OpenFileDialog(arg1) {
   return window['go']['main']['App']['OpenFileDialog'](arg1);
 }
 
+export function OpenFolderDialog() {
+  return window['go']['main']['App']['OpenFolderDialog']();
+}
+
+export function OpenHexFile(arg1) {
+  return window['go']['main']['App']['OpenHexFile'](arg1);
+}
+
+export function OpenLargeFile(arg1) {
+  return window['go']['main']['App']['OpenLargeFile'](arg1);
+}
+
+export function OpenLog(arg1, arg2) {
+  return window['go']['main']['App']['OpenLog'](arg1, arg2);
+}
+
+export function OpenMode(arg1) {
+  return window['go']['main']['App']['OpenMode'](arg1);
+}
+
+export function OpenRemote(arg1) {
+  return window['go']['main']['App']['OpenRemote'](arg1);
+}
+
+export function OpenSettingsFile() {
+  return window['go']['main']['App']['OpenSettingsFile']();
+}
+
+export function OpenWorkspace(arg1) {
+  return window['go']['main']['App']['OpenWorkspace'](arg1);
+}
+
+export function OpenWorkspaceDialog() {
+  return window['go']['main']['App']['OpenWorkspaceDialog']();
+}
+
 export function PasteAction() {
   return window['go']['main']['App']['PasteAction']();
 }
 
+export function PauseLog(arg1, arg2) {
+  return window['go']['main']['App']['PauseLog'](arg1, arg2);
+}
+
 export function Ping(arg1) {
   return window['go']['main']['App']['Ping'](arg1);
 }
 
+export function ProvidePassphrase(arg1, arg2) {
+  return window['go']['main']['App']['ProvidePassphrase'](arg1, arg2);
+}
+
 export function ProxyURL(arg1) {
   return window['go']['main']['App']['ProxyURL'](arg1);
 }
@@ -118,10 +442,50 @@ export function RemoveRecentFile(arg1) {
   return window['go']['main']['App']['RemoveRecentFile'](arg1);
 }
 
+export function RemoveRecentWorkspace(arg1) {
+  return window['go']['main']['App']['RemoveRecentWorkspace'](arg1);
+}
+
+export function RemoveWorkspaceFolder(arg1) {
+  return window['go']['main']['App']['RemoveWorkspaceFolder'](arg1);
+}
+
+export function RenamePath(arg1, arg2) {
+  return window['go']['main']['App']['RenamePath'](arg1, arg2);
+}
+
 export function RequestClose() {
   return window['go']['main']['App']['RequestClose']();
 }
 
+export function ResizeTerminal(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ResizeTerminal'](arg1, arg2, arg3);
+}
+
+export function RestoreTrash(arg1) {
+  return window['go']['main']['App']['RestoreTrash'](arg1);
+}
+
+export function RunFile(arg1) {
+  return window['go']['main']['App']['RunFile'](arg1);
+}
+
+export function RunGoTests(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['RunGoTests'](arg1, arg2, arg3, arg4);
+}
+
+export function RunLinters(arg1) {
+  return window['go']['main']['App']['RunLinters'](arg1);
+}
+
+export function RunTask(arg1, arg2) {
+  return window['go']['main']['App']['RunTask'](arg1, arg2);
+}
+
+export function SaveComparePatch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SaveComparePatch'](arg1, arg2, arg3);
+}
+
 export function SaveFile(arg1, arg2, arg3) {
   return window['go']['main']['App']['SaveFile'](arg1, arg2, arg3);
 }
@@ -130,14 +494,66 @@ export function SaveFileUnder(arg1, arg2) {
   return window['go']['main']['App']['SaveFileUnder'](arg1, arg2);
 }
 
+export function SaveWorkspaceAs() {
+  return window['go']['main']['App']['SaveWorkspaceAs']();
+}
+
+export function SelectCompareDirectory(arg1) {
+  return window['go']['main']['App']['SelectCompareDirectory'](arg1);
+}
+
+export function SelectCompareFile(arg1) {
+  return window['go']['main']['App']['SelectCompareFile'](arg1);
+}
+
 export function SetAppTitle(arg1) {
   return window['go']['main']['App']['SetAppTitle'](arg1);
 }
 
+export function SetExecutable(arg1, arg2) {
+  return window['go']['main']['App']['SetExecutable'](arg1, arg2);
+}
+
+export function SetFormatOnSave(arg1) {
+  return window['go']['main']['App']['SetFormatOnSave'](arg1);
+}
+
+export function SetRunner(arg1, arg2) {
+  return window['go']['main']['App']['SetRunner'](arg1, arg2);
+}
+
+export function SetSetting(arg1, arg2) {
+  return window['go']['main']['App']['SetSetting'](arg1, arg2);
+}
+
 export function SetUnsavedChanges(arg1) {
   return window['go']['main']['App']['SetUnsavedChanges'](arg1);
 }
 
+export function StartTerminal(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StartTerminal'](arg1, arg2, arg3);
+}
+
+export function StopProcess(arg1) {
+  return window['go']['main']['App']['StopProcess'](arg1);
+}
+
+export function TakeOpenRequests() {
+  return window['go']['main']['App']['TakeOpenRequests']();
+}
+
+export function TrashPath(arg1) {
+  return window['go']['main']['App']['TrashPath'](arg1);
+}
+
 export function UndoAction() {
   return window['go']['main']['App']['UndoAction']();
 }
+
+export function WorkspaceSymbols(arg1, arg2) {
+  return window['go']['main']['App']['WorkspaceSymbols'](arg1, arg2);
+}
+
+export function WriteTerminal(arg1, arg2) {
+  return window['go']['main']['App']['WriteTerminal'](arg1, arg2);
+}
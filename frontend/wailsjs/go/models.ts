@@ -1,9 +1,526 @@
 export namespace main {
 	
+	export class ArchiveSettings {
+	    writable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ArchiveSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.writable = source["writable"];
+	    }
+	}
+	export class CoverageBlock {
+	    startLine: number;
+	    startCol: number;
+	    endLine: number;
+	    endCol: number;
+	    covered: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CoverageBlock(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.startLine = source["startLine"];
+	        this.startCol = source["startCol"];
+	        this.endLine = source["endLine"];
+	        this.endCol = source["endCol"];
+	        this.covered = source["covered"];
+	    }
+	}
+	export class DebugBreakpoint {
+	    line: number;
+	    verified: boolean;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DebugBreakpoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.verified = source["verified"];
+	        this.message = source["message"];
+	    }
+	}
+	export class DebugScope {
+	    name: string;
+	    variablesReference: number;
+	    expensive: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DebugScope(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.variablesReference = source["variablesReference"];
+	        this.expensive = source["expensive"];
+	    }
+	}
+	export class DebugStackFrame {
+	    id: number;
+	    name: string;
+	    path: string;
+	    line: number;
+	    column: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DebugStackFrame(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	    }
+	}
+	export class DebugThread {
+	    id: number;
+	    name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DebugThread(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	    }
+	}
+	export class DebugVariable {
+	    name: string;
+	    value: string;
+	    type: string;
+	    variablesReference: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DebugVariable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.value = source["value"];
+	        this.type = source["type"];
+	        this.variablesReference = source["variablesReference"];
+	    }
+	}
+	export class DiffSegment {
+	    text: string;
+	    changed: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffSegment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.changed = source["changed"];
+	    }
+	}
+	export class DiffRow {
+	    kind: string;
+	    leftLine: number;
+	    rightLine: number;
+	    left: DiffSegment[];
+	    right: DiffSegment[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.leftLine = source["leftLine"];
+	        this.rightLine = source["rightLine"];
+	        this.left = this.convertValues(source["left"], DiffSegment);
+	        this.right = this.convertValues(source["right"], DiffSegment);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DiffHunk {
+	    leftStart: number;
+	    leftCount: number;
+	    rightStart: number;
+	    rightCount: number;
+	    rows: DiffRow[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffHunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.leftStart = source["leftStart"];
+	        this.leftCount = source["leftCount"];
+	        this.rightStart = source["rightStart"];
+	        this.rightCount = source["rightCount"];
+	        this.rows = this.convertValues(source["rows"], DiffRow);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DiffOptions {
+	    ignoreWhitespace: boolean;
+	    ignoreCase: boolean;
+	    patience: boolean;
+	    context: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ignoreWhitespace = source["ignoreWhitespace"];
+	        this.ignoreCase = source["ignoreCase"];
+	        this.patience = source["patience"];
+	        this.context = source["context"];
+	    }
+	}
+	export class DiffResult {
+	    leftName: string;
+	    rightName: string;
+	    hunks: DiffHunk[];
+	    added: number;
+	    removed: number;
+	    changed: number;
+	    identical: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.leftName = source["leftName"];
+	        this.rightName = source["rightName"];
+	        this.hunks = this.convertValues(source["hunks"], DiffHunk);
+	        this.added = source["added"];
+	        this.removed = source["removed"];
+	        this.changed = source["changed"];
+	        this.identical = source["identical"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class DiffSource {
+	    kind: string;
+	    name: string;
+	    path: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.text = source["text"];
+	    }
+	}
+	export class DirCompareEntry {
+	    path: string;
+	    name: string;
+	    depth: number;
+	    isDir: boolean;
+	    status: string;
+	    leftSize: number;
+	    rightSize: number;
+	    leftModified: number;
+	    rightModified: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DirCompareEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.depth = source["depth"];
+	        this.isDir = source["isDir"];
+	        this.status = source["status"];
+	        this.leftSize = source["leftSize"];
+	        this.rightSize = source["rightSize"];
+	        this.leftModified = source["leftModified"];
+	        this.rightModified = source["rightModified"];
+	        this.error = source["error"];
+	    }
+	}
+	export class DirCompareOptions {
+	    ignore: string[];
+	    thorough: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DirCompareOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ignore = source["ignore"];
+	        this.thorough = source["thorough"];
+	    }
+	}
+	export class DirCompareResult {
+	    left: string;
+	    right: string;
+	    entries: DirCompareEntry[];
+	    added: number;
+	    removed: number;
+	    changed: number;
+	    identical: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DirCompareResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.left = source["left"];
+	        this.right = source["right"];
+	        this.entries = this.convertValues(source["entries"], DirCompareEntry);
+	        this.added = source["added"];
+	        this.removed = source["removed"];
+	        this.changed = source["changed"];
+	        this.identical = source["identical"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DirEntry {
+	    name: string;
+	    isDir: boolean;
+	    size: number;
+	    modTime: number;
+	    mode: string;
+	    perm: number;
+	    isSymlink: boolean;
+	    target?: string;
+	    broken?: boolean;
+	    hidden: boolean;
+	    archive?: boolean;
+	    mime: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DirEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.isDir = source["isDir"];
+	        this.size = source["size"];
+	        this.modTime = source["modTime"];
+	        this.mode = source["mode"];
+	        this.perm = source["perm"];
+	        this.isSymlink = source["isSymlink"];
+	        this.target = source["target"];
+	        this.broken = source["broken"];
+	        this.hidden = source["hidden"];
+	        this.archive = source["archive"];
+	        this.mime = source["mime"];
+	    }
+	}
+	export class EditorConfig {
+	    indentStyle: string;
+	    indentSize: number;
+	    tabWidth: number;
+	    endOfLine: string;
+	    charset: string;
+	    trimTrailingWhitespace: boolean;
+	    insertFinalNewline?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EditorConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.indentStyle = source["indentStyle"];
+	        this.indentSize = source["indentSize"];
+	        this.tabWidth = source["tabWidth"];
+	        this.endOfLine = source["endOfLine"];
+	        this.charset = source["charset"];
+	        this.trimTrailingWhitespace = source["trimTrailingWhitespace"];
+	        this.insertFinalNewline = source["insertFinalNewline"];
+	    }
+	}
+	export class EditorSettings {
+	    font_size: number;
+	    tab_size: number;
+	    indent_style: string;
+	    word_wrap: boolean;
+	    line_numbers: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EditorSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.font_size = source["font_size"];
+	        this.tab_size = source["tab_size"];
+	        this.indent_style = source["indent_style"];
+	        this.word_wrap = source["word_wrap"];
+	        this.line_numbers = source["line_numbers"];
+	    }
+	}
+	export class ExplorerSettings {
+	    show_hidden: boolean;
+	    hide_ignored: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExplorerSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.show_hidden = source["show_hidden"];
+	        this.hide_ignored = source["hide_ignored"];
+	    }
+	}
+	export class FileOpError {
+	    op: string;
+	    path: string;
+	    code: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileOpError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.op = source["op"];
+	        this.path = source["path"];
+	        this.code = source["code"];
+	        this.message = source["message"];
+	    }
+	}
+	export class FileOpResult {
+	    path: string;
+	    error?: FileOpError;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileOpResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.error = this.convertValues(source["error"], FileOpError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class FileResult {
 	    content: string;
 	    filename: string;
 	    error: string;
+	    editorConfig: EditorConfig;
+	    charset?: string;
+	    mode?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new FileResult(source);
@@ -14,8 +531,964 @@ export namespace main {
 	        this.content = source["content"];
 	        this.filename = source["filename"];
 	        this.error = source["error"];
+	        this.editorConfig = this.convertValues(source["editorConfig"], EditorConfig);
+	        this.charset = source["charset"];
+	        this.mode = source["mode"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FormatError {
+	    line: number;
+	    column: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FormatError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.message = source["message"];
+	    }
+	}
+	export class FormatResult {
+	    content: string;
+	    changed: boolean;
+	    errors: FormatError[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FormatResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.content = source["content"];
+	        this.changed = source["changed"];
+	        this.errors = this.convertValues(source["errors"], FormatError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FormatterConfig {
+	    command: string;
+	    args: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FormatterConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.command = source["command"];
+	        this.args = source["args"];
+	    }
+	}
+	export class HexSearchOptions {
+	    text: boolean;
+	    from: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HexSearchOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.from = source["from"];
+	    }
+	}
+	export class HistoryRevision {
+	    id: string;
+	    // Go type: time
+	    time: any;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryRevision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.size = source["size"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistorySettings {
+	    enabled: boolean;
+	    max_revisions: number;
+	    max_age_days: number;
+	    max_size_mb: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistorySettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.max_revisions = source["max_revisions"];
+	        this.max_age_days = source["max_age_days"];
+	        this.max_size_mb = source["max_size_mb"];
+	    }
+	}
+	export class IndexedSymbol {
+	    name: string;
+	    kind: string;
+	    container: string;
+	    path: string;
+	    line: number;
+	    character: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new IndexedSymbol(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.kind = source["kind"];
+	        this.container = source["container"];
+	        this.path = source["path"];
+	        this.line = source["line"];
+	        this.character = source["character"];
+	    }
+	}
+	export class LSPCompletionItem {
+	    label: string;
+	    kind: number;
+	    detail: string;
+	    documentation: string;
+	    insertText: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LSPCompletionItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.kind = source["kind"];
+	        this.detail = source["detail"];
+	        this.documentation = source["documentation"];
+	        this.insertText = source["insertText"];
+	    }
+	}
+	export class LSPPosition {
+	    line: number;
+	    character: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LSPPosition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.character = source["character"];
+	    }
+	}
+	export class LSPRange {
+	    start: LSPPosition;
+	    end: LSPPosition;
+	
+	    static createFrom(source: any = {}) {
+	        return new LSPRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = this.convertValues(source["start"], LSPPosition);
+	        this.end = this.convertValues(source["end"], LSPPosition);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LSPTextEdit {
+	    range: LSPRange;
+	    newText: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LSPTextEdit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.range = this.convertValues(source["range"], LSPRange);
+	        this.newText = source["newText"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LSPFileEdits {
+	    path: string;
+	    edits: LSPTextEdit[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LSPFileEdits(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.edits = this.convertValues(source["edits"], LSPTextEdit);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LSPLocation {
+	    path: string;
+	    range: LSPRange;
+	
+	    static createFrom(source: any = {}) {
+	        return new LSPLocation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.range = this.convertValues(source["range"], LSPRange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class LargeFileInfo {
+	    id: string;
+	    path: string;
+	    size: number;
+	    lines: number;
+	    modified: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LargeFileInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.lines = source["lines"];
+	        this.modified = source["modified"];
+	    }
+	}
+	export class LargeFileMatch {
+	    line: number;
+	    column: number;
+	    length: number;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LargeFileMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.length = source["length"];
+	        this.text = source["text"];
+	    }
+	}
+	export class LargeFileSearchOptions {
+	    regex: boolean;
+	    caseSensitive: boolean;
+	    maxResults: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LargeFileSearchOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.regex = source["regex"];
+	        this.caseSensitive = source["caseSensitive"];
+	        this.maxResults = source["maxResults"];
+	    }
+	}
+	export class LargeFileWindow {
+	    start: number;
+	    lines: string[];
+	    truncated: number[];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LargeFileWindow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = source["start"];
+	        this.lines = source["lines"];
+	        this.truncated = source["truncated"];
+	        this.total = source["total"];
+	    }
+	}
+	export class LinterConfig {
+	    name: string;
+	    command: string;
+	    args: string[];
+	    pattern?: string;
+	    severity?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LinterConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.pattern = source["pattern"];
+	        this.severity = source["severity"];
+	    }
+	}
+	export class ListDirOptions {
+	    showHidden: boolean;
+	    hideIgnored: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ListDirOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.showHidden = source["showHidden"];
+	        this.hideIgnored = source["hideIgnored"];
+	    }
+	}
+	export class LogFilter {
+	    pattern: string;
+	    caseSensitive: boolean;
+	    levels: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LogFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pattern = source["pattern"];
+	        this.caseSensitive = source["caseSensitive"];
+	        this.levels = source["levels"];
+	    }
+	}
+	export class LogLine {
+	    number: number;
+	    text: string;
+	    level?: string;
+	    time?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.number = source["number"];
+	        this.text = source["text"];
+	        this.level = source["level"];
+	        this.time = source["time"];
+	    }
+	}
+	export class LogSession {
+	    id: string;
+	    path: string;
+	    lines: LogLine[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LogSession(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.path = source["path"];
+	        this.lines = this.convertValues(source["lines"], LogLine);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LogSettings {
+	    backlog: number;
+	    poll_ms: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.backlog = source["backlog"];
+	        this.poll_ms = source["poll_ms"];
 	    }
 	}
+	export class OpenRequest {
+	    path: string;
+	    line?: number;
+	    column?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new OpenRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	    }
+	}
+	export class OutlineSymbol {
+	    name: string;
+	    kind: string;
+	    detail: string;
+	    level: number;
+	    range: LSPRange;
+	    children: OutlineSymbol[];
+	
+	    static createFrom(source: any = {}) {
+	        return new OutlineSymbol(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.kind = source["kind"];
+	        this.detail = source["detail"];
+	        this.level = source["level"];
+	        this.range = this.convertValues(source["range"], LSPRange);
+	        this.children = this.convertValues(source["children"], OutlineSymbol);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Problem {
+	    path: string;
+	    line: number;
+	    column: number;
+	    severity: string;
+	    message: string;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Problem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.severity = source["severity"];
+	        this.message = source["message"];
+	        this.source = source["source"];
+	    }
+	}
+	export class ProcessInfo {
+	    id: string;
+	    name: string;
+	    command: string;
+	    dir: string;
+	    running: boolean;
+	    exitCode: number;
+	    // Go type: time
+	    started: any;
+	    duration: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProcessInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.command = source["command"];
+	        this.dir = source["dir"];
+	        this.running = source["running"];
+	        this.exitCode = source["exitCode"];
+	        this.started = this.convertValues(source["started"], null);
+	        this.duration = source["duration"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RunnerConfig {
+	    command: string;
+	    args: string[];
+	    env?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new RunnerConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.env = source["env"];
+	    }
+	}
+	export class SettingDef {
+	    key: string;
+	    type: string;
+	    default?: any;
+	    min?: number;
+	    max?: number;
+	    enum?: string[];
+	    description: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SettingDef(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.type = source["type"];
+	        this.default = source["default"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.enum = source["enum"];
+	        this.description = source["description"];
+	    }
+	}
+	export class Settings {
+	    version: number;
+	    max_recent_files: number;
+	    format_on_save: boolean;
+	    large_file_mb: number;
+	    formatters?: Record<string, FormatterConfig>;
+	    linters?: Record<string, Array<LinterConfig>>;
+	    runners?: Record<string, RunnerConfig>;
+	    editor: EditorSettings;
+	    history: HistorySettings;
+	    explorer: ExplorerSettings;
+	    log: LogSettings;
+	    archive: ArchiveSettings;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.max_recent_files = source["max_recent_files"];
+	        this.format_on_save = source["format_on_save"];
+	        this.large_file_mb = source["large_file_mb"];
+	        this.formatters = this.convertValues(source["formatters"], FormatterConfig, true);
+	        this.linters = this.convertValues(source["linters"], Array<LinterConfig>, true);
+	        this.runners = this.convertValues(source["runners"], RunnerConfig, true);
+	        this.editor = this.convertValues(source["editor"], EditorSettings);
+	        this.history = this.convertValues(source["history"], HistorySettings);
+	        this.explorer = this.convertValues(source["explorer"], ExplorerSettings);
+	        this.log = this.convertValues(source["log"], LogSettings);
+	        this.archive = this.convertValues(source["archive"], ArchiveSettings);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SettingsResult {
+	    settings: Settings;
+	    errors: string[];
+	    path: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SettingsResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.settings = this.convertValues(source["settings"], Settings);
+	        this.errors = source["errors"];
+	        this.path = source["path"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskDefinition {
+	    name: string;
+	    command: string;
+	    args?: string[];
+	    cwd?: string;
+	    env?: Record<string, string>;
+	    pattern?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskDefinition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.cwd = source["cwd"];
+	        this.env = source["env"];
+	        this.pattern = source["pattern"];
+	    }
+	}
+	export class TaskList {
+	    root: string;
+	    file: string;
+	    tasks: TaskDefinition[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskList(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.root = source["root"];
+	        this.file = source["file"];
+	        this.tasks = this.convertValues(source["tasks"], TaskDefinition);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TrashItem {
+	    id: string;
+	    name: string;
+	    originalPath: string;
+	    deletedAt: number;
+	    isDir: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrashItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.originalPath = source["originalPath"];
+	        this.deletedAt = source["deletedAt"];
+	        this.isDir = source["isDir"];
+	    }
+	}
+	export class WorkspaceAISettings {
+	    model?: string;
+	    baseUrl?: string;
+	    apiKeyEnv?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkspaceAISettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.baseUrl = source["baseUrl"];
+	        this.apiKeyEnv = source["apiKeyEnv"];
+	    }
+	}
+	export class WorkspaceSettings {
+	    formatters?: Record<string, FormatterConfig>;
+	    tasks?: TaskDefinition[];
+	    exclude?: string[];
+	    ai?: WorkspaceAISettings;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkspaceSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.formatters = this.convertValues(source["formatters"], FormatterConfig, true);
+	        this.tasks = this.convertValues(source["tasks"], TaskDefinition);
+	        this.exclude = source["exclude"];
+	        this.ai = this.convertValues(source["ai"], WorkspaceAISettings);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorkspaceFolder {
+	    path: string;
+	    name?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkspaceFolder(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	    }
+	}
+	export class Workspace {
+	    file: string;
+	    name: string;
+	    folders: WorkspaceFolder[];
+	    settings: WorkspaceSettings;
+	
+	    static createFrom(source: any = {}) {
+	        return new Workspace(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = source["file"];
+	        this.name = source["name"];
+	        this.folders = this.convertValues(source["folders"], WorkspaceFolder);
+	        this.settings = this.convertValues(source["settings"], WorkspaceSettings);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
 
 }
 
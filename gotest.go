@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// GoTestNode is a package, test or subtest in the results tree
+type GoTestNode struct {
+	Name     string        `json:"name"`
+	Package  string        `json:"package"`
+	Test     string        `json:"test"`   // Full test name incl. subtests, empty for packages
+	Status   string        `json:"status"` // run, pass, fail, skip
+	Elapsed  float64       `json:"elapsed"`
+	Output   []string      `json:"output"`
+	Path     string        `json:"path,omitempty"` // Declaration, or the first failure location
+	Line     int           `json:"line,omitempty"` // 1-based
+	Children []*GoTestNode `json:"children"`
+
+	failPath string // First source location in the test's output
+	failLine int
+}
+
+// CoverageBlock is a statement range of a coverage profile (1-based lines and columns)
+type CoverageBlock struct {
+	StartLine int  `json:"startLine"`
+	StartCol  int  `json:"startCol"`
+	EndLine   int  `json:"endLine"`
+	EndCol    int  `json:"endCol"`
+	Covered   bool `json:"covered"`
+}
+
+// goTestEvent is one line of "go test -json" (test2json)
+type goTestEvent struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// goTestRun collects the events of one "go test -json" run into a tree
+type goTestRun struct {
+	app      *App
+	dir      string
+	mu       sync.Mutex
+	root     *GoTestNode
+	nodes    map[string]*GoTestNode // package + "\x00" + test
+	decls    map[string]goTestDecl  // Test function -> declaration
+	lastEmit time.Time
+}
+
+func newGoTestRun(app *App, dir string) *goTestRun {
+	return &goTestRun{
+		app:   app,
+		dir:   dir,
+		root:  &GoTestNode{Name: dir, Status: "run", Output: []string{}, Children: []*GoTestNode{}},
+		nodes: make(map[string]*GoTestNode),
+		decls: goTestDecls(dir),
+	}
+}
+
+type goTestDecl struct {
+	path string
+	line int
+}
+
+// goTestDecls finds the declarations of the test functions in the _test.go files of dir
+func goTestDecls(dir string) map[string]goTestDecl {
+	decls := make(map[string]goTestDecl)
+	files, _ := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	for _, file := range files {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, fd := range goTestFuncs(f) {
+			decls[fd.Name.Name] = goTestDecl{path: file, line: fset.Position(fd.Pos()).Line}
+		}
+	}
+	return decls
+}
+
+// goTestFuncs returns the test, benchmark, fuzz and example functions of a file
+func goTestFuncs(f *ast.File) []*ast.FuncDecl {
+	var funcs []*ast.FuncDecl
+	for _, d := range f.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil {
+			continue
+		}
+		for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+			if strings.HasPrefix(fd.Name.Name, prefix) && fd.Name.Name != "TestMain" {
+				funcs = append(funcs, fd)
+				break
+			}
+		}
+	}
+	return funcs
+}
+
+func (r *goTestRun) node(pkg string, test string) *GoTestNode {
+	key := pkg + "\x00" + test
+	if n, ok := r.nodes[key]; ok {
+		return n
+	}
+
+	n := &GoTestNode{Package: pkg, Test: test, Status: "run", Output: []string{}, Children: []*GoTestNode{}}
+	parent := r.root
+	if test == "" {
+		n.Name = pkg
+	} else {
+		parent = r.node(pkg, "")
+		if i := strings.LastIndex(test, "/"); i >= 0 {
+			parent = r.node(pkg, test[:i])
+			n.Name = test[i+1:]
+		} else {
+			n.Name = test
+			if d, ok := r.decls[test]; ok {
+				n.Path, n.Line = d.path, d.line
+			}
+		}
+	}
+	parent.Children = append(parent.Children, n)
+	r.nodes[key] = n
+	return n
+}
+
+// event applies a test2json event and returns the output line for the output panel
+func (r *goTestRun) event(ev goTestEvent, re *regexp.Regexp, line *OutputLine) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Compiler output ("build-output") belongs to no test
+	if strings.HasPrefix(ev.Action, "build-") {
+		if ev.Action != "build-output" {
+			return false
+		}
+		line.Text = strings.TrimRight(ev.Output, "\r\n")
+		line.Path, line.Line, line.Column = matchLocation(re, line.Text, r.dir)
+		return true
+	}
+
+	n := r.node(ev.Package, ev.Test)
+	switch ev.Action {
+	case "output":
+		text := strings.TrimRight(ev.Output, "\r\n")
+		n.Output = append(n.Output, text)
+		line.Text = text
+		line.Path, line.Line, line.Column = matchLocation(re, text, r.dir)
+		if line.Path != "" && n.failPath == "" {
+			n.failPath, n.failLine = line.Path, line.Line
+		}
+		return true
+	case "run":
+		n.Status = "run"
+	case "pass", "fail", "skip":
+		n.Status = ev.Action
+		n.Elapsed = ev.Elapsed
+		// A failed test links to where it failed instead of its declaration
+		if ev.Action == "fail" && n.failPath != "" {
+			n.Path, n.Line = n.failPath, n.failLine
+		}
+		r.emitLocked(false)
+	}
+	return false
+}
+
+// emitLocked sends the tree to the frontend, at most every 200ms unless final
+func (r *goTestRun) emitLocked(final bool) {
+	if r.app.ctx == nil || (!final && time.Since(r.lastEmit) < 200*time.Millisecond) {
+		return
+	}
+	r.lastEmit = time.Now()
+	runtime.EventsEmit(r.app.ctx, "gotest-update", map[string]interface{}{
+		"tree":  r.root,
+		"final": final,
+	})
+}
+
+// finish marks tests that never reported a result (build failures, timeouts)
+func (r *goTestRun) finish(exitCode int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var walk func(n *GoTestNode) string
+	walk = func(n *GoTestNode) string {
+		for _, c := range n.Children {
+			if walk(c) == "fail" && n.Status == "run" {
+				n.Status = "fail"
+			}
+		}
+		if n.Status == "run" {
+			n.Status = "fail"
+		}
+		return n.Status
+	}
+	walk(r.root)
+	if exitCode == 0 {
+		r.root.Status = "pass"
+	}
+	r.emitLocked(true)
+}
+
+// goTestAt returns the test function in path that contains line (0-based)
+func goTestAt(path string, line int) (string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return "", err
+	}
+	for _, fd := range goTestFuncs(f) {
+		if fset.Position(fd.Pos()).Line-1 <= line && line <= fset.Position(fd.End()).Line-1 {
+			return fd.Name.Name, nil
+		}
+	}
+	return "", fmt.Errorf("Kein Test an dieser Stelle")
+}
+
+// RunGoTests runs "go test -json" for the package of path. scope is
+// "package", "file" (tests of this file) or "test" (the test at line, 0-based).
+// The results tree arrives as "gotest-update" events, the output in the output panel.
+func (a *App) RunGoTests(path string, scope string, line int, coverage bool) (ProcessInfo, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".go") {
+		return ProcessInfo{}, fmt.Errorf("Keine Go-Datei: %s", filepath.Base(path))
+	}
+	dir := filepath.Dir(path)
+	args := []string{"test", "-json"}
+	name := "go test " + filepath.Base(dir)
+
+	switch scope {
+	case "file":
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return ProcessInfo{}, fmt.Errorf("Fehler beim Lesen der Tests: %w", err)
+		}
+		var tests []string
+		for _, fd := range goTestFuncs(f) {
+			tests = append(tests, fd.Name.Name)
+		}
+		if len(tests) == 0 {
+			return ProcessInfo{}, fmt.Errorf("Keine Tests in %s", filepath.Base(path))
+		}
+		args = append(args, "-run", "^("+strings.Join(tests, "|")+")$")
+		name = "go test " + filepath.Base(path)
+	case "test":
+		test, err := goTestAt(path, line)
+		if err != nil {
+			return ProcessInfo{}, err
+		}
+		args = append(args, "-run", "^"+regexp.QuoteMeta(test)+"$")
+		name = "go test " + test
+	}
+
+	var profile string
+	if coverage {
+		f, err := os.CreateTemp("", "leoedit-cover-*.out")
+		if err != nil {
+			return ProcessInfo{}, err
+		}
+		f.Close()
+		profile = f.Name()
+		args = append(args, "-coverprofile="+profile)
+	}
+	args = append(args, ".")
+
+	run := newGoTestRun(a, dir)
+	re, _ := a.linters.pattern("")
+
+	info, err := a.processes.start(processSpec{
+		Name:    name,
+		Command: "go",
+		Args:    args,
+		Dir:     dir,
+		OnLine: func(line *OutputLine) bool {
+			var ev goTestEvent
+			if line.Stream != "stdout" || json.Unmarshal([]byte(line.Text), &ev) != nil || ev.Action == "" {
+				return true // Build errors and other plain output
+			}
+			return run.event(ev, re, line)
+		},
+		OnExit: func(info ProcessInfo) {
+			run.finish(info.ExitCode)
+			if profile == "" {
+				return
+			}
+			defer os.Remove(profile)
+			blocks, err := parseCoverProfile(profile, dir)
+			if err != nil {
+				return
+			}
+			a.coverageMu.Lock()
+			for file, b := range blocks {
+				a.coverage[file] = b
+			}
+			a.coverageMu.Unlock()
+			if a.ctx != nil {
+				files := make([]string, 0, len(blocks))
+				for file := range blocks {
+					files = append(files, file)
+				}
+				sort.Strings(files)
+				runtime.EventsEmit(a.ctx, "coverage-updated", files)
+			}
+		},
+	})
+	if err != nil {
+		if profile != "" {
+			os.Remove(profile)
+		}
+		return ProcessInfo{}, fmt.Errorf("Fehler beim Starten von go test: %w", err)
+	}
+	return info, nil
+}
+
+// parseCoverProfile reads a -coverprofile file. File names are import paths;
+// they are mapped into dir, the directory of the tested package.
+func parseCoverProfile(profile string, dir string) (map[string][]CoverageBlock, error) {
+	f, err := os.Open(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	result := make(map[string][]CoverageBlock)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		// name.go:line.column,line.column numberOfStatements count
+		text := sc.Text()
+		if strings.HasPrefix(text, "mode:") {
+			continue
+		}
+		colon := strings.LastIndex(text, ":")
+		fields := strings.Fields(text[colon+1:])
+		if colon < 0 || len(fields) != 3 {
+			continue
+		}
+		var b CoverageBlock
+		if _, err := fmt.Sscanf(fields[0], "%d.%d,%d.%d", &b.StartLine, &b.StartCol, &b.EndLine, &b.EndCol); err != nil {
+			continue
+		}
+		count, _ := strconv.Atoi(fields[2])
+		b.Covered = count > 0
+
+		file := filepath.Join(dir, filepath.Base(text[:colon]))
+		result[file] = append(result[file], b)
+	}
+	return result, sc.Err()
+}
+
+// GetCoverage returns the coverage blocks of the last test run for path
+func (a *App) GetCoverage(path string) []CoverageBlock {
+	a.coverageMu.Lock()
+	defer a.coverageMu.Unlock()
+	if blocks, ok := a.coverage[path]; ok {
+		return blocks
+	}
+	return []CoverageBlock{}
+}
+
+// ClearCoverage removes all coverage data
+func (a *App) ClearCoverage() {
+	a.coverageMu.Lock()
+	a.coverage = make(map[string][]CoverageBlock)
+	a.coverageMu.Unlock()
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Bytes looked at to tell binary from text files
+const binarySniffLen = 8000
+
+// Bytes returned by HexRead at most
+const hexMaxRead = 64 * 1024
+
+// HexSearchOptions control HexSearch
+type HexSearchOptions struct {
+	Text bool  `json:"text"` // The pattern is text (UTF-8), not hex bytes
+	From int64 `json:"from"` // Search starts here and wraps around
+}
+
+// looksBinary reports whether data (the start of a file) is binary: it has
+// NUL bytes or many control characters. UTF-16 text with BOM is not binary.
+func looksBinary(data []byte) bool {
+	if bytes.HasPrefix(data, []byte{0xFE, 0xFF}) || bytes.HasPrefix(data, []byte{0xFF, 0xFE}) {
+		return false
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+	control := 0
+	for _, b := range data {
+		// Tab, line ends, form feed and escape appear in text files
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != 0x1b || b == 0x7f {
+			control++
+		}
+	}
+	return control*10 > len(data)
+}
+
+// isBinaryFile checks the start of a local file
+func isBinaryFile(path string) bool {
+	if isRemotePath(path) {
+		return false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	buf := make([]byte, binarySniffLen)
+	n, _ := io.ReadFull(f, buf)
+	return !isEncrypted(buf[:n]) && looksBinary(buf[:n])
+}
+
+// parseHexBytes accepts hex digits with optional spaces, e.g. "DE AD be ef"
+func parseHexBytes(s string) ([]byte, error) {
+	s = strings.Join(strings.Fields(s), "")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("Ungültige Hex-Bytes: %s", s)
+	}
+	return data, nil
+}
+
+// find returns the offset of the first occurrence of pattern at or after
+// from, or -1
+func (lf *largeFile) find(pattern []byte, from int64) (int64, error) {
+	const chunk = 1 << 20
+	r := lf.reader(from)
+	buf := make([]byte, 0, chunk+len(pattern))
+	pos := from // Document offset of buf[0]
+	for {
+		n, err := io.ReadFull(r, buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if i := bytes.Index(buf, pattern); i >= 0 {
+			return pos + int64(i), nil
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return -1, nil
+		}
+		if err != nil {
+			return -1, err
+		}
+		// Keep the tail, a match may span the chunks
+		keep := min(len(pattern)-1, len(buf))
+		pos += int64(len(buf) - keep)
+		buf = append(buf[:0], buf[len(buf)-keep:]...)
+	}
+}
+
+// OpenHexFile opens a file for the hex editor; it shares the piece table
+// and the calls LargeFileSave and CloseLargeFile with large file mode
+func (a *App) OpenHexFile(path string) (LargeFileInfo, error) {
+	if isRemotePath(path) {
+		return LargeFileInfo{}, fmt.Errorf("Der Hex-Editor ist nur für lokale Dateien verfügbar")
+	}
+	return a.OpenLargeFile(path)
+}
+
+// HexRead returns up to length bytes from offset, hex encoded
+func (a *App) HexRead(id string, offset, length int64) (string, error) {
+	var out string
+	err := a.largeFiles.with(id, func(lf *largeFile) error {
+		if offset < 0 || offset > lf.docSize() {
+			return fmt.Errorf("Ungültiger Offset %d", offset)
+		}
+		buf := make([]byte, max(min(length, hexMaxRead, lf.docSize()-offset), 0))
+		n, err := io.ReadFull(lf.reader(offset), buf)
+		if err == io.ErrUnexpectedEOF || err == io.EOF {
+			err = nil
+		}
+		out = hex.EncodeToString(buf[:n])
+		return err
+	})
+	return out, err
+}
+
+// HexWrite writes hex encoded bytes at offset, overwriting or inserting;
+// overwriting past the end appends
+func (a *App) HexWrite(id string, offset int64, data string, insert bool) (LargeFileInfo, error) {
+	raw, err := parseHexBytes(data)
+	if err != nil {
+		return LargeFileInfo{}, err
+	}
+	var info LargeFileInfo
+	err = a.largeFiles.with(id, func(lf *largeFile) error {
+		to := offset
+		if !insert {
+			to = min(offset+int64(len(raw)), lf.docSize())
+		}
+		if err := lf.replaceRange(offset, to, raw); err != nil {
+			return err
+		}
+		info = lf.info(id)
+		return nil
+	})
+	return info, err
+}
+
+// HexDelete removes count bytes from offset
+func (a *App) HexDelete(id string, offset, count int64) (LargeFileInfo, error) {
+	var info LargeFileInfo
+	err := a.largeFiles.with(id, func(lf *largeFile) error {
+		if err := lf.replaceRange(offset, min(offset+count, lf.docSize()), nil); err != nil {
+			return err
+		}
+		info = lf.info(id)
+		return nil
+	})
+	return info, err
+}
+
+// HexSearch finds the next occurrence of a byte pattern from opts.From on,
+// continuing at the start of the file; -1 if there is none
+func (a *App) HexSearch(id, pattern string, opts HexSearchOptions) (int64, error) {
+	needle := []byte(pattern)
+	if !opts.Text {
+		var err error
+		if needle, err = parseHexBytes(pattern); err != nil {
+			return -1, err
+		}
+	}
+	if len(needle) == 0 {
+		return -1, fmt.Errorf("Kein Suchbegriff")
+	}
+	found := int64(-1)
+	err := a.largeFiles.with(id, func(lf *largeFile) (err error) {
+		from := max(min(opts.From, lf.docSize()), 0)
+		if found, err = lf.find(needle, from); err != nil || found >= 0 || from == 0 {
+			return err
+		}
+		found, err = lf.find(needle, 0)
+		return err
+	})
+	return found, err
+}
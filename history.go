@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// HistoryRevision is one saved state of a file in the local history
+type HistoryRevision struct {
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+	Size int64     `json:"size"` // Uncompressed
+}
+
+// historyStore keeps gzip snapshots below <config>/history, one directory
+// per file named after the hash of its path. Snapshots are named
+// "<unix nanos>-<size>.gz". Independent of git, so untracked files are covered.
+type historyStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func newHistoryStore(dir string) *historyStore {
+	return &historyStore{dir: dir}
+}
+
+func (h *historyStore) fileDir(path string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(path)))
+	return filepath.Join(h.dir, hex.EncodeToString(sum[:12]))
+}
+
+// list returns the revisions of path, newest first
+func (h *historyStore) list(path string) ([]HistoryRevision, error) {
+	entries, err := os.ReadDir(h.fileDir(path))
+	if os.IsNotExist(err) {
+		return []HistoryRevision{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	revs := []HistoryRevision{}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".gz")
+		if !ok {
+			continue
+		}
+		stamp, size, _ := strings.Cut(name, "-")
+		nanos, err := strconv.ParseInt(stamp, 10, 64)
+		if err != nil {
+			continue
+		}
+		n, _ := strconv.ParseInt(size, 10, 64)
+		revs = append(revs, HistoryRevision{ID: name, Time: time.Unix(0, nanos), Size: n})
+	}
+	sort.Slice(revs, func(i, j int) bool { return revs[i].ID > revs[j].ID })
+	return revs, nil
+}
+
+func (h *historyStore) read(path, id string) ([]byte, error) {
+	if strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("Ungültiger Stand: %s", id)
+	}
+	f, err := os.Open(filepath.Join(h.fileDir(path), id+".gz"))
+	if err != nil {
+		return nil, fmt.Errorf("Stand nicht gefunden: %w", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// snapshot stores data as the newest revision of path unless it equals the
+// current newest one; it reports whether a revision was written
+func (h *historyStore) snapshot(path string, data []byte, limits HistorySettings) (bool, error) {
+	if !limits.Enabled {
+		return false, nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	revs, err := h.list(path)
+	if err != nil {
+		return false, err
+	}
+	if len(revs) > 0 {
+		if last, err := h.read(path, revs[0].ID); err == nil && bytes.Equal(last, data) {
+			return false, nil
+		}
+	}
+
+	dir := h.fileDir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+	// The original path, only for people looking into the directory
+	os.WriteFile(filepath.Join(dir, "path.txt"), []byte(path+"\n"), 0644)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(data)
+	if err := zw.Close(); err != nil {
+		return false, err
+	}
+	stamp := time.Now().UnixNano()
+	if len(revs) > 0 && revs[0].Time.UnixNano() >= stamp {
+		stamp = revs[0].Time.UnixNano() + 1
+	}
+	name := fmt.Sprintf("%d-%d.gz", stamp, len(data))
+	tmp := filepath.Join(dir, name+".tmp")
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		os.Remove(tmp)
+		return false, err
+	}
+	h.prune(path, limits)
+	return true, nil
+}
+
+// prune deletes revisions beyond the count, age and size limits; the
+// newest revision is always kept
+func (h *historyStore) prune(path string, limits HistorySettings) {
+	revs, err := h.list(path)
+	if err != nil {
+		return
+	}
+	dir := h.fileDir(path)
+	cutoff := time.Now().AddDate(0, 0, -limits.MaxAgeDays)
+	var total int64
+	for i, rev := range revs {
+		file := filepath.Join(dir, rev.ID+".gz")
+		if info, err := os.Stat(file); err == nil {
+			total += info.Size()
+		}
+		if i == 0 {
+			continue
+		}
+		if i >= limits.MaxRevisions || rev.Time.Before(cutoff) || total > int64(limits.MaxSizeMB)<<20 {
+			os.Remove(file)
+		}
+	}
+}
+
+// recordHistory stores the saved bytes of path in the local history
+func (a *App) recordHistory(path string, data []byte) {
+	if a.encrypted(path) {
+		// The history is not encrypted
+		return
+	}
+	added, err := a.history.snapshot(path, data, a.prefs().History)
+	if err != nil {
+		log.Printf("history: %s: %v", path, err)
+		return
+	}
+	if added && a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "history-updated", path)
+	}
+}
+
+// historyText returns a revision as text; an empty id stands for the file on disk
+func (a *App) historyText(path, id string) (string, error) {
+	if id == "" {
+		text, _, _, err := a.readText(path)
+		return text, err
+	}
+	data, err := a.history.read(path, id)
+	if err != nil {
+		return "", err
+	}
+	text, _ := decodeText(data, resolveEditorConfig(path).Charset)
+	return text, nil
+}
+
+// HistoryList returns the saved revisions of a file, newest first
+func (a *App) HistoryList(path string) ([]HistoryRevision, error) {
+	return a.history.list(path)
+}
+
+// HistoryGet returns the content of a revision
+func (a *App) HistoryGet(path, id string) (string, error) {
+	return a.historyText(path, id)
+}
+
+// HistoryDiff returns a unified diff between two revisions; an empty id
+// stands for the current file on disk
+func (a *App) HistoryDiff(path, fromID, toID string) (string, error) {
+	from, err := a.historyText(path, fromID)
+	if err != nil {
+		return "", err
+	}
+	to, err := a.historyText(path, toID)
+	if err != nil {
+		return "", err
+	}
+	label := func(id string) string {
+		if id == "" {
+			return path
+		}
+		return fmt.Sprintf("%s (%s)", path, historyLabel(id))
+	}
+	return unifiedDiff(label(fromID), label(toID), splitLines(from), splitLines(to), 3), nil
+}
+
+func historyLabel(id string) string {
+	stamp, _, _ := strings.Cut(id, "-")
+	nanos, _ := strconv.ParseInt(stamp, 10, 64)
+	return time.Unix(0, nanos).Format("2006-01-02 15:04:05")
+}
+
+// HistoryRestore writes a revision back to the file. The current content is
+// recorded first, so the restore can be undone from the history as well.
+// An open buffer of the file is replaced via "buffer-formatted".
+func (a *App) HistoryRestore(path, id string) (string, error) {
+	data, err := a.history.read(path, id)
+	if err != nil {
+		return "", err
+	}
+	if current, err := a.readData(path); err == nil {
+		a.recordHistory(path, current)
+	}
+	if err := a.writeData(path, data); err != nil {
+		return "", fmt.Errorf("Fehler beim Wiederherstellen: %w", err)
+	}
+	a.recordHistory(path, data)
+
+	content, charset := decodeText(data, resolveEditorConfig(path).Charset)
+	a.charsets.set(path, charset)
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "buffer-formatted", map[string]interface{}{
+			"path":    path,
+			"content": content,
+		})
+	}
+	return content, nil
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Only one Leoedit runs per user: the first instance listens on a Unix
+// domain socket, later launches send their arguments there and exit.
+
+// Skips the lock and starts another window
+const newInstanceFlag = "--new-instance"
+
+// OpenRequest is a file from the command line, optionally with a position
+type OpenRequest struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line,omitempty"`   // 1-based, 0 if not given
+	Column int    `json:"column,omitempty"` // 1-based, 0 if not given
+}
+
+// instanceMessage is what a second launch sends to the running instance
+type instanceMessage struct {
+	Dir  string   `json:"dir"` // Working directory, relative paths are resolved against it
+	Args []string `json:"args"`
+}
+
+// instanceServer receives the arguments of later launches. Files wait in
+// queue until the frontend takes them, so none get lost during startup.
+type instanceServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	queue    []OpenRequest
+}
+
+func instanceSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "leoedit.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("leoedit-%d.sock", os.Getuid()))
+}
+
+// acquireInstance takes the single-instance lock. If another instance runs,
+// args are forwarded to it and ok is false. Without a usable socket the
+// app starts anyway, just without the lock.
+func acquireInstance(args []string) (l net.Listener, ok bool) {
+	for _, arg := range args {
+		if arg == newInstanceFlag {
+			return nil, true
+		}
+	}
+	sock := instanceSocketPath()
+	// Launches at the same time wait here, so only one of them listens
+	unlock, err := lockFile(sock + ".lock")
+	if err != nil {
+		log.Printf("instance: %v", err)
+		return nil, true
+	}
+	defer unlock()
+
+	err = forwardArgs(sock, args)
+	if err == nil {
+		return nil, false
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		// Nobody listens, the socket is left over from a crash
+		os.Remove(sock)
+	}
+	if l, err = net.Listen("unix", sock); err != nil {
+		log.Printf("instance: %v", err)
+		return nil, true
+	}
+	os.Chmod(sock, 0600)
+	return l, true
+}
+
+// forwardArgs sends args to the instance listening on sock
+func forwardArgs(sock string, args []string) error {
+	conn, err := net.DialTimeout("unix", sock, time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	dir, _ := os.Getwd()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return json.NewEncoder(conn).Encode(instanceMessage{Dir: dir, Args: args})
+}
+
+// serveInstance handles forwarded launches until the listener is closed
+func (a *App) serveInstance() {
+	for {
+		conn, err := a.instance.listener.Accept()
+		if err != nil {
+			return
+		}
+		var msg instanceMessage
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		err = json.NewDecoder(conn).Decode(&msg)
+		conn.Close()
+		if err != nil {
+			log.Printf("instance: %v", err)
+			continue
+		}
+		a.openArgs(msg.Dir, msg.Args)
+		runtime.WindowUnminimise(a.ctx)
+		runtime.WindowShow(a.ctx)
+	}
+}
+
+// parseOpenArgs splits command line arguments into files and folders.
+// Positions are given as "file:12", "file:12:5" or "+12 file"; a suffix
+// only counts as position if the full name does not exist.
+func parseOpenArgs(dir string, args []string) (files []OpenRequest, folders []string, errs []string) {
+	line := 0
+	for _, arg := range args {
+		if arg == newInstanceFlag || arg == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimPrefix(arg, "+")); err == nil && strings.HasPrefix(arg, "+") {
+			line = n
+			continue
+		}
+		req := OpenRequest{Path: arg, Line: line}
+		line = 0
+		if !filepath.IsAbs(req.Path) && dir != "" {
+			req.Path = filepath.Join(dir, req.Path)
+		}
+		info, err := os.Stat(req.Path)
+		if os.IsNotExist(err) {
+			if p, l, c, ok := splitPosition(req.Path); ok {
+				req.Path, req.Line, req.Column = p, l, c
+				info, err = os.Stat(req.Path)
+			}
+		}
+		switch {
+		case os.IsNotExist(err):
+			errs = append(errs, fmt.Sprintf("Datei existiert nicht: %s", arg))
+		case err != nil:
+			errs = append(errs, fmt.Sprintf("Fehler beim Prüfen des Pfads: %s (%v)", arg, err))
+		case info.IsDir() || isWorkspaceFile(req.Path):
+			// Folders and workspace files are opened as workspace
+			folders = append(folders, req.Path)
+		default:
+			files = append(files, req)
+		}
+	}
+	return files, folders, errs
+}
+
+// splitPosition cuts ":line" or ":line:column" from the end of a path
+func splitPosition(p string) (path string, line, col int, ok bool) {
+	parts := strings.Split(p, ":")
+	nums := []int{}
+	for len(parts) > 1 && len(nums) < 2 {
+		n, err := strconv.Atoi(parts[len(parts)-1])
+		if err != nil || n < 1 {
+			break
+		}
+		nums = append([]int{n}, nums...)
+		parts = parts[:len(parts)-1]
+	}
+	if len(nums) == 0 {
+		return p, 0, 0, false
+	}
+	line = nums[0]
+	if len(nums) == 2 {
+		col = nums[1]
+	}
+	return strings.Join(parts, ":"), line, col, true
+}
+
+// openArgs opens folders as workspace and queues files for the frontend,
+// which is told with "open-files"
+func (a *App) openArgs(dir string, args []string) {
+	files, folders, errs := parseOpenArgs(dir, args)
+	for _, e := range errs {
+		runtime.EventsEmit(a.ctx, "error", e)
+	}
+	for _, f := range folders {
+		if _, err := a.OpenWorkspace(f); err != nil {
+			runtime.EventsEmit(a.ctx, "error", err.Error())
+		}
+	}
+	if len(files) == 0 {
+		return
+	}
+	a.instance.mu.Lock()
+	a.instance.queue = append(a.instance.queue, files...)
+	a.instance.mu.Unlock()
+	runtime.EventsEmit(a.ctx, "open-files")
+}
+
+// TakeOpenRequests returns the files from the command line and from later
+// launches that are not opened yet
+func (a *App) TakeOpenRequests() []OpenRequest {
+	a.instance.mu.Lock()
+	defer a.instance.mu.Unlock()
+	files := a.instance.queue
+	a.instance.queue = nil
+	if files == nil {
+		return []OpenRequest{}
+	}
+	return files
+}
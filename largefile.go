@@ -0,0 +1,669 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Every largeFileStride-th line start of the original file is kept in the index
+const largeFileStride = 256
+
+// Lines longer than this are cut in windows and searched only up to here
+const largeFileMaxLine = 64 * 1024
+
+// LargeFileInfo describes a file opened in large file mode
+type LargeFileInfo struct {
+	ID       string `json:"id"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Lines    int    `json:"lines"`
+	Modified bool   `json:"modified"`
+}
+
+// LargeFileWindow is a range of lines; Truncated lists the lines (absolute
+// numbers) cut at largeFileMaxLine, which cannot be edited
+type LargeFileWindow struct {
+	Start     int      `json:"start"`
+	Lines     []string `json:"lines"`
+	Truncated []int    `json:"truncated"`
+	Total     int      `json:"total"`
+}
+
+// LargeFileSearchOptions control LargeFileSearch
+type LargeFileSearchOptions struct {
+	Regex         bool `json:"regex"`
+	CaseSensitive bool `json:"caseSensitive"`
+	MaxResults    int  `json:"maxResults"` // 0: 1000
+}
+
+// LargeFileMatch is a search hit; Line and Column are 0-based (bytes)
+type LargeFileMatch struct {
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Length int    `json:"length"`
+	Text   string `json:"text"`
+}
+
+// piece is a span of the original file or of the add buffer
+type piece struct {
+	add    bool
+	start  int64
+	length int64
+	lines  int // Newlines in the span
+}
+
+// largeFile is a piece table over the original file: edits append their
+// text to the add buffer and split the pieces, the file itself is only
+// read. Line starts of the original are indexed sparsely.
+type largeFile struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	size   int64
+	index  []int64 // Offset of line i*largeFileStride
+	lines  int     // Newlines in the original
+	add    []byte
+	pieces []piece
+	dirty  bool
+}
+
+func openLargeFile(path string) (*largeFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	lf := &largeFile{path: path, file: f}
+	if err := lf.indexFile(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return lf, nil
+}
+
+// indexFile counts the lines and records every largeFileStride-th line start
+func (lf *largeFile) indexFile() error {
+	info, err := lf.file.Stat()
+	if err != nil {
+		return err
+	}
+	lf.size = info.Size()
+	lf.index = []int64{0}
+	lf.lines = 0
+	buf := make([]byte, 1<<20)
+	var offset int64
+	for {
+		n, err := lf.file.ReadAt(buf, offset)
+		chunk := buf[:n]
+		for i := bytes.IndexByte(chunk, '\n'); i >= 0; {
+			lf.lines++
+			if lf.lines%largeFileStride == 0 {
+				lf.index = append(lf.index, offset+int64(i)+1)
+			}
+			next := bytes.IndexByte(chunk[i+1:], '\n')
+			if next < 0 {
+				break
+			}
+			i += next + 1
+		}
+		offset += int64(n)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	lf.add = nil
+	lf.pieces = nil
+	if lf.size > 0 {
+		lf.pieces = []piece{{start: 0, length: lf.size, lines: lf.lines}}
+	}
+	lf.dirty = false
+	return nil
+}
+
+// origLineStart returns the offset of line n of the original file
+func (lf *largeFile) origLineStart(n int) (int64, error) {
+	offset := lf.index[n/largeFileStride]
+	return lf.skipLines(offset, n%largeFileStride)
+}
+
+// skipLines returns the offset after count newlines from offset
+func (lf *largeFile) skipLines(offset int64, count int) (int64, error) {
+	if count == 0 {
+		return offset, nil
+	}
+	r := bufio.NewReaderSize(io.NewSectionReader(lf.file, offset, lf.size-offset), 64*1024)
+	for count > 0 {
+		chunk, err := r.ReadSlice('\n')
+		offset += int64(len(chunk))
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return offset, err
+		}
+		count--
+	}
+	return offset, nil
+}
+
+// origLinesBefore counts the newlines of the original file before offset
+func (lf *largeFile) origLinesBefore(offset int64) (int, error) {
+	i := sort.Search(len(lf.index), func(i int) bool { return lf.index[i] > offset }) - 1
+	count := i * largeFileStride
+	n, err := countNewlines(io.NewSectionReader(lf.file, lf.index[i], offset-lf.index[i]))
+	return count + n, err
+}
+
+func countNewlines(r io.Reader) (int, error) {
+	buf := make([]byte, 64*1024)
+	count := 0
+	for {
+		n, err := r.Read(buf)
+		count += bytes.Count(buf[:n], []byte{'\n'})
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+}
+
+// makePiece creates a piece of the original or the add buffer with its line count
+func (lf *largeFile) makePiece(add bool, start, length int64) (piece, error) {
+	p := piece{add: add, start: start, length: length}
+	if add {
+		p.lines = bytes.Count(lf.add[start:start+length], []byte{'\n'})
+		return p, nil
+	}
+	before, err := lf.origLinesBefore(start)
+	if err != nil {
+		return p, err
+	}
+	end, err := lf.origLinesBefore(start + length)
+	p.lines = end - before
+	return p, err
+}
+
+// totalLines counts lines like an editor: a trailing newline starts an empty line
+func (lf *largeFile) totalLines() int {
+	n := 1
+	for _, p := range lf.pieces {
+		n += p.lines
+	}
+	return n
+}
+
+// lineStart returns the document offset of line n
+func (lf *largeFile) lineStart(n int) (int64, error) {
+	if n == 0 {
+		return 0, nil
+	}
+	var offset int64
+	seen := 0
+	for _, p := range lf.pieces {
+		if seen+p.lines < n {
+			seen += p.lines
+			offset += p.length
+			continue
+		}
+		// The (n-seen)-th newline of this piece ends the previous line
+		k := n - seen
+		if p.add {
+			data := lf.add[p.start : p.start+p.length]
+			pos := 0
+			for ; k > 0; k-- {
+				pos += bytes.IndexByte(data[pos:], '\n') + 1
+			}
+			return offset + int64(pos), nil
+		}
+		first, err := lf.origLinesBefore(p.start)
+		if err != nil {
+			return 0, err
+		}
+		start, err := lf.origLineStart(first + k)
+		return offset + start - p.start, err
+	}
+	return 0, fmt.Errorf("Zeile %d existiert nicht", n+1)
+}
+
+func (lf *largeFile) docSize() int64 {
+	var size int64
+	for _, p := range lf.pieces {
+		size += p.length
+	}
+	return size
+}
+
+// reader reads the document from offset
+func (lf *largeFile) reader(offset int64) io.Reader {
+	var readers []io.Reader
+	var pos int64
+	for _, p := range lf.pieces {
+		end := pos + p.length
+		if end > offset {
+			skip := max(offset-pos, 0)
+			if p.add {
+				readers = append(readers, bytes.NewReader(lf.add[p.start+skip:p.start+p.length]))
+			} else {
+				readers = append(readers, io.NewSectionReader(lf.file, p.start+skip, p.length-skip))
+			}
+		}
+		pos = end
+	}
+	return io.MultiReader(readers...)
+}
+
+// readLine reads one line without the line end; longer lines are cut at
+// largeFileMaxLine and the rest is skipped
+func readLine(r *bufio.Reader) (string, bool, error) {
+	var line []byte
+	truncated := false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(line)+len(chunk) > largeFileMaxLine {
+			if !truncated {
+				line = append(line, chunk[:largeFileMaxLine-len(line)]...)
+			}
+			truncated = true
+		} else {
+			line = append(line, chunk...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return string(line), truncated, err
+		}
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte{'\n'}), []byte{'\r'})
+		return string(line), truncated, nil
+	}
+}
+
+func (lf *largeFile) window(start, count int) (LargeFileWindow, error) {
+	total := lf.totalLines()
+	start = max(min(start, total-1), 0)
+	w := LargeFileWindow{Start: start, Lines: []string{}, Truncated: []int{}, Total: total}
+	offset, err := lf.lineStart(start)
+	if err != nil {
+		return w, err
+	}
+	r := bufio.NewReaderSize(lf.reader(offset), 64*1024)
+	for i := 0; i < count && start+i < total; i++ {
+		line, truncated, err := readLine(r)
+		w.Lines = append(w.Lines, line)
+		if truncated {
+			w.Truncated = append(w.Truncated, start+i)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return w, err
+		}
+	}
+	return w, nil
+}
+
+// split makes sure a piece boundary is at offset and returns the index of
+// the piece starting there
+func (lf *largeFile) split(offset int64) (int, error) {
+	var pos int64
+	for i, p := range lf.pieces {
+		if offset == pos {
+			return i, nil
+		}
+		if offset < pos+p.length {
+			left, err := lf.makePiece(p.add, p.start, offset-pos)
+			if err != nil {
+				return 0, err
+			}
+			right := piece{add: p.add, start: p.start + left.length, length: p.length - left.length, lines: p.lines - left.lines}
+			lf.pieces = append(lf.pieces[:i], append([]piece{left, right}, lf.pieces[i+1:]...)...)
+			return i + 1, nil
+		}
+		pos += p.length
+	}
+	return len(lf.pieces), nil
+}
+
+// replaceLines replaces count lines from line start with text. The line end
+// after the last replaced line is kept; with count 0 text is inserted at the
+// start of the line.
+func (lf *largeFile) replaceLines(start, count int, text string) error {
+	total := lf.totalLines()
+	if start < 0 || count < 0 || start+count > total {
+		return fmt.Errorf("Ungültiger Zeilenbereich")
+	}
+	from, err := lf.lineStart(start)
+	if err != nil {
+		return err
+	}
+	to := from
+	if count > 0 {
+		// End of the last replaced line, before its line end
+		next := lf.docSize()
+		if start+count < total {
+			if next, err = lf.lineStart(start + count); err != nil {
+				return err
+			}
+			next--
+			if next > from && lf.byteAt(next-1) == '\r' {
+				next--
+			}
+		}
+		to = next
+	}
+
+	return lf.replaceRange(from, to, []byte(text))
+}
+
+// replaceRange replaces the bytes from..to of the document with data
+func (lf *largeFile) replaceRange(from, to int64, data []byte) error {
+	if from < 0 || to < from || to > lf.docSize() {
+		return fmt.Errorf("Ungültiger Bereich")
+	}
+	i, err := lf.split(from)
+	if err != nil {
+		return err
+	}
+	j, err := lf.split(to)
+	if err != nil {
+		return err
+	}
+	var inserted []piece
+	if len(data) > 0 {
+		p := piece{add: true, start: int64(len(lf.add)), length: int64(len(data))}
+		lf.add = append(lf.add, data...)
+		p.lines = bytes.Count(data, []byte{'\n'})
+		inserted = []piece{p}
+	}
+	lf.pieces = append(lf.pieces[:i], append(inserted, lf.pieces[j:]...)...)
+	lf.dirty = true
+	return nil
+}
+
+func (lf *largeFile) byteAt(offset int64) byte {
+	var b [1]byte
+	lf.reader(offset).Read(b[:])
+	return b[0]
+}
+
+// lineMatcher returns the [start, end) byte ranges of the matches in a line
+type lineMatcher func(line string) [][]int
+
+// newLineMatcher avoids regexp for plain queries; case-insensitive ASCII
+// queries compare against an ASCII-lowered copy, which keeps the offsets
+func newLineMatcher(query string, opts LargeFileSearchOptions) (lineMatcher, error) {
+	if query == "" {
+		return nil, fmt.Errorf("Kein Suchbegriff")
+	}
+	if !opts.Regex && (opts.CaseSensitive || isASCII(query)) {
+		needle := query
+		if !opts.CaseSensitive {
+			needle = asciiLower(query)
+		}
+		return func(line string) [][]int {
+			if !opts.CaseSensitive {
+				line = asciiLower(line)
+			}
+			var locs [][]int
+			for pos := 0; ; {
+				i := strings.Index(line[pos:], needle)
+				if i < 0 {
+					return locs
+				}
+				locs = append(locs, []int{pos + i, pos + i + len(needle)})
+				pos += i + len(needle)
+			}
+		}, nil
+	}
+	expr := query
+	if !opts.Regex {
+		expr = regexp.QuoteMeta(query)
+	}
+	if !opts.CaseSensitive {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("Ungültiger Ausdruck: %w", err)
+	}
+	return func(line string) [][]int { return re.FindAllStringIndex(line, -1) }, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// asciiLower lowers A-Z only, so byte offsets stay valid
+func asciiLower(s string) string {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 'A' && c <= 'Z' {
+			b := []byte(s)
+			for j := i; j < len(b); j++ {
+				if b[j] >= 'A' && b[j] <= 'Z' {
+					b[j] += 'a' - 'A'
+				}
+			}
+			return string(b)
+		}
+	}
+	return s
+}
+
+func (lf *largeFile) search(query string, opts LargeFileSearchOptions) ([]LargeFileMatch, error) {
+	match, err := newLineMatcher(query, opts)
+	if err != nil {
+		return nil, err
+	}
+	limit := opts.MaxResults
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	matches := []LargeFileMatch{}
+	r := bufio.NewReaderSize(lf.reader(0), 256*1024)
+	for line := 0; len(matches) < limit; line++ {
+		text, _, err := readLine(r)
+		for _, loc := range match(text) {
+			preview := text
+			if len(preview) > 200 {
+				from := max(loc[0]-80, 0)
+				preview = preview[from:min(from+200, len(preview))]
+			}
+			matches = append(matches, LargeFileMatch{Line: line, Column: loc[0], Length: loc[1] - loc[0], Text: preview})
+			if len(matches) == limit {
+				break
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return matches, err
+		}
+	}
+	return matches, nil
+}
+
+// save writes the document to a temporary file next to the original and
+// renames it over the original, then reindexes
+func (lf *largeFile) save() error {
+	info, err := lf.file.Stat()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(lf.path), "."+filepath.Base(lf.path)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tmp, lf.reader(0))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), info.Mode().Perm())
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), lf.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	f, err := os.Open(lf.path)
+	if err != nil {
+		return err
+	}
+	lf.file.Close()
+	lf.file = f
+	return lf.indexFile()
+}
+
+func (lf *largeFile) info(id string) LargeFileInfo {
+	return LargeFileInfo{ID: id, Path: lf.path, Size: lf.docSize(), Lines: lf.totalLines(), Modified: lf.dirty}
+}
+
+// largeFiles holds the files open in large file mode
+type largeFiles struct {
+	mu    sync.Mutex
+	next  int
+	files map[string]*largeFile
+}
+
+func newLargeFiles() *largeFiles {
+	return &largeFiles{files: make(map[string]*largeFile)}
+}
+
+// with runs fn on the open file id
+func (l *largeFiles) with(id string, fn func(lf *largeFile) error) error {
+	l.mu.Lock()
+	lf, ok := l.files[id]
+	l.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("Datei ist nicht geöffnet")
+	}
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	return fn(lf)
+}
+
+// isLargeFile reports whether path exceeds the large file threshold
+func (a *App) isLargeFile(path string) bool {
+	if isRemotePath(path) {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && info.Size() > int64(a.prefs().LargeFileMB)<<20
+}
+
+// openMode tells how a file is shown: "hex" for binary files, "large"
+// above the size limit, else "" for the editor
+func (a *App) openMode(path string) string {
+	switch {
+	case isArchivePath(path) || isCompressedFile(path) || isEncryptedName(path):
+		// Read through readData, which is not paged
+		return ""
+	case isBinaryFile(path):
+		return "hex"
+	case a.isLargeFile(path):
+		return "large"
+	}
+	return ""
+}
+
+// OpenMode tells the frontend to open path with OpenHexFile ("hex") or
+// OpenLargeFile ("large") instead of reading it into the editor
+func (a *App) OpenMode(path string) string {
+	return a.openMode(path)
+}
+
+// OpenLargeFile indexes a file for paged viewing and editing
+func (a *App) OpenLargeFile(path string) (LargeFileInfo, error) {
+	lf, err := openLargeFile(path)
+	if err != nil {
+		return LargeFileInfo{}, fmt.Errorf("Fehler beim Öffnen: %w", err)
+	}
+	a.largeFiles.mu.Lock()
+	a.largeFiles.next++
+	id := fmt.Sprintf("large-%d", a.largeFiles.next)
+	a.largeFiles.files[id] = lf
+	a.largeFiles.mu.Unlock()
+	return lf.info(id), nil
+}
+
+// LargeFileLines returns count lines from line start (0-based)
+func (a *App) LargeFileLines(id string, start, count int) (LargeFileWindow, error) {
+	var w LargeFileWindow
+	err := a.largeFiles.with(id, func(lf *largeFile) (err error) {
+		w, err = lf.window(start, count)
+		return err
+	})
+	return w, err
+}
+
+// LargeFileSearch searches the whole document, including unsaved edits
+func (a *App) LargeFileSearch(id, query string, opts LargeFileSearchOptions) ([]LargeFileMatch, error) {
+	var matches []LargeFileMatch
+	err := a.largeFiles.with(id, func(lf *largeFile) (err error) {
+		matches, err = lf.search(query, opts)
+		return err
+	})
+	return matches, err
+}
+
+// LargeFileEdit replaces count lines from line start with text, which may
+// span several lines
+func (a *App) LargeFileEdit(id string, start, count int, text string) (LargeFileInfo, error) {
+	var info LargeFileInfo
+	err := a.largeFiles.with(id, func(lf *largeFile) error {
+		if err := lf.replaceLines(start, count, text); err != nil {
+			return err
+		}
+		info = lf.info(id)
+		return nil
+	})
+	return info, err
+}
+
+// LargeFileSave writes the edits to disk
+func (a *App) LargeFileSave(id string) (LargeFileInfo, error) {
+	var info LargeFileInfo
+	err := a.largeFiles.with(id, func(lf *largeFile) error {
+		if err := lf.save(); err != nil {
+			return fmt.Errorf("Fehler beim Speichern: %w", err)
+		}
+		info = lf.info(id)
+		return nil
+	})
+	return info, err
+}
+
+// CloseLargeFile releases the file; unsaved edits are dropped
+func (a *App) CloseLargeFile(id string) {
+	a.largeFiles.mu.Lock()
+	lf, ok := a.largeFiles.files[id]
+	delete(a.largeFiles.files, id)
+	a.largeFiles.mu.Unlock()
+	if ok {
+		lf.mu.Lock()
+		lf.file.Close()
+		lf.mu.Unlock()
+	}
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// LinterConfig describes an external linter. "${file}" and "${dir}" in Args
+// are replaced with the saved file and its directory. Pattern is a problem
+// matcher with the named groups file, line, col, severity and message;
+// Severity is used for matches without a severity group.
+type LinterConfig struct {
+	Name     string   `json:"name"`
+	Command  string   `json:"command"`
+	Args     []string `json:"args"`
+	Pattern  string   `json:"pattern,omitempty"`
+	Severity string   `json:"severity,omitempty"`
+}
+
+// Problem is one linter finding (1-based line/column, 0 = unknown)
+type Problem struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"` // error, warning, info
+	Message  string `json:"message"`
+	Source   string `json:"source"`
+}
+
+// defaultProblemPattern matches "file:line:col: severity: message" and the
+// shorter variants without column or severity
+const defaultProblemPattern = `^(?P<file>[^:\s][^:]*):(?P<line>\d+):(?:(?P<col>\d+):)?\s*(?:(?P<severity>error|warning|warn|info|note|style)\s*:\s*)?(?P<message>.+)$`
+
+// Built-in linters per file extension; entries in Settings.Linters replace them
+var defaultLinters = map[string][]LinterConfig{
+	".go": {
+		{Name: "go vet", Command: "go", Args: []string{"vet", "."}, Severity: "warning"},
+		{Name: "staticcheck", Command: "staticcheck", Args: []string{"."}, Severity: "warning"},
+	},
+	".js": {
+		{Name: "eslint", Command: "eslint", Args: []string{"--format", "unix", "${file}"}},
+	},
+	".sh": {
+		{Name: "shellcheck", Command: "shellcheck", Args: []string{"--format", "gcc", "${file}"}},
+	},
+}
+
+const linterTimeout = 60 * time.Second
+
+type lintRun struct {
+	id     int
+	cancel context.CancelFunc
+}
+
+// linterRunner runs the linters for saved files and keeps the latest problems
+type linterRunner struct {
+	app *App
+	mu  sync.Mutex
+	// Running lints per saved file, so a new save cancels the old run
+	running map[string]lintRun
+	nextID  int
+	// Problems per linter name and file
+	problems map[string]map[string][]Problem
+	patterns map[string]*regexp.Regexp
+}
+
+func newLinterRunner(app *App) *linterRunner {
+	return &linterRunner{
+		app:      app,
+		running:  make(map[string]lintRun),
+		problems: make(map[string]map[string][]Problem),
+		patterns: make(map[string]*regexp.Regexp),
+	}
+}
+
+func (a *App) lintersFor(path string) []LinterConfig {
+	ext := strings.ToLower(filepath.Ext(path))
+	if l, ok := a.prefs().Linters[ext]; ok {
+		return l
+	}
+	return defaultLinters[ext]
+}
+
+func (r *linterRunner) pattern(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		expr = defaultProblemPattern
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if re, ok := r.patterns[expr]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	r.patterns[expr] = re
+	return re, nil
+}
+
+// lint runs all linters configured for path in the background
+func (r *linterRunner) lint(path string) {
+	linters := r.app.lintersFor(path)
+	if len(linters) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), linterTimeout)
+	r.mu.Lock()
+	if prev, ok := r.running[path]; ok {
+		prev.cancel()
+	}
+	r.nextID++
+	id := r.nextID
+	r.running[path] = lintRun{id: id, cancel: cancel}
+	r.mu.Unlock()
+
+	go func() {
+		defer func() {
+			r.mu.Lock()
+			// Only remove our own entry, a newer save may have replaced it
+			if run, ok := r.running[path]; ok && run.id == id {
+				delete(r.running, path)
+			}
+			r.mu.Unlock()
+			cancel()
+		}()
+
+		var wg sync.WaitGroup
+		for _, l := range linters {
+			if _, err := exec.LookPath(l.Command); err != nil {
+				continue
+			}
+			wg.Add(1)
+			go func(l LinterConfig) {
+				defer wg.Done()
+				problems, err := r.runLinter(ctx, l, path)
+				if ctx.Err() != nil || (err != nil && r.app.ctx == nil) {
+					return
+				}
+				if err != nil && r.app.ctx != nil {
+					runtime.EventsEmit(r.app.ctx, "error", fmt.Sprintf("%s: %v", l.source(), err))
+					return
+				}
+				r.store(l.source(), path, lintsDirectory(l), problems)
+			}(l)
+		}
+		wg.Wait()
+	}()
+}
+
+func (r *linterRunner) runLinter(ctx context.Context, l LinterConfig, path string) ([]Problem, error) {
+	re, err := r.pattern(l.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("ungültiges Muster: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	args := make([]string, len(l.Args))
+	for i, arg := range l.Args {
+		arg = strings.ReplaceAll(arg, "${file}", path)
+		args[i] = strings.ReplaceAll(arg, "${dir}", dir)
+	}
+
+	cmd := exec.CommandContext(ctx, l.Command, args...)
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	// Linters exit non-zero when they find something, so the exit code is ignored
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, err
+		}
+	}
+
+	return parseProblems(out.String(), re, path, l.source(), l.Severity), nil
+}
+
+// source names the linter in problems and results; unnamed linters go by
+// their command
+func (l LinterConfig) source() string {
+	if l.Name == "" {
+		return l.Command
+	}
+	return l.Name
+}
+
+// parseProblems applies a problem matcher to the linter output for path.
+// Relative file names are resolved against its directory; matchers without
+// a file group report path itself.
+func parseProblems(output string, re *regexp.Regexp, path string, source string, severity string) []Problem {
+	problems := []Problem{}
+	sc := bufio.NewScanner(strings.NewReader(output))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		p, ok := matchProblem(re, sc.Text(), filepath.Dir(path))
+		if !ok {
+			continue
+		}
+		if p.Path == "" {
+			p.Path = path
+		}
+		if p.Severity == "" {
+			p.Severity = severity
+		}
+		p.Severity = normalizeSeverity(p.Severity)
+		p.Source = source
+		problems = append(problems, p)
+	}
+	return problems
+}
+
+// matchProblem applies a problem matcher to a single output line. The
+// severity is left empty when the matcher has none.
+func matchProblem(re *regexp.Regexp, line string, dir string) (Problem, bool) {
+	m := re.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return Problem{}, false
+	}
+	group := func(name string) string {
+		if i := re.SubexpIndex(name); i >= 0 && i < len(m) {
+			return m[i]
+		}
+		return ""
+	}
+
+	p := Problem{
+		Path:     group("file"),
+		Severity: group("severity"),
+		Message:  strings.TrimSpace(group("message")),
+	}
+	p.Line, _ = strconv.Atoi(group("line"))
+	p.Column, _ = strconv.Atoi(group("col"))
+	if p.Path != "" && !filepath.IsAbs(p.Path) {
+		p.Path = filepath.Join(dir, p.Path)
+	}
+	return p, true
+}
+
+func normalizeSeverity(s string) string {
+	switch strings.ToLower(s) {
+	case "", "error", "fatal":
+		return "error"
+	case "warning", "warn", "style":
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// lintsDirectory reports whether a linter checks the whole directory
+// (package) instead of only the saved file
+func lintsDirectory(l LinterConfig) bool {
+	for _, arg := range l.Args {
+		if strings.Contains(arg, "${file}") {
+			return false
+		}
+	}
+	return true
+}
+
+// store replaces the problems of one linter for the linted file (or its whole
+// directory) and for the files it reported on
+func (r *linterRunner) store(linter string, path string, wholeDir bool, problems []Problem) {
+	byFile := map[string][]Problem{path: {}}
+
+	r.mu.Lock()
+	if r.problems[linter] == nil {
+		r.problems[linter] = make(map[string][]Problem)
+	}
+	if wholeDir {
+		dir := filepath.Dir(path)
+		for file := range r.problems[linter] {
+			if filepath.Dir(file) == dir {
+				byFile[file] = []Problem{}
+			}
+		}
+	}
+	for _, p := range problems {
+		byFile[p.Path] = append(byFile[p.Path], p)
+	}
+	for file, list := range byFile {
+		if len(list) == 0 {
+			delete(r.problems[linter], file)
+		} else {
+			r.problems[linter][file] = list
+		}
+	}
+	r.mu.Unlock()
+
+	if r.app.ctx == nil {
+		return
+	}
+	for file, list := range byFile {
+		runtime.EventsEmit(r.app.ctx, "lint-results", map[string]interface{}{
+			"path":     file,
+			"linter":   linter,
+			"problems": list,
+		})
+	}
+}
+
+// all returns every known problem sorted by file and position
+func (r *linterRunner) all() []Problem {
+	result := []Problem{}
+	r.mu.Lock()
+	for _, files := range r.problems {
+		for _, list := range files {
+			result = append(result, list...)
+		}
+	}
+	r.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return result
+}
+
+// RunLinters lints a file on demand, results arrive as "lint-results" events
+func (a *App) RunLinters(path string) {
+	a.linters.lint(path)
+}
+
+// GetProblems returns the problems list of all linters
+func (a *App) GetProblems() []Problem {
+	return a.linters.all()
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ListDirOptions filter a directory listing
+type ListDirOptions struct {
+	ShowHidden  bool `json:"showHidden"`  // Include names starting with "."
+	HideIgnored bool `json:"hideIgnored"` // Drop entries matched by .gitignore
+}
+
+// DirEntry is one entry of a directory listing. Symlinks report the type
+// and size of their target.
+type DirEntry struct {
+	Name      string `json:"name"`
+	IsDir     bool   `json:"isDir"`
+	Size      int64  `json:"size"`
+	ModTime   int64  `json:"modTime"` // Unix seconds
+	Mode      string `json:"mode"`    // As in ls, e.g. "-rwxr-xr-x"
+	Perm      uint32 `json:"perm"`
+	IsSymlink bool   `json:"isSymlink"`
+	Target    string `json:"target,omitempty"` // Link target as written
+	Broken    bool   `json:"broken,omitempty"` // The link target does not exist
+	Hidden    bool   `json:"hidden"`
+	Archive   bool   `json:"archive,omitempty"` // Can be browsed as folder "<path>!"
+	Mime      string `json:"mime"`
+}
+
+// DirPage is sent as "listdir-page" while a listing started with
+// ListDirStream is read
+type DirPage struct {
+	ID      string     `json:"id"`
+	Entries []DirEntry `json:"entries"`
+	Done    bool       `json:"done"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// dirListings tracks the running streamed listings so they can be cancelled
+type dirListings struct {
+	mu      sync.Mutex
+	next    int
+	cancels map[string]context.CancelFunc
+}
+
+func newDirListings() *dirListings {
+	return &dirListings{cancels: make(map[string]context.CancelFunc)}
+}
+
+func (l *dirListings) start() (string, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.next++
+	id := fmt.Sprintf("list-%d", l.next)
+	l.cancels[id] = cancel
+	return id, ctx
+}
+
+func (l *dirListings) finish(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if cancel, ok := l.cancels[id]; ok {
+		cancel()
+		delete(l.cancels, id)
+	}
+}
+
+// naturalLess compares case-insensitively with digit runs as numbers,
+// so "file2" sorts before "file10"
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		ra, sa := utf8.DecodeRuneInString(a)
+		rb, sb := utf8.DecodeRuneInString(b)
+		if isDigit(ra) && isDigit(rb) {
+			na, nb := digitRun(a), digitRun(b)
+			ta, tb := strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if len(ta) != len(tb) {
+				return len(ta) < len(tb)
+			}
+			if ta != tb {
+				return ta < tb
+			}
+			a, b = a[len(na):], b[len(nb):]
+			continue
+		}
+		if la, lb := unicode.ToLower(ra), unicode.ToLower(rb); la != lb {
+			return la < lb
+		}
+		a, b = a[sa:], b[sb:]
+	}
+	return len(a) < len(b)
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func digitRun(s string) string {
+	i := 0
+	for i < len(s) && isDigit(rune(s[i])) {
+		i++
+	}
+	return s[:i]
+}
+
+// sortDirEntries puts folders first, then sorts naturally
+func sortDirEntries(entries []DirEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].IsDir != entries[j].IsDir {
+			return entries[i].IsDir
+		}
+		if naturalLess(entries[i].Name, entries[j].Name) {
+			return true
+		}
+		if naturalLess(entries[j].Name, entries[i].Name) {
+			return false
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+// ignoreRule is one line of a .gitignore file
+type ignoreRule struct {
+	base     string // Folder of the .gitignore, relative to the repository
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool // Contains a slash: relative to base, not to any level
+}
+
+// gitIgnore holds the rules that apply to one folder of a repository
+type gitIgnore struct {
+	root  string
+	rules []ignoreRule
+}
+
+// loadGitIgnore collects the .gitignore files from the repository root down
+// to dir and .git/info/exclude; nil outside of a repository
+func loadGitIgnore(dir string) *gitIgnore {
+	root := dir
+	for {
+		if _, err := os.Stat(filepath.Join(root, ".git")); err == nil {
+			break
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return nil
+		}
+		root = parent
+	}
+	g := &gitIgnore{root: root}
+	g.addFile(filepath.Join(root, ".git", "info", "exclude"), "")
+	rel, _ := filepath.Rel(root, dir)
+	base := ""
+	g.addFile(filepath.Join(root, ".gitignore"), "")
+	if rel != "." {
+		for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+			base = path.Join(base, part)
+			g.addFile(filepath.Join(root, filepath.FromSlash(base), ".gitignore"), base)
+		}
+	}
+	return g
+}
+
+func (g *gitIgnore) addFile(file, base string) {
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			r.negate, line = true, line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly, line = true, strings.TrimSuffix(line, "/")
+		}
+		r.anchored = strings.Contains(line, "/")
+		r.pattern = strings.TrimPrefix(line, "/")
+		if r.pattern != "" {
+			g.rules = append(g.rules, r)
+		}
+	}
+}
+
+// ignored reports whether the entry at path is ignored; the last matching rule wins
+func (g *gitIgnore) ignored(p string, isDir bool) bool {
+	rel, err := filepath.Rel(g.root, p)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".git" {
+		return true
+	}
+	ignored := false
+	for _, r := range g.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		sub := rel
+		if r.base != "" {
+			if !strings.HasPrefix(rel, r.base+"/") {
+				continue
+			}
+			sub = rel[len(r.base)+1:]
+		}
+		pattern := r.pattern
+		if !r.anchored {
+			pattern = "**/" + pattern
+		}
+		if globMatch(pattern, sub) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// mimeByName guesses the MIME type from the extension only
+func mimeByName(name string) string {
+	t, _, _ := strings.Cut(mime.TypeByExtension(path.Ext(name)), ";")
+	if t == "" {
+		return "application/octet-stream"
+	}
+	return t
+}
+
+// detectMime guesses the MIME type from the extension, or from the first
+// bytes when the extension is unknown
+func detectMime(p string, info os.FileInfo) string {
+	switch {
+	case info.IsDir():
+		return "inode/directory"
+	case !info.Mode().IsRegular():
+		return "inode/x-special"
+	case info.Size() == 0:
+		return "inode/x-empty"
+	}
+	if t := mime.TypeByExtension(filepath.Ext(p)); t != "" {
+		t, _, _ = strings.Cut(t, ";")
+		return t
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	defer f.Close()
+	buf := make([]byte, 512)
+	n, _ := io.ReadFull(f, buf)
+	t, _, _ := strings.Cut(http.DetectContentType(buf[:n]), ";")
+	return t
+}
+
+// dirEntry describes entry of dir; false for entries that are filtered out
+// or vanished while listing
+func (a *App) dirEntry(dir string, entry os.DirEntry, opts ListDirOptions, ign *gitIgnore) (DirEntry, bool) {
+	name := entry.Name()
+	full := filepath.Join(dir, name)
+	hidden := strings.HasPrefix(name, ".")
+	if hidden && !opts.ShowHidden || a.isExcluded(full) {
+		return DirEntry{}, false
+	}
+	info, err := entry.Info()
+	if err != nil {
+		return DirEntry{}, false
+	}
+	e := DirEntry{
+		Name:    name,
+		Size:    info.Size(),
+		ModTime: info.ModTime().Unix(),
+		Mode:    info.Mode().String(),
+		Perm:    uint32(info.Mode().Perm()),
+		Hidden:  hidden,
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		e.IsSymlink = true
+		e.Target, _ = os.Readlink(full)
+		if target, err := os.Stat(full); err == nil {
+			info = target
+			e.Size = target.Size()
+		} else {
+			e.Broken = true
+			e.Mime = "inode/symlink"
+		}
+	}
+	e.IsDir = info.IsDir()
+	e.Archive = info.Mode().IsRegular() && isArchiveName(name)
+	if ign != nil && ign.ignored(full, e.IsDir) {
+		return DirEntry{}, false
+	}
+	if e.Mime == "" {
+		e.Mime = detectMime(full, info)
+	}
+	return e, true
+}
+
+// ListDir lists a folder, folders first and sorted naturally
+func (a *App) ListDir(path string, opts ListDirOptions) ([]DirEntry, error) {
+	if isArchivePath(path) {
+		return listArchiveDir(path, opts)
+	}
+	if isRemotePath(path) {
+		return a.listRemoteDir(path, opts)
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var ign *gitIgnore
+	if opts.HideIgnored {
+		ign = loadGitIgnore(path)
+	}
+	result := make([]DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		if e, ok := a.dirEntry(path, entry, opts, ign); ok {
+			result = append(result, e)
+		}
+	}
+	sortDirEntries(result)
+	return result, nil
+}
+
+// ListDirStream reads a folder in pages of pageSize entries, each sent as a
+// sorted "listdir-page" event; the last page has Done set. The order across
+// pages is up to the receiver. Returns the id for CancelListDir.
+func (a *App) ListDirStream(path string, opts ListDirOptions, pageSize int) (string, error) {
+	if pageSize <= 0 {
+		pageSize = 500
+	}
+	id, ctx := a.listings.start()
+	emit := func(page DirPage) {
+		if ctx.Err() == nil && a.ctx != nil {
+			runtime.EventsEmit(a.ctx, "listdir-page", page)
+		}
+	}
+
+	go func() {
+		defer a.listings.finish(id)
+		if isRemotePath(path) || isArchivePath(path) {
+			entries, err := a.ListDir(path, opts)
+			page := DirPage{ID: id, Entries: entries, Done: true}
+			if err != nil {
+				page.Error = err.Error()
+			}
+			emit(page)
+			return
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			emit(DirPage{ID: id, Entries: []DirEntry{}, Done: true, Error: err.Error()})
+			return
+		}
+		defer f.Close()
+		var ign *gitIgnore
+		if opts.HideIgnored {
+			ign = loadGitIgnore(path)
+		}
+		for ctx.Err() == nil {
+			batch, err := f.ReadDir(pageSize)
+			page := DirPage{ID: id, Entries: make([]DirEntry, 0, len(batch))}
+			for _, entry := range batch {
+				if e, ok := a.dirEntry(path, entry, opts, ign); ok {
+					page.Entries = append(page.Entries, e)
+				}
+			}
+			sortDirEntries(page.Entries)
+			if err != nil {
+				page.Done = true
+				if err != io.EOF {
+					page.Error = err.Error()
+				}
+			}
+			emit(page)
+			if page.Done {
+				return
+			}
+		}
+	}()
+	return id, nil
+}
+
+// CancelListDir stops a streamed listing; no further pages are sent
+func (a *App) CancelListDir(id string) {
+	a.listings.finish(id)
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Timestamps and levels are only looked for this far into a line
+const logScanPrefix = 160
+
+// Lines per "log-lines" event
+const logBatchSize = 500
+
+// Assumed average line length when the backlog is read from the end
+const logAvgLine = 256
+
+var logLevelPattern = regexp.MustCompile(`(?i)\b(fatal|panic|crit(?:ical)?|err(?:or)?|warn(?:ing)?|info|notice|debug|trace)\b`)
+
+// ISO 8601, Apache (10/Oct/2026:13:55:36 +0200), syslog (Oct 18 13:55:36), time only
+var logTimePattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?` +
+	`|\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2}(?: [+-]\d{4})?` +
+	`|\b[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}\b` +
+	`|\b\d{2}:\d{2}:\d{2}(?:[.,]\d+)?\b`)
+
+// LogFilter selects the lines sent to the log view
+type LogFilter struct {
+	Pattern       string   `json:"pattern"` // Regular expression; empty matches all lines
+	CaseSensitive bool     `json:"caseSensitive"`
+	Levels        []string `json:"levels"` // Empty: all lines, else only lines with these levels
+}
+
+// LogLine is one line of a log file
+type LogLine struct {
+	Number int    `json:"number"` // 1-based, restarts after rotation
+	Text   string `json:"text"`
+	Level  string `json:"level,omitempty"` // FATAL, ERROR, WARN, INFO, DEBUG or TRACE
+	Time   string `json:"time,omitempty"`  // Timestamp as written in the line
+}
+
+// LogSession is returned by OpenLog; Lines are the last matching lines
+type LogSession struct {
+	ID    string    `json:"id"`
+	Path  string    `json:"path"`
+	Lines []LogLine `json:"lines"`
+}
+
+// LogBatch is sent as "log-lines" with lines appended to the file. Reset is
+// "rotated" or "truncated" when the lines start a new file.
+type LogBatch struct {
+	ID    string    `json:"id"`
+	Lines []LogLine `json:"lines"`
+	Reset string    `json:"reset,omitempty"`
+	Error string    `json:"error,omitempty"`
+}
+
+// logLevel maps the spellings found in logs to one of six levels
+func logLevel(word string) string {
+	switch w := strings.ToLower(word); {
+	case w == "fatal" || w == "panic" || strings.HasPrefix(w, "crit"):
+		return "FATAL"
+	case strings.HasPrefix(w, "err"):
+		return "ERROR"
+	case strings.HasPrefix(w, "warn"):
+		return "WARN"
+	case w == "info" || w == "notice":
+		return "INFO"
+	}
+	return strings.ToUpper(word)
+}
+
+// classify finds timestamp and level of a line. Lines with neither, like
+// stack traces, keep the level of the line before.
+func classify(text, prev string) (level, ts string) {
+	prefix := text
+	if len(prefix) > logScanPrefix {
+		prefix = prefix[:logScanPrefix]
+	}
+	ts = logTimePattern.FindString(prefix)
+	if m := logLevelPattern.FindString(prefix); m != "" {
+		return logLevel(m), ts
+	}
+	if ts == "" && strings.TrimSpace(text) != "" {
+		return prev, ts
+	}
+	return "", ts
+}
+
+// compile turns the filter into a predicate
+func (f LogFilter) compile() (func(*LogLine) bool, error) {
+	var re *regexp.Regexp
+	if f.Pattern != "" {
+		expr := f.Pattern
+		if !f.CaseSensitive {
+			expr = "(?i)" + expr
+		}
+		var err error
+		if re, err = regexp.Compile(expr); err != nil {
+			return nil, fmt.Errorf("Ungültiger Filter: %w", err)
+		}
+	}
+	levels := make(map[string]bool, len(f.Levels))
+	for _, l := range f.Levels {
+		levels[strings.ToUpper(l)] = true
+	}
+	return func(l *LogLine) bool {
+		if len(levels) > 0 && !levels[l.Level] {
+			return false
+		}
+		return re == nil || re.MatchString(l.Text)
+	}, nil
+}
+
+// logTail follows one log file
+type logTail struct {
+	id       string
+	path     string
+	match    func(*LogLine) bool
+	filtered bool
+	cancel   context.CancelFunc
+	paused   atomic.Bool
+	wake     chan struct{}
+
+	file    *os.File
+	offset  int64 // Read up to here
+	line    int   // Lines read from the current file
+	level   string
+	partial []byte // Incomplete last line
+}
+
+func (t *logTail) open() error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	if t.file != nil {
+		t.file.Close()
+	}
+	t.file, t.offset, t.line, t.level, t.partial = f, 0, 0, "", nil
+	return nil
+}
+
+// read passes the matching lines from the offset to the end of the file to fn
+func (t *logTail) read(fn func(LogLine)) error {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := t.file.ReadAt(buf, t.offset)
+		t.offset += int64(n)
+		data := buf[:n]
+		for {
+			i := bytes.IndexByte(data, '\n')
+			if i < 0 {
+				break
+			}
+			t.emitLine(append(t.partial, data[:i]...), fn)
+			t.partial = t.partial[:0]
+			data = data[i+1:]
+		}
+		// Overlong lines are cut, the rest is skipped up to the newline
+		if room := largeFileMaxLine - len(t.partial); room > 0 {
+			t.partial = append(t.partial, data[:min(len(data), room)]...)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (t *logTail) emitLine(raw []byte, fn func(LogLine)) {
+	t.line++
+	text := strings.ToValidUTF8(string(bytes.TrimSuffix(raw, []byte("\r"))), "�")
+	if len(text) > largeFileMaxLine {
+		text = text[:largeFileMaxLine]
+	}
+	l := LogLine{Number: t.line, Text: text}
+	l.Level, l.Time = classify(text, t.level)
+	t.level = l.Level
+	if t.match(&l) {
+		fn(l)
+	}
+}
+
+// backlog returns the last n matching lines. Without a filter only the end
+// of the file is read; the lines before are just counted.
+func (t *logTail) backlog(n int) ([]LogLine, error) {
+	if !t.filtered {
+		info, err := t.file.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if start := info.Size() - int64(n)*logAvgLine; start > 0 {
+			lines, err := countNewlines(io.NewSectionReader(t.file, 0, start))
+			if err != nil {
+				return nil, err
+			}
+			// The line cut at start is skipped
+			t.line = lines
+			if err := t.skipPartial(start); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Ring buffer, next is the oldest line once it is full
+	ring, next := make([]LogLine, 0, n), 0
+	err := t.read(func(l LogLine) {
+		if len(ring) < n {
+			ring = append(ring, l)
+			return
+		}
+		ring[next] = l
+		next = (next + 1) % n
+	})
+	return append(ring[next:], ring[:next]...), err
+}
+
+// skipPartial moves the offset from start to the beginning of the next line
+func (t *logTail) skipPartial(start int64) error {
+	t.offset, t.partial = start, nil
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := t.file.ReadAt(buf, t.offset)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			t.offset += int64(i + 1)
+			t.line++
+			return nil
+		}
+		t.offset += int64(n)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// poll reads what was appended since the last call. A replaced file
+// (rotation) is read to the end before the new one is opened; a file that
+// shrank (truncation) is read again from the start.
+func (t *logTail) poll() (reset string, lines []LogLine, err error) {
+	collect := func(l LogLine) { lines = append(lines, l) }
+	info, statErr := os.Stat(t.path)
+	current, err := t.file.Stat()
+	if err != nil {
+		return "", nil, err
+	}
+	switch {
+	case statErr != nil:
+		// Rotated, the new file is not there yet
+		err = t.read(collect)
+		return "", lines, err
+	case !os.SameFile(info, current):
+		if err := t.read(collect); err != nil {
+			return "", lines, err
+		}
+		// The last lines of the old file go out first, the switch
+		// follows with the next poll
+		if len(lines) > 0 {
+			return "", lines, nil
+		}
+		if err := t.open(); err != nil {
+			return "", nil, err
+		}
+		reset = "rotated"
+	case info.Size() < t.offset:
+		t.offset, t.line, t.level, t.partial = 0, 0, "", nil
+		reset = "truncated"
+	}
+	err = t.read(collect)
+	return reset, lines, err
+}
+
+// logTails holds the followed log files
+type logTails struct {
+	mu    sync.Mutex
+	next  int
+	tails map[string]*logTail
+}
+
+func newLogTails() *logTails {
+	return &logTails{tails: make(map[string]*logTail)}
+}
+
+func (l *logTails) closeAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for id, t := range l.tails {
+		t.cancel()
+		delete(l.tails, id)
+	}
+}
+
+// follow polls the file until the tail is closed
+func (a *App) follow(ctx context.Context, t *logTail) {
+	defer t.file.Close()
+	emit := func(b LogBatch) {
+		if ctx.Err() == nil && a.ctx != nil {
+			runtime.EventsEmit(a.ctx, "log-lines", b)
+		}
+	}
+	ticker := time.NewTicker(time.Duration(a.prefs().Log.PollMS) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-t.wake:
+		}
+		if t.paused.Load() {
+			continue
+		}
+		reset, lines, err := t.poll()
+		if reset != "" {
+			emit(LogBatch{ID: t.id, Lines: []LogLine{}, Reset: reset})
+		}
+		for len(lines) > 0 {
+			n := min(len(lines), logBatchSize)
+			emit(LogBatch{ID: t.id, Lines: lines[:n]})
+			lines = lines[n:]
+		}
+		if err != nil {
+			emit(LogBatch{ID: t.id, Lines: []LogLine{}, Error: err.Error()})
+		}
+	}
+}
+
+// OpenLog shows the last lines of a log file that pass the filter and
+// follows the file; new lines arrive as "log-lines" events
+func (a *App) OpenLog(path string, filter LogFilter) (LogSession, error) {
+	if isRemotePath(path) {
+		return LogSession{}, fmt.Errorf("Die Log-Ansicht ist nur für lokale Dateien verfügbar")
+	}
+	match, err := filter.compile()
+	if err != nil {
+		return LogSession{}, err
+	}
+	t := &logTail{
+		path:     path,
+		match:    match,
+		filtered: filter.Pattern != "" || len(filter.Levels) > 0,
+		wake:     make(chan struct{}, 1),
+	}
+	if err := t.open(); err != nil {
+		return LogSession{}, fmt.Errorf("Fehler beim Öffnen: %w", err)
+	}
+	lines, err := t.backlog(a.prefs().Log.Backlog)
+	if err != nil {
+		t.file.Close()
+		return LogSession{}, fmt.Errorf("Fehler beim Lesen: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	a.logs.mu.Lock()
+	a.logs.next++
+	t.id = fmt.Sprintf("log-%d", a.logs.next)
+	a.logs.tails[t.id] = t
+	a.logs.mu.Unlock()
+	go a.follow(ctx, t)
+	return LogSession{ID: t.id, Path: path, Lines: lines}, nil
+}
+
+// PauseLog stops or resumes following; lines written while paused are
+// sent on resume
+func (a *App) PauseLog(id string, paused bool) {
+	a.logs.mu.Lock()
+	t, ok := a.logs.tails[id]
+	a.logs.mu.Unlock()
+	if !ok {
+		return
+	}
+	t.paused.Store(paused)
+	if !paused {
+		select {
+		case t.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// CloseLog stops following a log file
+func (a *App) CloseLog(id string) {
+	a.logs.mu.Lock()
+	defer a.logs.mu.Unlock()
+	if t, ok := a.logs.tails[id]; ok {
+		t.cancel()
+		delete(a.logs.tails, id)
+	}
+}
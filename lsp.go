@@ -0,0 +1,909 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// lspServerSpec describes how a language server is started
+type lspServerSpec struct {
+	Command []string
+	// Maps file extensions to the LSP languageId sent in didOpen
+	Languages map[string]string
+	// Files that mark the project root for this server
+	RootMarkers []string
+}
+
+// lspServers is the built-in table of supported language servers
+var lspServers = map[string]lspServerSpec{
+	"gopls": {
+		Command:     []string{"gopls"},
+		Languages:   map[string]string{".go": "go"},
+		RootMarkers: []string{"go.work", "go.mod"},
+	},
+	"typescript": {
+		Command: []string{"typescript-language-server", "--stdio"},
+		Languages: map[string]string{
+			".js":  "javascript",
+			".mjs": "javascript",
+			".cjs": "javascript",
+			".jsx": "javascriptreact",
+			".ts":  "typescript",
+			".tsx": "typescriptreact",
+		},
+		RootMarkers: []string{"tsconfig.json", "jsconfig.json", "package.json"},
+	},
+	"pylsp": {
+		Command:     []string{"pylsp"},
+		Languages:   map[string]string{".py": "python"},
+		RootMarkers: []string{"pyproject.toml", "setup.py", "setup.cfg", "requirements.txt"},
+	},
+}
+
+const lspRequestTimeout = 15 * time.Second
+
+// LSPPosition is a zero-based line/character position (UTF-16 code units, like CodeMirror)
+type LSPPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type LSPRange struct {
+	Start LSPPosition `json:"start"`
+	End   LSPPosition `json:"end"`
+}
+
+type LSPLocation struct {
+	Path  string   `json:"path"`
+	Range LSPRange `json:"range"`
+}
+
+type LSPDiagnostic struct {
+	Range    LSPRange `json:"range"`
+	Severity int      `json:"severity"` // 1 Error, 2 Warning, 3 Information, 4 Hint
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type LSPCompletionItem struct {
+	Label         string `json:"label"`
+	Kind          int    `json:"kind"`
+	Detail        string `json:"detail"`
+	Documentation string `json:"documentation"`
+	InsertText    string `json:"insertText"`
+}
+
+type LSPTextEdit struct {
+	Range   LSPRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+// LSPFileEdits groups the edits of a rename for one file
+type LSPFileEdits struct {
+	Path  string        `json:"path"`
+	Edits []LSPTextEdit `json:"edits"`
+}
+
+// ---------------------------------------------------------------------------
+// JSON-RPC client
+// ---------------------------------------------------------------------------
+
+type lspMessage struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *lspError        `json:"error,omitempty"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *lspError) Error() string {
+	return fmt.Sprintf("LSP-Fehler %d: %s", e.Code, e.Message)
+}
+
+// lspClient talks JSON-RPC to one language server process over stdio
+type lspClient struct {
+	name    string
+	rootDir string
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan *lspMessage
+	// Open documents and their current version
+	versions map[string]int
+	done     chan struct{}
+
+	onNotify func(method string, params json.RawMessage)
+}
+
+func startLSPClient(name string, spec lspServerSpec, rootDir string, onNotify func(string, json.RawMessage)) (*lspClient, error) {
+	if _, err := exec.LookPath(spec.Command[0]); err != nil {
+		return nil, fmt.Errorf("Language Server %s nicht gefunden: %w", spec.Command[0], err)
+	}
+
+	cmd := exec.Command(spec.Command[0], spec.Command[1:]...)
+	cmd.Dir = rootDir
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("Language Server %s konnte nicht gestartet werden: %w", name, err)
+	}
+
+	c := &lspClient{
+		name:     name,
+		rootDir:  rootDir,
+		cmd:      cmd,
+		stdin:    stdin,
+		pending:  make(map[int]chan *lspMessage),
+		versions: make(map[string]int),
+		done:     make(chan struct{}),
+		onNotify: onNotify,
+	}
+	go c.readLoop(bufio.NewReader(stdout))
+	go func() {
+		cmd.Wait()
+		close(c.done)
+	}()
+
+	if err := c.initialize(); err != nil {
+		c.kill()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *lspClient) initialize() error {
+	params := map[string]any{
+		"processId": os.Getpid(),
+		"rootUri":   pathToURI(c.rootDir),
+		"workspaceFolders": []map[string]string{
+			{"uri": pathToURI(c.rootDir), "name": filepath.Base(c.rootDir)},
+		},
+		"capabilities": map[string]any{
+			"textDocument": map[string]any{
+				"synchronization": map[string]any{"didSave": true},
+				"completion": map[string]any{
+					"completionItem": map[string]any{
+						"snippetSupport":          false,
+						"documentationFormat":     []string{"markdown", "plaintext"},
+						"insertReplaceSupport":    false,
+						"labelDetailsSupport":     true,
+						"deprecatedSupport":       true,
+						"resolveSupport":          map[string]any{"properties": []string{}},
+						"commitCharactersSupport": false,
+					},
+				},
+				"hover":              map[string]any{"contentFormat": []string{"markdown", "plaintext"}},
+				"definition":         map[string]any{"linkSupport": true},
+				"references":         map[string]any{},
+				"rename":             map[string]any{"prepareSupport": false},
+				"publishDiagnostics": map[string]any{"relatedInformation": false},
+			},
+			"workspace": map[string]any{
+				"workspaceFolders": true,
+				"configuration":    true,
+				"workspaceEdit":    map[string]any{"documentChanges": true},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := c.call(ctx, "initialize", params, nil); err != nil {
+		return fmt.Errorf("Initialisierung von %s fehlgeschlagen: %w", c.name, err)
+	}
+	return c.notify("initialized", map[string]any{})
+}
+
+func (c *lspClient) write(msg *lspMessage) error {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := fmt.Fprintf(c.stdin, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.stdin.Write(body)
+	return err
+}
+
+func (c *lspClient) notify(method string, params any) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.write(&lspMessage{Method: method, Params: raw})
+}
+
+// call sends a request and decodes the result into result (if not nil)
+func (c *lspClient) call(ctx context.Context, method string, params any, result any) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	ch := make(chan *lspMessage, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	idRaw := json.RawMessage(strconv.Itoa(id))
+	if err := c.write(&lspMessage{ID: &idRaw, Method: method, Params: raw}); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && len(resp.Result) > 0 {
+			return json.Unmarshal(resp.Result, result)
+		}
+		return nil
+	case <-c.done:
+		return fmt.Errorf("Language Server %s wurde beendet", c.name)
+	case <-ctx.Done():
+		c.notify("$/cancelRequest", map[string]int{"id": id})
+		return ctx.Err()
+	}
+}
+
+func (c *lspClient) readLoop(r *bufio.Reader) {
+	for {
+		msg, err := readLSPMessage(r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				fmt.Printf("LSP %s: read error: %v\n", c.name, err)
+			}
+			return
+		}
+
+		switch {
+		case msg.ID != nil && msg.Method != "":
+			// Request from the server to the client
+			c.handleServerRequest(msg)
+		case msg.ID != nil:
+			id, err := strconv.Atoi(string(*msg.ID))
+			if err != nil {
+				continue
+			}
+			c.mu.Lock()
+			ch := c.pending[id]
+			c.mu.Unlock()
+			if ch != nil {
+				ch <- msg
+			}
+		case msg.Method != "":
+			if c.onNotify != nil {
+				c.onNotify(msg.Method, msg.Params)
+			}
+		}
+	}
+}
+
+// handleServerRequest answers requests the server sends to us. We do not
+// support any of them in a meaningful way, but servers like gopls block
+// until they get an answer.
+func (c *lspClient) handleServerRequest(msg *lspMessage) {
+	var result any
+	switch msg.Method {
+	case "workspace/configuration":
+		var params struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		json.Unmarshal(msg.Params, &params)
+		result = make([]any, len(params.Items))
+	case "workspace/workspaceFolders":
+		result = []map[string]string{{"uri": pathToURI(c.rootDir), "name": filepath.Base(c.rootDir)}}
+	case "window/workDoneProgress/create", "client/registerCapability", "client/unregisterCapability":
+		result = nil
+	default:
+		c.write(&lspMessage{ID: msg.ID, Error: &lspError{Code: -32601, Message: "method not found: " + msg.Method}})
+		return
+	}
+	raw, _ := json.Marshal(result)
+	c.write(&lspMessage{ID: msg.ID, Result: raw})
+}
+
+func readLSPMessage(r *bufio.Reader) (*lspMessage, error) {
+	body, err := readFramedMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	var msg lspMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// readFramedMessage reads one "Content-Length" framed message body, the wire
+// format shared by LSP and the Debug Adapter Protocol
+func readFramedMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if v, ok := strings.CutPrefix(line, "Content-Length:"); ok {
+			length, err = strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length: %q", v)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func (c *lspClient) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := c.call(ctx, "shutdown", nil, nil); err == nil {
+		c.notify("exit", nil)
+	}
+	select {
+	case <-c.done:
+	case <-time.After(2 * time.Second):
+		c.kill()
+	}
+}
+
+func (c *lspClient) kill() {
+	if c.cmd.Process != nil {
+		c.cmd.Process.Kill()
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Manager
+// ---------------------------------------------------------------------------
+
+// lspManager keeps one running client per server and project root
+type lspManager struct {
+	app      *App
+	mu       sync.Mutex
+	clients  map[string]*lspClient
+	starting map[string]*lspStart
+	closed   bool // After shutdownAll no servers are started
+}
+
+// lspStart is a server start in progress; callers for the same key wait
+// for done instead of starting a second server
+type lspStart struct {
+	done   chan struct{}
+	client *lspClient
+	err    error
+}
+
+func newLSPManager(app *App) *lspManager {
+	return &lspManager{app: app, clients: make(map[string]*lspClient), starting: make(map[string]*lspStart)}
+}
+
+// lspServerFor returns the server name and languageId responsible for path
+func lspServerFor(path string) (string, string, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	for name, spec := range lspServers {
+		if lang, ok := spec.Languages[ext]; ok {
+			return name, lang, true
+		}
+	}
+	return "", "", false
+}
+
+// findProjectRoot walks up from dir until one of the markers is found
+func findProjectRoot(dir string, markers []string) string {
+	markers = append(markers, ".git")
+	for d := dir; ; {
+		for _, m := range markers {
+			if _, err := os.Stat(filepath.Join(d, m)); err == nil {
+				return d
+			}
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
+
+// clientKey returns the server and project root responsible for path
+func clientKey(path string) (key, name, lang, root string, ok bool) {
+	name, lang, ok = lspServerFor(path)
+	if !ok {
+		return "", "", "", "", false
+	}
+	root = findProjectRoot(filepath.Dir(path), lspServers[name].RootMarkers)
+	return name + "\x00" + root, name, lang, root, true
+}
+
+// runningClient returns the client of key if it is still alive; m.mu is held
+func (m *lspManager) runningClient(key string) *lspClient {
+	c, ok := m.clients[key]
+	if !ok {
+		return nil
+	}
+	select {
+	case <-c.done:
+		delete(m.clients, key)
+		return nil
+	default:
+		return c
+	}
+}
+
+// existingClient returns the running client for path without starting one
+func (m *lspManager) existingClient(path string) *lspClient {
+	key, _, _, _, ok := clientKey(path)
+	if !ok {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.runningClient(key)
+}
+
+// clientFor returns the running client for path, starting it on demand.
+// The start happens outside m.mu, so other files are not blocked while a
+// server initializes.
+func (m *lspManager) clientFor(path string) (*lspClient, string, error) {
+	key, name, lang, root, ok := clientKey(path)
+	if !ok {
+		return nil, "", fmt.Errorf("Kein Language Server für %s konfiguriert", filepath.Base(path))
+	}
+
+	m.mu.Lock()
+	if c := m.runningClient(key); c != nil {
+		m.mu.Unlock()
+		return c, lang, nil
+	}
+	if m.closed {
+		m.mu.Unlock()
+		return nil, "", fmt.Errorf("Language Server werden beendet")
+	}
+	st, waiting := m.starting[key]
+	if !waiting {
+		st = &lspStart{done: make(chan struct{})}
+		m.starting[key] = st
+	}
+	m.mu.Unlock()
+
+	if waiting {
+		<-st.done
+		return st.client, lang, st.err
+	}
+
+	c, err := startLSPClient(name, lspServers[name], root, m.handleNotification)
+	m.mu.Lock()
+	delete(m.starting, key)
+	if err == nil && m.closed {
+		err = fmt.Errorf("Language Server werden beendet")
+		go c.shutdown()
+		c = nil
+	}
+	if err == nil {
+		m.clients[key] = c
+	}
+	st.client, st.err = c, err
+	m.mu.Unlock()
+	close(st.done)
+	return c, lang, err
+}
+
+func (m *lspManager) handleNotification(method string, params json.RawMessage) {
+	switch method {
+	case "textDocument/publishDiagnostics":
+		var p struct {
+			URI         string          `json:"uri"`
+			Diagnostics []LSPDiagnostic `json:"diagnostics"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return
+		}
+		if p.Diagnostics == nil {
+			p.Diagnostics = []LSPDiagnostic{}
+		}
+		if m.app.ctx != nil {
+			runtime.EventsEmit(m.app.ctx, "lsp-diagnostics", map[string]interface{}{
+				"path":        uriToPath(p.URI),
+				"diagnostics": p.Diagnostics,
+			})
+		}
+	case "window/showMessage":
+		var p struct {
+			Type    int    `json:"type"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(params, &p) == nil && p.Type == 1 && m.app.ctx != nil {
+			runtime.EventsEmit(m.app.ctx, "error", p.Message)
+		}
+	}
+}
+
+// documentSaved sends didSave to servers that have path open, without
+// starting a server just for that
+func (m *lspManager) documentSaved(path string) {
+	m.mu.Lock()
+	clients := make([]*lspClient, 0, len(m.clients))
+	for _, c := range m.clients {
+		clients = append(clients, c)
+	}
+	m.mu.Unlock()
+	for _, c := range clients {
+		c.mu.Lock()
+		_, open := c.versions[path]
+		c.mu.Unlock()
+		if open {
+			c.notify("textDocument/didSave", map[string]any{
+				"textDocument": map[string]string{"uri": pathToURI(path)},
+			})
+		}
+	}
+}
+
+func (m *lspManager) shutdownAll() {
+	m.mu.Lock()
+	clients := m.clients
+	m.clients = make(map[string]*lspClient)
+	m.closed = true
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, c := range clients {
+		wg.Add(1)
+		go func(c *lspClient) {
+			defer wg.Done()
+			c.shutdown()
+		}(c)
+	}
+	wg.Wait()
+}
+
+func pathToURI(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}
+	return u.String()
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func textDocumentPosition(path string, line, character int) map[string]any {
+	return map[string]any{
+		"textDocument": map[string]string{"uri": pathToURI(path)},
+		"position":     LSPPosition{Line: line, Character: character},
+	}
+}
+
+// decodeLSPLocations accepts Location, []Location and []LocationLink
+func decodeLSPLocations(raw json.RawMessage) []LSPLocation {
+	type location struct {
+		URI                  string   `json:"uri"`
+		Range                LSPRange `json:"range"`
+		TargetURI            string   `json:"targetUri"`
+		TargetSelectionRange LSPRange `json:"targetSelectionRange"`
+	}
+	var list []location
+	if err := json.Unmarshal(raw, &list); err != nil {
+		var single location
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return []LSPLocation{}
+		}
+		list = []location{single}
+	}
+
+	result := []LSPLocation{}
+	for _, l := range list {
+		if l.TargetURI != "" {
+			result = append(result, LSPLocation{Path: uriToPath(l.TargetURI), Range: l.TargetSelectionRange})
+		} else if l.URI != "" {
+			result = append(result, LSPLocation{Path: uriToPath(l.URI), Range: l.Range})
+		}
+	}
+	return result
+}
+
+// markupToString flattens MarkupContent, MarkedString and arrays of them
+func markupToString(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var markup struct {
+		Language string `json:"language"`
+		Value    string `json:"value"`
+	}
+	if json.Unmarshal(raw, &markup) == nil && markup.Value != "" {
+		if markup.Language != "" {
+			return "```" + markup.Language + "\n" + markup.Value + "\n```"
+		}
+		return markup.Value
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		parts := make([]string, 0, len(list))
+		for _, item := range list {
+			if p := markupToString(item); p != "" {
+				parts = append(parts, p)
+			}
+		}
+		return strings.Join(parts, "\n\n")
+	}
+	return ""
+}
+
+// ---------------------------------------------------------------------------
+// Methods exported to JavaScript
+// ---------------------------------------------------------------------------
+
+// LSPOpenDocument tells the responsible language server that a buffer was opened
+func (a *App) LSPOpenDocument(path string, content string) error {
+	c, lang, err := a.lsp.clientFor(path)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.versions[path] = 1
+	c.mu.Unlock()
+	return c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{
+			"uri":        pathToURI(path),
+			"languageId": lang,
+			"version":    1,
+			"text":       content,
+		},
+	})
+}
+
+// LSPChangeDocument sends the full new buffer content to the language server
+func (a *App) LSPChangeDocument(path string, content string) error {
+	c, _, err := a.lsp.clientFor(path)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	_, open := c.versions[path]
+	c.versions[path]++
+	version := c.versions[path]
+	c.mu.Unlock()
+	if !open {
+		return a.LSPOpenDocument(path, content)
+	}
+	return c.notify("textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": pathToURI(path), "version": version},
+		"contentChanges": []map[string]string{{"text": content}},
+	})
+}
+
+// LSPSaveDocument notifies the language server that the buffer was written to disk
+func (a *App) LSPSaveDocument(path string) error {
+	c := a.lsp.existingClient(path)
+	if c == nil {
+		return nil
+	}
+	return c.notify("textDocument/didSave", map[string]any{
+		"textDocument": map[string]string{"uri": pathToURI(path)},
+	})
+}
+
+// LSPCloseDocument tells the language server that the buffer was closed
+func (a *App) LSPCloseDocument(path string) error {
+	// A server that is not running has nothing open
+	c := a.lsp.existingClient(path)
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	delete(c.versions, path)
+	c.mu.Unlock()
+	return c.notify("textDocument/didClose", map[string]any{
+		"textDocument": map[string]string{"uri": pathToURI(path)},
+	})
+}
+
+// LSPCompletion returns completion proposals at the given position
+func (a *App) LSPCompletion(path string, line, character int) ([]LSPCompletionItem, error) {
+	c, _, err := a.lsp.clientFor(path)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lspRequestTimeout)
+	defer cancel()
+
+	var raw json.RawMessage
+	if err := c.call(ctx, "textDocument/completion", textDocumentPosition(path, line, character), &raw); err != nil {
+		return nil, err
+	}
+
+	type completionItem struct {
+		Label         string          `json:"label"`
+		Kind          int             `json:"kind"`
+		Detail        string          `json:"detail"`
+		Documentation json.RawMessage `json:"documentation"`
+		InsertText    string          `json:"insertText"`
+		TextEdit      *LSPTextEdit    `json:"textEdit"`
+	}
+	var items []completionItem
+	if err := json.Unmarshal(raw, &items); err != nil {
+		var list struct {
+			Items []completionItem `json:"items"`
+		}
+		json.Unmarshal(raw, &list)
+		items = list.Items
+	}
+
+	result := make([]LSPCompletionItem, 0, len(items))
+	for _, it := range items {
+		insert := it.InsertText
+		if it.TextEdit != nil {
+			insert = it.TextEdit.NewText
+		}
+		if insert == "" {
+			insert = it.Label
+		}
+		result = append(result, LSPCompletionItem{
+			Label:         it.Label,
+			Kind:          it.Kind,
+			Detail:        it.Detail,
+			Documentation: markupToString(it.Documentation),
+			InsertText:    insert,
+		})
+	}
+	return result, nil
+}
+
+// LSPHover returns the hover text (usually Markdown) at the given position
+func (a *App) LSPHover(path string, line, character int) (string, error) {
+	c, _, err := a.lsp.clientFor(path)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lspRequestTimeout)
+	defer cancel()
+
+	var hover struct {
+		Contents json.RawMessage `json:"contents"`
+	}
+	if err := c.call(ctx, "textDocument/hover", textDocumentPosition(path, line, character), &hover); err != nil {
+		return "", err
+	}
+	return markupToString(hover.Contents), nil
+}
+
+// LSPDefinition returns the definition location(s) of the symbol at the position
+func (a *App) LSPDefinition(path string, line, character int) ([]LSPLocation, error) {
+	c, _, err := a.lsp.clientFor(path)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lspRequestTimeout)
+	defer cancel()
+
+	var raw json.RawMessage
+	if err := c.call(ctx, "textDocument/definition", textDocumentPosition(path, line, character), &raw); err != nil {
+		return nil, err
+	}
+	return decodeLSPLocations(raw), nil
+}
+
+// LSPReferences returns all references to the symbol at the position
+func (a *App) LSPReferences(path string, line, character int) ([]LSPLocation, error) {
+	c, _, err := a.lsp.clientFor(path)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lspRequestTimeout)
+	defer cancel()
+
+	params := textDocumentPosition(path, line, character)
+	params["context"] = map[string]bool{"includeDeclaration": true}
+	var raw json.RawMessage
+	if err := c.call(ctx, "textDocument/references", params, &raw); err != nil {
+		return nil, err
+	}
+	return decodeLSPLocations(raw), nil
+}
+
+// LSPRename asks the server for the edits needed to rename the symbol at the position.
+// The edits are returned to the frontend, which applies them to open buffers.
+func (a *App) LSPRename(path string, line, character int, newName string) ([]LSPFileEdits, error) {
+	c, _, err := a.lsp.clientFor(path)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lspRequestTimeout)
+	defer cancel()
+
+	params := textDocumentPosition(path, line, character)
+	params["newName"] = newName
+	var edit struct {
+		Changes         map[string][]LSPTextEdit `json:"changes"`
+		DocumentChanges []struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+			Edits []LSPTextEdit `json:"edits"`
+		} `json:"documentChanges"`
+	}
+	if err := c.call(ctx, "textDocument/rename", params, &edit); err != nil {
+		return nil, err
+	}
+
+	result := []LSPFileEdits{}
+	for _, dc := range edit.DocumentChanges {
+		if dc.TextDocument.URI != "" {
+			result = append(result, LSPFileEdits{Path: uriToPath(dc.TextDocument.URI), Edits: dc.Edits})
+		}
+	}
+	if len(result) == 0 {
+		for uri, edits := range edit.Changes {
+			result = append(result, LSPFileEdits{Path: uriToPath(uri), Edits: edits})
+		}
+	}
+	return result, nil
+}
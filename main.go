@@ -26,8 +26,15 @@ func main() {
 			os.Setenv("WEBKIT_DISABLE_CONTEXT_MENU", "0")
 		}
 	}
+	// Läuft Leoedit schon, übernimmt die laufende Instanz die Argumente
+	listener, ok := acquireInstance(os.Args[1:])
+	if !ok {
+		return
+	}
+
 	// App-Instanz erstellen (aus app.go)
 	app := NewApp()
+	app.instance.listener = listener
 
 	// App mit Menü und Assets starten
 	err := wails.Run(&options.App{
@@ -51,6 +58,7 @@ func main() {
 		},
 		OnStartup:     app.startup,
 		OnBeforeClose: app.onWindowClose,
+		OnShutdown:    app.shutdown,
 		Bind: []interface{}{
 			app,
 		},
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ProcessInfo describes a process started by the task runner
+type ProcessInfo struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Command  string    `json:"command"`
+	Dir      string    `json:"dir"`
+	Running  bool      `json:"running"`
+	ExitCode int       `json:"exitCode"`
+	Started  time.Time `json:"started"`
+	Duration float64   `json:"duration"` // Seconds, set when finished
+}
+
+// OutputLine is one line of process output. Path/Line/Column are set when a
+// problem matcher found a source location in it (1-based, 0 = unknown).
+type OutputLine struct {
+	ProcessID string `json:"processId"`
+	Stream    string `json:"stream"` // stdout, stderr
+	Text      string `json:"text"`
+	Path      string `json:"path,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	Column    int    `json:"column,omitempty"`
+}
+
+// processSpec is what the runners (tasks, run file, tests) hand to the process manager
+type processSpec struct {
+	Name    string
+	Command string
+	Args    []string
+	Dir     string
+	Env     []string // Added to the environment of the editor
+	Pattern string   // Problem matcher, default file:line:col
+	// Called for every output line before it is matched and sent; returning
+	// false drops the line. May be nil.
+	OnLine func(line *OutputLine) bool
+	// Called after the process has exited, may be nil
+	OnExit func(info ProcessInfo)
+}
+
+type managedProcess struct {
+	info   ProcessInfo
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Number of finished processes kept for the process list
+const maxFinishedProcesses = 20
+
+type processManager struct {
+	app    *App
+	mu     sync.Mutex
+	procs  map[string]*managedProcess
+	nextID int
+}
+
+func newProcessManager(app *App) *processManager {
+	return &processManager{app: app, procs: make(map[string]*managedProcess)}
+}
+
+// start runs spec and streams its output as "process-output" events.
+// "process-exit" is emitted when the process has finished.
+func (m *processManager) start(spec processSpec) (ProcessInfo, error) {
+	re, err := m.app.linters.pattern(spec.Pattern)
+	if err != nil {
+		return ProcessInfo{}, fmt.Errorf("ungültiges Muster: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, spec.Command, spec.Args...)
+	cmd.Dir = spec.Dir
+	cmd.Env = append(os.Environ(), spec.Env...)
+	setProcessGroup(cmd)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return ProcessInfo{}, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		cancel()
+		return ProcessInfo{}, err
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return ProcessInfo{}, err
+	}
+
+	m.mu.Lock()
+	m.nextID++
+	p := &managedProcess{
+		info: ProcessInfo{
+			ID:      fmt.Sprintf("proc-%d", m.nextID),
+			Name:    spec.Name,
+			Command: commandLine(spec.Command, spec.Args),
+			Dir:     spec.Dir,
+			Running: true,
+			Started: time.Now(),
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	m.procs[p.info.ID] = p
+	info := p.info
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go m.stream(&wg, p.info.ID, "stdout", stdout, re, spec)
+	go m.stream(&wg, p.info.ID, "stderr", stderr, re, spec)
+
+	go func() {
+		// Pipes must be drained before Wait closes them
+		wg.Wait()
+		err := cmd.Wait()
+		cancel()
+
+		m.mu.Lock()
+		p.info.Running = false
+		p.info.ExitCode = cmd.ProcessState.ExitCode()
+		p.info.Duration = time.Since(p.info.Started).Seconds()
+		info := p.info
+		m.pruneLocked()
+		m.mu.Unlock()
+		close(p.done)
+
+		if spec.OnExit != nil {
+			spec.OnExit(info)
+		}
+		if m.app.ctx != nil {
+			msg := ""
+			if err != nil {
+				msg = err.Error()
+			}
+			runtime.EventsEmit(m.app.ctx, "process-exit", map[string]interface{}{
+				"id":    info.ID,
+				"info":  info,
+				"error": msg,
+			})
+		}
+	}()
+
+	return info, nil
+}
+
+func (m *processManager) stream(wg *sync.WaitGroup, id string, name string, r io.Reader, re *regexp.Regexp, spec processSpec) {
+	defer wg.Done()
+	emit := func(line OutputLine) {
+		if m.app.ctx != nil {
+			runtime.EventsEmit(m.app.ctx, "process-output", line)
+		}
+	}
+	// Overlong lines are cut, the pipe is always read to the end so the
+	// process never blocks on a full pipe
+	br := bufio.NewReaderSize(r, 64*1024)
+	for {
+		text, truncated, err := readLine(br)
+		if err == io.EOF && text == "" {
+			return
+		}
+		if truncated {
+			text += " …"
+		}
+		line := OutputLine{ProcessID: id, Stream: name, Text: text}
+		if spec.OnLine == nil || spec.OnLine(&line) {
+			if line.Path == "" {
+				line.Path, line.Line, line.Column = matchLocation(re, line.Text, spec.Dir)
+			}
+			emit(line)
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			emit(OutputLine{ProcessID: id, Stream: "stderr", Text: fmt.Sprintf("Fehler beim Lesen der Ausgabe: %v", err)})
+			io.Copy(io.Discard, r)
+			return
+		}
+	}
+}
+
+// matchLocation returns the source location in an output line, if any
+func matchLocation(re *regexp.Regexp, text string, dir string) (string, int, int) {
+	p, ok := matchProblem(re, text, dir)
+	if !ok || p.Line <= 0 {
+		return "", 0, 0
+	}
+	// Only link to files that exist, output like "url:80:..." is no location
+	if info, err := os.Stat(p.Path); err != nil || info.IsDir() {
+		return "", 0, 0
+	}
+	return p.Path, p.Line, p.Column
+}
+
+// pruneLocked drops the oldest finished processes from the list
+func (m *processManager) pruneLocked() {
+	var finished []*managedProcess
+	for _, p := range m.procs {
+		if !p.info.Running {
+			finished = append(finished, p)
+		}
+	}
+	if len(finished) <= maxFinishedProcesses {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].info.Started.Before(finished[j].info.Started) })
+	for _, p := range finished[:len(finished)-maxFinishedProcesses] {
+		delete(m.procs, p.info.ID)
+	}
+}
+
+func (m *processManager) list() []ProcessInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]ProcessInfo, 0, len(m.procs))
+	for _, p := range m.procs {
+		result = append(result, p.info)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Started.Before(result[j].Started) })
+	return result
+}
+
+func (m *processManager) stop(id string) error {
+	m.mu.Lock()
+	p, ok := m.procs[id]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("Prozess %s nicht gefunden", id)
+	}
+	p.cancel()
+	return nil
+}
+
+// stopAll kills all running processes, called when the app shuts down
+func (m *processManager) stopAll() {
+	m.mu.Lock()
+	var running []*managedProcess
+	for _, p := range m.procs {
+		if p.info.Running {
+			running = append(running, p)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, p := range running {
+		p.cancel()
+		select {
+		case <-p.done:
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+var needsQuoteRe = regexp.MustCompile(`[\s"']`)
+
+// commandLine formats a command for display
+func commandLine(command string, args []string) string {
+	line := command
+	for _, a := range args {
+		if a == "" || needsQuoteRe.MatchString(a) {
+			a = fmt.Sprintf("%q", a)
+		}
+		line += " " + a
+	}
+	return line
+}
+
+// ListProcesses returns the running and recently finished processes
+func (a *App) ListProcesses() []ProcessInfo {
+	return a.processes.list()
+}
+
+// StopProcess cancels a running process
+func (a *App) StopProcess(id string) error {
+	return a.processes.stop(id)
+}
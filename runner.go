@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// RunnerConfig describes how files of one type are run. "${file}" and
+// "${dir}" in Args are replaced with the file and its directory; Env is added
+// to the environment of the editor.
+type RunnerConfig struct {
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// Built-in runners per file extension; entries in Settings.Runners replace them
+var defaultRunners = map[string]RunnerConfig{
+	".go":  {Command: "go", Args: []string{"run", "${file}"}},
+	".py":  {Command: pythonCommand(), Args: []string{"${file}"}},
+	".js":  {Command: "node", Args: []string{"${file}"}},
+	".mjs": {Command: "node", Args: []string{"${file}"}},
+	".cjs": {Command: "node", Args: []string{"${file}"}},
+	".sh":  {Command: "bash", Args: []string{"${file}"}},
+}
+
+func pythonCommand() string {
+	if runtime.GOOS == "windows" {
+		return "python"
+	}
+	return "python3"
+}
+
+// shebangRunner builds a runner from the "#!" line of a script.
+// "#!/usr/bin/env -S python3 -u" runs "python3 -u file".
+func shebangRunner(path string) (RunnerConfig, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return RunnerConfig{}, false
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && line == "" {
+		return RunnerConfig{}, false
+	}
+	if !strings.HasPrefix(line, "#!") {
+		return RunnerConfig{}, false
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) > 0 && filepath.Base(fields[0]) == "env" {
+		fields = fields[1:]
+		if len(fields) > 0 && fields[0] == "-S" {
+			fields = fields[1:]
+		}
+	}
+	if len(fields) == 0 {
+		return RunnerConfig{}, false
+	}
+	return RunnerConfig{Command: fields[0], Args: append(fields[1:], "${file}")}, true
+}
+
+// runnerFor picks the runner by file extension, then by shebang
+func (a *App) runnerFor(path string) (RunnerConfig, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if r, ok := a.prefs().Runners[ext]; ok && r.Command != "" {
+		return r, true
+	}
+	if r, ok := defaultRunners[ext]; ok {
+		return r, true
+	}
+	return shebangRunner(path)
+}
+
+// RunFile runs a saved file with the runner for its type in the file's
+// directory. Output is streamed as "process-output" events.
+func (a *App) RunFile(path string) (ProcessInfo, error) {
+	if path == "" {
+		return ProcessInfo{}, fmt.Errorf("Die Datei muss zuerst gespeichert werden")
+	}
+	r, ok := a.runnerFor(path)
+	if !ok {
+		return ProcessInfo{}, fmt.Errorf("Kein Runner für %s konfiguriert", filepath.Base(path))
+	}
+
+	dir := filepath.Dir(path)
+	args := make([]string, len(r.Args))
+	for i, arg := range r.Args {
+		arg = strings.ReplaceAll(arg, "${file}", path)
+		args[i] = strings.ReplaceAll(arg, "${dir}", dir)
+	}
+
+	info, err := a.processes.start(processSpec{
+		Name:    filepath.Base(path),
+		Command: r.Command,
+		Args:    args,
+		Dir:     dir,
+		Env:     envList(r.Env),
+	})
+	if err != nil {
+		return ProcessInfo{}, fmt.Errorf("Fehler beim Starten von %s: %w", r.Command, err)
+	}
+	return info, nil
+}
+
+// GetRunners returns the runner table, built-in entries merged with the configured ones
+func (a *App) GetRunners() map[string]RunnerConfig {
+	configured := a.prefs().Runners
+	runners := make(map[string]RunnerConfig, len(defaultRunners)+len(configured))
+	for ext, r := range defaultRunners {
+		runners[ext] = r
+	}
+	for ext, r := range configured {
+		runners[ext] = r
+	}
+	return runners
+}
+
+// SetRunner configures the runner for a file extension, an empty command
+// restores the built-in one
+func (a *App) SetRunner(ext string, runner RunnerConfig) string {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	// Only the runners of settings.json, not those of the workspace
+	runners := map[string]RunnerConfig{}
+	a.settings.userValue("runners", &runners)
+	if runner.Command == "" {
+		delete(runners, ext)
+	} else {
+		runners[ext] = runner
+	}
+	if err := a.setSetting("runners", runners); err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	return fmt.Sprintf("Runner für %s gespeichert", ext)
+}
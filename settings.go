@@ -0,0 +1,520 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// settingsVersion is the current layout of settings.json, see settingsMigrations
+const settingsVersion = 1
+
+// Settings are the effective user preferences: schema defaults, overridden by
+// settings.json and then by the "settings" of the open workspace
+type Settings struct {
+	Version        int  `json:"version"`
+	MaxRecentFiles int  `json:"max_recent_files"`
+	FormatOnSave   bool `json:"format_on_save"`
+	// Files above this size open in large file mode
+	LargeFileMB int `json:"large_file_mb"`
+	// Per file extension (".py"), overrides the built-in formatter table
+	Formatters map[string]FormatterConfig `json:"formatters,omitempty"`
+	// Per file extension, replaces the built-in linters for that extension
+	Linters map[string][]LinterConfig `json:"linters,omitempty"`
+	// Per file extension, replaces the built-in runner for "Datei ausführen"
+	Runners  map[string]RunnerConfig `json:"runners,omitempty"`
+	Editor   EditorSettings          `json:"editor"`
+	History  HistorySettings         `json:"history"`
+	Explorer ExplorerSettings        `json:"explorer"`
+	Log      LogSettings             `json:"log"`
+	Archive  ArchiveSettings         `json:"archive"`
+}
+
+// EditorSettings configure the CodeMirror views; .editorconfig wins for indentation
+type EditorSettings struct {
+	FontSize    int    `json:"font_size"`
+	TabSize     int    `json:"tab_size"`
+	IndentStyle string `json:"indent_style"`
+	WordWrap    bool   `json:"word_wrap"`
+	LineNumbers bool   `json:"line_numbers"`
+}
+
+// HistorySettings limit the local file history per file
+type HistorySettings struct {
+	Enabled      bool `json:"enabled"`
+	MaxRevisions int  `json:"max_revisions"`
+	MaxAgeDays   int  `json:"max_age_days"`
+	MaxSizeMB    int  `json:"max_size_mb"`
+}
+
+// ExplorerSettings filter the entries of the file explorer
+type ExplorerSettings struct {
+	ShowHidden  bool `json:"show_hidden"`
+	HideIgnored bool `json:"hide_ignored"`
+}
+
+// LogSettings configure the log viewer
+type LogSettings struct {
+	Backlog int `json:"backlog"`
+	PollMS  int `json:"poll_ms"`
+}
+
+// ArchiveSettings control the files opened from archives
+type ArchiveSettings struct {
+	Writable bool `json:"writable"`
+}
+
+// SettingDef describes one key of settings.json
+type SettingDef struct {
+	Key         string   `json:"key"`  // Nested keys are joined with "."
+	Type        string   `json:"type"` // bool, int, string, object
+	Default     any      `json:"default,omitempty"`
+	Min         int      `json:"min,omitempty"`
+	Max         int      `json:"max,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Description string   `json:"description"`
+	// For objects: a pointer to the Go type the value has to decode into
+	target func() any
+}
+
+// SettingsResult is the state of the settings for the frontend
+type SettingsResult struct {
+	Settings Settings `json:"settings"`
+	Errors   []string `json:"errors"`
+	Path     string   `json:"path"`
+}
+
+var settingsSchema = []SettingDef{
+	{Key: "max_recent_files", Type: "int", Default: 10, Min: 1, Max: 100,
+		Description: "Anzahl der zuletzt geöffneten Dateien"},
+	{Key: "format_on_save", Type: "bool", Default: false,
+		Description: "Dateien beim Speichern formatieren"},
+	{Key: "large_file_mb", Type: "int", Default: 20, Min: 1, Max: 4096,
+		Description: "Dateien ab dieser Größe (MB) seitenweise laden"},
+	{Key: "formatters", Type: "object", Description: "Formatierer je Dateiendung",
+		target: func() any { return &map[string]FormatterConfig{} }},
+	{Key: "linters", Type: "object", Description: "Linter je Dateiendung",
+		target: func() any { return &map[string][]LinterConfig{} }},
+	{Key: "runners", Type: "object", Description: "Befehle für \"Datei ausführen\" je Dateiendung",
+		target: func() any { return &map[string]RunnerConfig{} }},
+	{Key: "editor.font_size", Type: "int", Default: 14, Min: 6, Max: 48,
+		Description: "Schriftgröße des Editors in Pixel"},
+	{Key: "editor.tab_size", Type: "int", Default: 4, Min: 1, Max: 16,
+		Description: "Tabulatorbreite ohne .editorconfig"},
+	{Key: "editor.indent_style", Type: "string", Default: "space", Enum: []string{"space", "tab"},
+		Description: "Einrückung mit Leerzeichen oder Tabs ohne .editorconfig"},
+	{Key: "editor.word_wrap", Type: "bool", Default: true,
+		Description: "Lange Zeilen umbrechen"},
+	{Key: "editor.line_numbers", Type: "bool", Default: true,
+		Description: "Zeilennummern anzeigen"},
+	{Key: "history.enabled", Type: "bool", Default: true,
+		Description: "Beim Speichern einen Stand im lokalen Verlauf ablegen"},
+	{Key: "history.max_revisions", Type: "int", Default: 50, Min: 1, Max: 1000,
+		Description: "Höchstzahl der Stände je Datei"},
+	{Key: "history.max_age_days", Type: "int", Default: 30, Min: 1, Max: 3650,
+		Description: "Ältere Stände werden gelöscht"},
+	{Key: "history.max_size_mb", Type: "int", Default: 20, Min: 1, Max: 1024,
+		Description: "Höchstgröße des komprimierten Verlaufs je Datei in MB"},
+	{Key: "explorer.show_hidden", Type: "bool", Default: true,
+		Description: "Versteckte Dateien (.name) im Explorer anzeigen"},
+	{Key: "explorer.hide_ignored", Type: "bool", Default: false,
+		Description: "Von .gitignore ausgeschlossene Einträge im Explorer ausblenden"},
+	{Key: "log.backlog", Type: "int", Default: 5000, Min: 100, Max: 100000,
+		Description: "Zeilen, die beim Öffnen einer Log-Datei angezeigt werden"},
+	{Key: "log.poll_ms", Type: "int", Default: 500, Min: 100, Max: 10000,
+		Description: "Abstand in ms, in dem Log-Dateien auf neue Zeilen geprüft werden"},
+	{Key: "archive.writable", Type: "bool", Default: false,
+		Description: "Geänderte Dateien aus zip- und tar-Archiven in das Archiv zurückschreiben"},
+}
+
+// settingsMigrations[v] turns a version v settings map into version v+1
+var settingsMigrations = []func(map[string]any){
+	// 0: the settings were part of config.json, drop the session state
+	func(m map[string]any) {
+		for _, key := range []string{"recent_files", "last_directory", "recent_workspaces", "last_workspace"} {
+			delete(m, key)
+		}
+	},
+}
+
+// settingsManager holds the user layer (settings.json) and the resolved settings
+type settingsManager struct {
+	mu      sync.RWMutex
+	path    string
+	user    map[string]any // settings.json as written by the user
+	broken  bool           // settings.json could not be parsed and must not be overwritten
+	current Settings
+	errors  []string
+}
+
+func newSettingsManager(path string) *settingsManager {
+	s := &settingsManager{path: path, user: map[string]any{}}
+	s.current, _ = resolveSettings()
+	return s
+}
+
+func schemaEntry(key string) *SettingDef {
+	for i := range settingsSchema {
+		if settingsSchema[i].Key == key {
+			return &settingsSchema[i]
+		}
+	}
+	return nil
+}
+
+// flattenSettings joins nested keys with "." down to the keys of the schema
+func flattenSettings(prefix string, m map[string]any, out map[string]any) {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]any); ok && schemaEntry(key) == nil {
+			flattenSettings(key, nested, out)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+// checkSetting validates one value against its schema entry
+func checkSetting(def *SettingDef, v any) error {
+	switch def.Type {
+	case "bool":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("Wahrheitswert erwartet")
+		}
+	case "int":
+		f, ok := v.(float64)
+		if !ok || f != math.Trunc(f) {
+			return fmt.Errorf("ganze Zahl erwartet")
+		}
+		if (def.Min != 0 || def.Max != 0) && (int(f) < def.Min || int(f) > def.Max) {
+			return fmt.Errorf("Wert muss zwischen %d und %d liegen", def.Min, def.Max)
+		}
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("Text erwartet")
+		}
+		if len(def.Enum) > 0 && !slices.Contains(def.Enum, s) {
+			return fmt.Errorf("erlaubt sind: %s", strings.Join(def.Enum, ", "))
+		}
+	case "object":
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		dec := json.NewDecoder(strings.NewReader(string(data)))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(def.target()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveSettings applies the layers in order on top of the schema defaults.
+// Invalid or unknown keys are reported and fall back to the lower layer.
+func resolveSettings(layers ...settingsLayer) (Settings, []string) {
+	flat := map[string]any{}
+	for _, def := range settingsSchema {
+		if def.Default != nil {
+			flat[def.Key] = def.Default
+		}
+	}
+
+	var errs []string
+	for _, layer := range layers {
+		values := map[string]any{}
+		flattenSettings("", layer.values, values)
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if key == "version" {
+				continue
+			}
+			def := schemaEntry(key)
+			if def == nil {
+				errs = append(errs, fmt.Sprintf("%s: unbekannte Einstellung \"%s\"", layer.name, key))
+				continue
+			}
+			if err := checkSetting(def, values[key]); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %s: %v", layer.name, key, err))
+				continue
+			}
+			flat[key] = values[key]
+		}
+	}
+
+	nested := map[string]any{}
+	for key, v := range flat {
+		setNested(nested, key, v)
+	}
+	var s Settings
+	data, _ := json.Marshal(nested)
+	if err := json.Unmarshal(data, &s); err != nil {
+		errs = append(errs, err.Error())
+	}
+	s.Version = settingsVersion
+	return s, errs
+}
+
+// settingsLayer is one source of settings with the name used in error messages
+type settingsLayer struct {
+	name   string
+	values map[string]any
+}
+
+// setNested stores v under a "."-separated key; nil removes the key
+func setNested(m map[string]any, key string, v any) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := m[part].(map[string]any)
+		if !ok {
+			if v == nil {
+				return
+			}
+			next = map[string]any{}
+			m[part] = next
+		}
+		m = next
+	}
+	last := parts[len(parts)-1]
+	if v == nil {
+		delete(m, last)
+	} else {
+		m[last] = v
+	}
+}
+
+// migrateSettings brings a settings map to settingsVersion; it reports
+// whether anything changed
+func migrateSettings(m map[string]any) (bool, error) {
+	version := 0
+	if v, ok := m["version"].(float64); ok {
+		version = int(v)
+	}
+	if version > settingsVersion {
+		return false, fmt.Errorf("settings.json stammt von einer neueren Version (%d)", version)
+	}
+	for ; version < settingsVersion; version++ {
+		settingsMigrations[version](m)
+	}
+	changed := m["version"] != float64(settingsVersion)
+	m["version"] = float64(settingsVersion)
+	return changed, nil
+}
+
+// load reads settings.json; legacy holds config.json for the migration from
+// the time the settings were stored there
+func (s *settingsManager) load(legacy []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors = nil
+	s.broken = false
+	data, err := os.ReadFile(s.path)
+	fromLegacy := false
+	if os.IsNotExist(err) {
+		data, fromLegacy = legacy, true
+		if len(data) == 0 {
+			data = []byte("{}")
+		}
+	} else if err != nil {
+		s.errors = append(s.errors, fmt.Sprintf("Fehler beim Lesen von settings.json: %v", err))
+		s.broken = true
+		return
+	}
+
+	user := map[string]any{}
+	if err := json.Unmarshal(data, &user); err != nil {
+		if fromLegacy {
+			// config.json is broken, that is reported by loadConfig
+			user = map[string]any{}
+		} else {
+			s.errors = append(s.errors, fmt.Sprintf("settings.json: %v", err))
+			s.broken = true
+			return
+		}
+	}
+	if fromLegacy {
+		delete(user, "version")
+	}
+	changed, err := migrateSettings(user)
+	if err != nil {
+		s.errors = append(s.errors, err.Error())
+	}
+	s.user = user
+	if changed || fromLegacy {
+		if err := s.writeLocked(); err != nil {
+			s.errors = append(s.errors, fmt.Sprintf("Fehler beim Speichern von settings.json: %v", err))
+		}
+	}
+}
+
+func (s *settingsManager) writeLocked() error {
+	data, err := json.MarshalIndent(s.user, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// resolve computes the effective settings with the workspace layer on top
+func (s *settingsManager) resolve(ws *Workspace) SettingsResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	layers := []settingsLayer{{name: "settings.json", values: s.user}}
+	if ws != nil && len(ws.Overrides) > 0 {
+		name := "Workspace"
+		if ws.File != "" {
+			name = filepath.Base(ws.File)
+		}
+		layers = append(layers, settingsLayer{name: name, values: ws.Overrides})
+	}
+	current, errs := resolveSettings(layers...)
+	s.current = current
+	errs = append(append([]string{}, s.errors...), errs...)
+	return SettingsResult{Settings: current, Errors: errs, Path: s.path}
+}
+
+// set changes one key of settings.json; nil restores the default
+func (s *settingsManager) set(key string, v any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.broken {
+		return fmt.Errorf("settings.json enthält Fehler und wird nicht überschrieben")
+	}
+	if v != nil {
+		// Normalise Go values to their JSON form (ints become float64)
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if def := schemaEntry(key); def != nil {
+			if err := checkSetting(def, v); err != nil {
+				return fmt.Errorf("%s: %v", key, err)
+			}
+		}
+	}
+	setNested(s.user, key, v)
+	return s.writeLocked()
+}
+
+func (s *settingsManager) get() Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// userValue returns the settings.json value of a top-level object key
+func (s *settingsManager) userValue(key string, target any) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if v, ok := s.user[key]; ok {
+		data, _ := json.Marshal(v)
+		json.Unmarshal(data, target)
+	}
+}
+
+func (s *settingsManager) isFile(path string) bool {
+	return filepath.Clean(path) == s.path
+}
+
+// prefs returns the effective settings
+func (a *App) prefs() Settings {
+	return a.settings.get()
+}
+
+// reloadSettings re-resolves all layers and pushes the result to the frontend
+func (a *App) reloadSettings() SettingsResult {
+	res := a.settings.resolve(a.workspace())
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "settings-changed", res)
+	}
+	return res
+}
+
+// setSetting stores a value in settings.json and applies it
+func (a *App) setSetting(key string, v any) error {
+	if err := a.settings.set(key, v); err != nil {
+		return err
+	}
+	a.reloadSettings()
+	return nil
+}
+
+// configFileSaved applies settings.json or the open workspace file after
+// they were saved in the editor
+func (a *App) configFileSaved(path string) {
+	if a.settings.isFile(path) {
+		a.settings.load(nil)
+		a.reloadSettings()
+		return
+	}
+	ws := a.workspace()
+	if ws == nil || ws.File == "" || filepath.Clean(path) != ws.File {
+		return
+	}
+	reloaded, err := loadWorkspace(ws.File)
+	if err != nil {
+		if a.ctx != nil {
+			runtime.EventsEmit(a.ctx, "error", err.Error())
+		}
+		return
+	}
+	a.ws.mu.Lock()
+	a.ws.current = reloaded
+	a.ws.mu.Unlock()
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "workspace-changed", a.GetWorkspace())
+	}
+	a.reloadSettings()
+}
+
+// GetSettings returns the effective settings and the problems found in the layers
+func (a *App) GetSettings() SettingsResult {
+	return a.settings.resolve(a.workspace())
+}
+
+// GetSettingsSchema lists all known settings with their defaults
+func (a *App) GetSettingsSchema() []SettingDef {
+	return settingsSchema
+}
+
+// SetSetting changes a key of settings.json ("editor.font_size"); null restores the default
+func (a *App) SetSetting(key string, value any) error {
+	if schemaEntry(key) == nil {
+		return fmt.Errorf("Unbekannte Einstellung: %s", key)
+	}
+	return a.setSetting(key, value)
+}
+
+// OpenSettingsFile returns the path of settings.json for editing, creating it if needed
+func (a *App) OpenSettingsFile() (string, error) {
+	if _, err := os.Stat(a.settings.path); os.IsNotExist(err) {
+		a.settings.mu.Lock()
+		err = a.settings.writeLocked()
+		a.settings.mu.Unlock()
+		if err != nil {
+			return "", fmt.Errorf("Fehler beim Anlegen von settings.json: %w", err)
+		}
+	}
+	return a.settings.path, nil
+}
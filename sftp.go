@@ -0,0 +1,453 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const remotePrefix = "sftp://"
+
+// remoteTarget is a parsed sftp://[user@]host[:port]/path
+type remoteTarget struct {
+	User   string
+	Host   string
+	Port   string
+	Path   string // Absolute, slash separated
+	prefix string // sftp://[user@]host[:port] as written
+}
+
+func isRemotePath(p string) bool {
+	return strings.HasPrefix(p, remotePrefix)
+}
+
+// parseRemotePath splits a remote path; the user defaults to the local
+// user name and the port to 22
+func parseRemotePath(p string) (remoteTarget, error) {
+	rest, ok := strings.CutPrefix(p, remotePrefix)
+	authority, rpath, _ := strings.Cut(rest, "/")
+	t := remoteTarget{Port: "22", Path: path.Clean("/" + rpath), prefix: remotePrefix + authority}
+
+	host := authority
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		t.User, host = authority[:i], authority[i+1:]
+	}
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		host, t.Port = h, port
+	}
+	t.Host = host
+	if !ok || t.Host == "" {
+		return remoteTarget{}, fmt.Errorf("Ungültige SFTP-Adresse: %s", p)
+	}
+	if t.User == "" {
+		if u, err := user.Current(); err == nil {
+			// Windows reports DOMAIN\name
+			t.User = u.Username[strings.LastIndex(u.Username, `\`)+1:]
+		}
+	}
+	return t, nil
+}
+
+// url returns the remote path p on the same host; the root has no trailing slash
+func (t remoteTarget) url(p string) string {
+	p = path.Clean("/" + p)
+	if p == "/" {
+		return t.prefix
+	}
+	return t.prefix + p
+}
+
+func (t remoteTarget) addr() string {
+	return net.JoinHostPort(t.Host, t.Port)
+}
+
+// key identifies the cached connection
+func (t remoteTarget) key() string {
+	return t.User + "@" + t.addr()
+}
+
+// cleanRemotePath normalizes a remote path for the recent files list
+func cleanRemotePath(p string) (string, error) {
+	t, err := parseRemotePath(p)
+	if err != nil {
+		return "", err
+	}
+	return t.url(t.Path), nil
+}
+
+type sftpConn struct {
+	ssh  *ssh.Client
+	sftp *sftp.Client
+}
+
+// sftpPool caches one connection per user and host. Connections that
+// drop are removed and dialed again on the next use.
+type sftpPool struct {
+	mu      sync.Mutex
+	conns   map[string]*sftpConn
+	dialing map[string]*sftpDial
+}
+
+// sftpDial is a connection attempt in progress; callers for the same host
+// wait for done instead of dialing again
+type sftpDial struct {
+	done chan struct{}
+	conn *sftpConn
+	err  error
+}
+
+func newSFTPPool() *sftpPool {
+	return &sftpPool{conns: make(map[string]*sftpConn), dialing: make(map[string]*sftpDial)}
+}
+
+// get returns the connection to t. Dialing happens outside p.mu, so an
+// unreachable host does not block the other hosts.
+func (p *sftpPool) get(t remoteTarget) (*sftpConn, error) {
+	key := t.key()
+	p.mu.Lock()
+	if c, ok := p.conns[key]; ok {
+		p.mu.Unlock()
+		return c, nil
+	}
+	d, waiting := p.dialing[key]
+	if !waiting {
+		d = &sftpDial{done: make(chan struct{})}
+		p.dialing[key] = d
+	}
+	p.mu.Unlock()
+	if waiting {
+		<-d.done
+		return d.conn, d.err
+	}
+
+	d.conn, d.err = dialSFTP(t)
+	p.mu.Lock()
+	delete(p.dialing, key)
+	if d.err == nil {
+		p.conns[key] = d.conn
+	}
+	p.mu.Unlock()
+	close(d.done)
+	if d.err != nil {
+		return nil, d.err
+	}
+	c := d.conn
+	go func() {
+		c.ssh.Wait()
+		p.drop(key, c)
+	}()
+	return c, nil
+}
+
+func (p *sftpPool) drop(key string, c *sftpConn) {
+	p.mu.Lock()
+	if p.conns[key] == c {
+		delete(p.conns, key)
+	}
+	p.mu.Unlock()
+	// Closing the transport first keeps sftp.Close from waiting on the server
+	c.ssh.Close()
+	c.sftp.Close()
+}
+
+// do runs fn on a connection to t; a lost connection is dialed again once
+func (p *sftpPool) do(t remoteTarget, fn func(*sftp.Client) error) error {
+	for attempt := 0; ; attempt++ {
+		c, err := p.get(t)
+		if err != nil {
+			return err
+		}
+		err = fn(c.sftp)
+		if err == nil || attempt > 0 || !connectionLost(err) {
+			return err
+		}
+		p.drop(t.key(), c)
+	}
+}
+
+func (p *sftpPool) closeAll() {
+	p.mu.Lock()
+	conns := p.conns
+	p.conns = make(map[string]*sftpConn)
+	p.mu.Unlock()
+	for _, c := range conns {
+		c.ssh.Close()
+		c.sftp.Close()
+	}
+}
+
+func connectionLost(err error) bool {
+	return errors.Is(err, sftp.ErrSSHFxConnectionLost) || errors.Is(err, io.EOF) ||
+		errors.Is(err, net.ErrClosed)
+}
+
+// dialSFTP connects with the keys of the SSH agent and the default key files
+// in ~/.ssh; the host key has to be in ~/.ssh/known_hosts
+func dialSFTP(t remoteTarget) (*sftpConn, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	hostKeys, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("known_hosts nicht lesbar: %w", err)
+	}
+
+	var signers []ssh.Signer
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			// The agent signs during the handshake, so it stays open until then
+			defer conn.Close()
+			if keys, err := agent.NewClient(conn).Signers(); err == nil {
+				signers = append(signers, keys...)
+			}
+		}
+	}
+	for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		data, err := os.ReadFile(filepath.Join(home, ".ssh", name))
+		if err != nil {
+			continue
+		}
+		// Keys with a passphrase are only usable through the agent
+		if key, err := ssh.ParsePrivateKey(data); err == nil {
+			signers = append(signers, key)
+		}
+	}
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("Keine SSH-Schlüssel gefunden (ssh-agent oder ~/.ssh/id_*)")
+	}
+
+	client, err := ssh.Dial("tcp", t.addr(), &ssh.ClientConfig{
+		User:            t.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signers...)},
+		HostKeyCallback: hostKeys,
+		Timeout:         10 * time.Second,
+	})
+	if err != nil {
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) {
+			if len(keyErr.Want) == 0 {
+				return nil, fmt.Errorf("Unbekannter Host %s: bitte zuerst mit ssh verbinden, um den Schlüssel zu bestätigen", t.Host)
+			}
+			return nil, fmt.Errorf("Der Host-Schlüssel von %s hat sich geändert", t.Host)
+		}
+		return nil, fmt.Errorf("SSH-Verbindung zu %s fehlgeschlagen: %w", t.Host, err)
+	}
+	sc, err := sftp.NewClient(client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("SFTP auf %s nicht verfügbar: %w", t.Host, err)
+	}
+	return &sftpConn{ssh: client, sftp: sc}, nil
+}
+
+// remote runs fn with the client and the remote path of p
+func (a *App) remote(p string, fn func(c *sftp.Client, rpath string) error) error {
+	t, err := parseRemotePath(p)
+	if err != nil {
+		return err
+	}
+	return a.sftp.do(t, func(c *sftp.Client) error {
+		return fn(c, t.Path)
+	})
+}
+
+// readData reads a local or remote file or an archive entry; compressed
+// files are decompressed, encrypted files decrypted
+func (a *App) readData(p string) ([]byte, error) {
+	var data []byte
+	var err error
+	if isArchivePath(p) {
+		data, err = readArchiveEntry(p)
+	} else if data, err = a.readRaw(p); err == nil {
+		data, err = decompress(p, data)
+	}
+	if err != nil || !isEncrypted(data) {
+		return data, err
+	}
+	return a.decrypt(p, data)
+}
+
+// writeData writes a local or remote file or an archive entry; compressed
+// files are compressed and encrypted files encrypted again
+func (a *App) writeData(p string, data []byte) error {
+	if a.encrypted(p) {
+		var err error
+		if data, err = a.encrypt(p, data); err != nil {
+			return err
+		}
+	}
+	if isArchivePath(p) {
+		return a.writeArchiveEntry(p, data)
+	}
+	data, err := compress(p, data)
+	if err != nil {
+		return err
+	}
+	return a.writeRaw(p, data)
+}
+
+func (a *App) readRaw(p string) ([]byte, error) {
+	if !isRemotePath(p) {
+		return os.ReadFile(p)
+	}
+	var data []byte
+	err := a.remote(p, func(c *sftp.Client, rpath string) error {
+		f, err := c.Open(rpath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		data, err = io.ReadAll(f)
+		return err
+	})
+	return data, err
+}
+
+// writeRaw writes a local or remote file; existing remote files keep their mode
+func (a *App) writeRaw(p string, data []byte) error {
+	if !isRemotePath(p) {
+		return os.WriteFile(p, data, 0644)
+	}
+	// Written to a temporary file next to the target and renamed over it,
+	// so a dropped connection does not leave a truncated file
+	return a.remote(p, func(c *sftp.Client, rpath string) error {
+		tmp := path.Join(path.Dir(rpath), fmt.Sprintf(".%s.leoedit-%d", path.Base(rpath), time.Now().UnixNano()))
+		f, err := c.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			if info, serr := c.Stat(rpath); serr == nil {
+				err = c.Chmod(tmp, info.Mode().Perm())
+			}
+		}
+		if err != nil {
+			c.Remove(tmp)
+			return err
+		}
+		if _, ok := c.HasExtension("posix-rename@openssh.com"); ok {
+			err = c.PosixRename(tmp, rpath)
+		} else {
+			err = renameOver(c, tmp, rpath)
+		}
+		if err != nil {
+			// The target is unchanged, the new content stays in tmp
+			return fmt.Errorf("%w (neuer Inhalt in %s)", err, tmp)
+		}
+		return nil
+	})
+}
+
+// renameOver replaces dst with tmp on servers without posix-rename, where
+// a rename onto an existing file fails: dst is moved aside first and only
+// removed once tmp took its place
+func renameOver(c *sftp.Client, tmp, dst string) error {
+	if _, err := c.Lstat(dst); err != nil {
+		return c.Rename(tmp, dst)
+	}
+	aside := tmp + ".alt"
+	if err := c.Rename(dst, aside); err != nil {
+		return err
+	}
+	if err := c.Rename(tmp, dst); err != nil {
+		c.Rename(aside, dst)
+		return err
+	}
+	c.Remove(aside)
+	return nil
+}
+
+func (a *App) statPath(p string) (os.FileInfo, error) {
+	if isArchivePath(p) {
+		return statArchivePath(p)
+	}
+	if !isRemotePath(p) {
+		return os.Stat(p)
+	}
+	var info os.FileInfo
+	err := a.remote(p, func(c *sftp.Client, rpath string) (err error) {
+		info, err = c.Stat(rpath)
+		return err
+	})
+	return info, err
+}
+
+// OpenRemote checks an sftp:// address. Folders are shown in the explorer
+// via "explorer-open" and "" is returned; for files the normalized path is
+// returned to be opened.
+func (a *App) OpenRemote(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if !isRemotePath(address) {
+		address = remotePrefix + address
+	}
+	p, err := cleanRemotePath(address)
+	if err != nil {
+		return "", err
+	}
+	info, err := a.statPath(p)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", p, err)
+	}
+	if info.IsDir() {
+		if a.ctx != nil {
+			runtime.EventsEmit(a.ctx, "explorer-open", p)
+		}
+		return "", nil
+	}
+	return p, nil
+}
+
+// listRemoteDir is ListDir for sftp:// folders; .gitignore is not applied
+func (a *App) listRemoteDir(p string, opts ListDirOptions) ([]DirEntry, error) {
+	var infos []os.FileInfo
+	err := a.remote(p, func(c *sftp.Client, rpath string) (err error) {
+		infos, err = c.ReadDir(rpath)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]DirEntry, 0, len(infos))
+	for _, info := range infos {
+		hidden := strings.HasPrefix(info.Name(), ".")
+		if hidden && !opts.ShowHidden {
+			continue
+		}
+		e := DirEntry{
+			Name:      info.Name(),
+			IsDir:     info.IsDir(),
+			Size:      info.Size(),
+			ModTime:   info.ModTime().Unix(),
+			Mode:      info.Mode().String(),
+			Perm:      uint32(info.Mode().Perm()),
+			IsSymlink: info.Mode()&os.ModeSymlink != 0,
+			Hidden:    hidden,
+			Mime:      "inode/directory",
+		}
+		if !e.IsDir {
+			e.Mime = mimeByName(e.Name)
+		}
+		result = append(result, e)
+	}
+	sortDirEntries(result)
+	return result, nil
+}
@@ -0,0 +1,511 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// IndexedSymbol is one entry of the workspace symbol index (zero-based position)
+type IndexedSymbol struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Container string `json:"container"` // Enclosing type/class, if any
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	Character int    `json:"character"`
+}
+
+const (
+	symbolIndexMaxFiles    = 20000
+	symbolIndexMaxFileSize = 1 << 20
+)
+
+// Directories that never contain interesting sources
+var symbolIndexSkipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"dist":         true,
+	"__pycache__":  true,
+	"target":       true,
+}
+
+// symbolRule extracts symbols with a regex; the first submatch is the name
+type symbolRule struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// symbolRules are the ctags-style rules for languages without a real parser
+var symbolRules = map[string][]symbolRule{
+	".js": {
+		{"function", regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*([A-Za-z_$][\w$]*)`)},
+		{"class", regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?class\s+([A-Za-z_$][\w$]*)`)},
+		{"function", regexp.MustCompile(`^\s*(?:export\s+)?(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*(?:async\s+)?(?:function|\([^)]*\)\s*=>|[A-Za-z_$][\w$]*\s*=>)`)},
+		{"method", regexp.MustCompile(`^\s+(?:static\s+)?(?:async\s+)?(?:get\s+|set\s+)?([A-Za-z_$][\w$]*)\s*\([^)]*\)\s*\{\s*$`)},
+		{"const", regexp.MustCompile(`^(?:export\s+)?const\s+([A-Za-z_$][\w$]*)\s*=`)},
+	},
+	".py": {
+		{"class", regexp.MustCompile(`^\s*class\s+([A-Za-z_]\w*)`)},
+		{"function", regexp.MustCompile(`^\s*(?:async\s+)?def\s+([A-Za-z_]\w*)`)},
+		{"const", regexp.MustCompile(`^([A-Z_][A-Z0-9_]*)\s*(?::[^=]+)?=`)},
+	},
+	".rs": {
+		{"function", regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(?:async\s+)?(?:unsafe\s+)?fn\s+([A-Za-z_]\w*)`)},
+		{"struct", regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?struct\s+([A-Za-z_]\w*)`)},
+		{"type", regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(?:enum|trait|type|union)\s+([A-Za-z_]\w*)`)},
+		{"const", regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(?:const|static)\s+([A-Za-z_]\w*)`)},
+		{"module", regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?mod\s+([A-Za-z_]\w*)`)},
+	},
+	".java": {
+		{"class", regexp.MustCompile(`^\s*(?:(?:public|protected|private|abstract|final|static)\s+)*(?:class|interface|enum|record)\s+([A-Za-z_]\w*)`)},
+		{"method", regexp.MustCompile(`^\s*(?:(?:public|protected|private|abstract|final|static|synchronized)\s+)+[\w<>\[\], ]+\s+([A-Za-z_]\w*)\s*\(`)},
+	},
+	".c": {
+		{"function", regexp.MustCompile(`^[A-Za-z_][\w \t\*]*?\b([A-Za-z_]\w*)\s*\([^;]*$`)},
+		{"struct", regexp.MustCompile(`^\s*(?:typedef\s+)?(?:struct|union|enum)\s+([A-Za-z_]\w*)`)},
+		{"macro", regexp.MustCompile(`^\s*#\s*define\s+([A-Za-z_]\w*)`)},
+	},
+	".sh": {
+		{"function", regexp.MustCompile(`^\s*(?:function\s+)?([A-Za-z_][\w-]*)\s*\(\)\s*\{?`)},
+		{"function", regexp.MustCompile(`^\s*function\s+([A-Za-z_][\w-]*)`)},
+	},
+	".md": {
+		{"heading", regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)},
+	},
+}
+
+// Extensions that share the rules of another language
+var symbolRuleAliases = map[string]string{
+	".mjs": ".js", ".cjs": ".js", ".jsx": ".js", ".ts": ".js", ".tsx": ".js",
+	".pyw": ".py",
+	".h":   ".c", ".cpp": ".c", ".cc": ".c", ".hpp": ".c", ".cxx": ".c",
+	".bash": ".sh", ".zsh": ".sh",
+	".markdown": ".md",
+}
+
+// C keywords that look like function names to the C rule
+var cControlWords = map[string]bool{"if": true, "for": true, "while": true, "switch": true, "return": true, "sizeof": true}
+
+// symbolIndex is a ctags-like index of all symbols below the root directories
+type symbolIndex struct {
+	mu       sync.RWMutex
+	roots    []string
+	skip     func(path string) bool
+	files    map[string][]IndexedSymbol
+	building bool
+	pending  map[string]bool // Files saved during build, indexed again afterwards
+}
+
+func newSymbolIndex() *symbolIndex {
+	return &symbolIndex{files: make(map[string][]IndexedSymbol)}
+}
+
+func symbolRulesFor(path string) ([]symbolRule, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if alias, ok := symbolRuleAliases[ext]; ok {
+		ext = alias
+	}
+	rules, ok := symbolRules[ext]
+	return rules, ok
+}
+
+func isIndexable(path string) bool {
+	if strings.EqualFold(filepath.Ext(path), ".go") {
+		return true
+	}
+	_, ok := symbolRulesFor(path)
+	return ok
+}
+
+// indexFile extracts all symbols of one file
+func indexFile(path string) ([]IndexedSymbol, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > symbolIndexMaxFileSize {
+		return nil, nil
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".go") {
+		nodes, err := goOutline(path, src)
+		if err != nil {
+			return nil, err
+		}
+		var result []IndexedSymbol
+		li := newLineIndex(src)
+		var walk func(nodes []*outlineNode, container string)
+		walk = func(nodes []*outlineNode, container string) {
+			for _, n := range nodes {
+				pos := li.position(n.start)
+				result = append(result, IndexedSymbol{
+					Name:      n.Name,
+					Kind:      n.Kind,
+					Container: container,
+					Path:      path,
+					Line:      pos.Line,
+					Character: pos.Character,
+				})
+				walk(n.children, n.Name)
+			}
+		}
+		walk(nodes, "")
+		return result, nil
+	}
+
+	rules, _ := symbolRulesFor(path)
+	return regexSymbols(path, string(src), rules), nil
+}
+
+func regexSymbols(path string, src string, rules []symbolRule) []IndexedSymbol {
+	var result []IndexedSymbol
+	var container string
+	containerIndent := -1
+	inFence := false
+
+	for lineNo, line := range strings.Split(src, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if inFence || trimmed == "" || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "*") || strings.HasPrefix(trimmed, "/*") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if containerIndent >= 0 && indent <= containerIndent && !strings.HasPrefix(trimmed, "}") {
+			container, containerIndent = "", -1
+		}
+
+		for _, rule := range rules {
+			m := rule.re.FindStringSubmatchIndex(line)
+			if m == nil {
+				continue
+			}
+			name := line[m[2]:m[3]]
+			if rule.kind == "function" && cControlWords[name] {
+				continue
+			}
+			kind := rule.kind
+			if (kind == "function" || kind == "method") && container != "" && indent > containerIndent {
+				kind = "method"
+			} else if kind == "method" {
+				continue
+			}
+			sym := IndexedSymbol{
+				Name:      name,
+				Kind:      kind,
+				Path:      path,
+				Line:      lineNo,
+				Character: len([]rune(line[:m[2]])),
+			}
+			if indent > containerIndent && containerIndent >= 0 {
+				sym.Container = container
+			}
+			result = append(result, sym)
+			if kind == "class" || kind == "struct" {
+				container, containerIndent = name, indent
+			}
+			break
+		}
+	}
+	return result
+}
+
+// build replaces the index with all symbols below roots. skip reports
+// excluded files and directories.
+func (idx *symbolIndex) build(roots []string, skip func(path string) bool) (int, error) {
+	idx.mu.Lock()
+	if idx.building {
+		idx.mu.Unlock()
+		return 0, fmt.Errorf("Symbolindex wird bereits erstellt")
+	}
+	idx.building = true
+	idx.pending = make(map[string]bool)
+	idx.mu.Unlock()
+	done := func() []string {
+		idx.mu.Lock()
+		defer idx.mu.Unlock()
+		idx.building = false
+		var pending []string
+		for p := range idx.pending {
+			pending = append(pending, p)
+		}
+		idx.pending = nil
+		return pending
+	}
+
+	var paths []string
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil // Unreadable entries are skipped
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != root && (strings.HasPrefix(name, ".") || symbolIndexSkipDirs[name] || skip(path)) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Type().IsRegular() && isIndexable(path) && !skip(path) {
+				paths = append(paths, path)
+				if len(paths) >= symbolIndexMaxFiles {
+					return filepath.SkipAll
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			done()
+			return 0, err
+		}
+		if len(paths) >= symbolIndexMaxFiles {
+			break
+		}
+	}
+
+	files := make(map[string][]IndexedSymbol, len(paths))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				syms, err := indexFile(path)
+				if err != nil || len(syms) == 0 {
+					continue
+				}
+				mu.Lock()
+				files[path] = syms
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, p := range paths {
+		jobs <- p
+	}
+	close(jobs)
+	wg.Wait()
+
+	count := 0
+	for _, syms := range files {
+		count += len(syms)
+	}
+
+	idx.mu.Lock()
+	idx.roots = roots
+	idx.skip = skip
+	idx.files = files
+	idx.mu.Unlock()
+	// Saves during the walk went to the old files and would be lost
+	for _, p := range done() {
+		idx.update(p)
+	}
+	return count, nil
+}
+
+// indexed reports whether build would index path: it lies below one of
+// the roots and neither it nor a directory above it is skipped
+func indexed(path string, roots []string, skip func(path string) bool) bool {
+	for _, root := range roots {
+		if !isWithin(path, root) {
+			continue
+		}
+		rel, _ := filepath.Rel(root, path)
+		parts := strings.Split(rel, string(filepath.Separator))
+		dir := root
+		for _, name := range parts[:len(parts)-1] {
+			dir = filepath.Join(dir, name)
+			if strings.HasPrefix(name, ".") || symbolIndexSkipDirs[name] || skip(dir) {
+				return false
+			}
+		}
+		return !skip(path)
+	}
+	return false
+}
+
+// update re-indexes a single file if it lies below an indexed root
+func (idx *symbolIndex) update(path string) {
+	if !isIndexable(path) {
+		return
+	}
+	idx.mu.Lock()
+	if idx.building {
+		idx.pending[path] = true
+	}
+	roots, skip := idx.roots, idx.skip
+	idx.mu.Unlock()
+	if skip == nil || !indexed(path, roots, skip) {
+		return
+	}
+
+	syms, err := indexFile(path)
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if err != nil || len(syms) == 0 {
+		delete(idx.files, path)
+		return
+	}
+	idx.files[path] = syms
+}
+
+// symbolMatchScore ranks a name against a query; 0 means no match
+func symbolMatchScore(name, query string) int {
+	n, q := strings.ToLower(name), strings.ToLower(query)
+	switch {
+	case name == query:
+		return 1000
+	case n == q:
+		return 900
+	case strings.HasPrefix(n, q):
+		return 800 - len(n)
+	case strings.Contains(n, q):
+		return 600 - len(n)
+	}
+	// Fuzzy: all query characters in order
+	qr := []rune(q)
+	i := 0
+	for _, r := range n {
+		if i < len(qr) && qr[i] == r {
+			i++
+		}
+	}
+	if i == len(qr) {
+		return 300 - len(n)
+	}
+	return 0
+}
+
+func (idx *symbolIndex) search(query string, limit int) []IndexedSymbol {
+	type scored struct {
+		sym   IndexedSymbol
+		score int
+	}
+	var matches []scored
+
+	idx.mu.RLock()
+	for _, syms := range idx.files {
+		for _, s := range syms {
+			if score := symbolMatchScore(s.Name, query); score > 0 || query == "" {
+				matches = append(matches, scored{s, score})
+			}
+		}
+	}
+	idx.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		if matches[i].sym.Path != matches[j].sym.Path {
+			return matches[i].sym.Path < matches[j].sym.Path
+		}
+		return matches[i].sym.Line < matches[j].sym.Line
+	})
+
+	if limit <= 0 || limit > len(matches) {
+		limit = len(matches)
+	}
+	result := make([]IndexedSymbol, 0, limit)
+	for _, m := range matches[:limit] {
+		result = append(result, m.sym)
+	}
+	return result
+}
+
+// definitions returns all symbols named exactly name. Symbols in the same
+// directory as fromPath come first, fields and headings last.
+func (idx *symbolIndex) definitions(name, fromPath string) []IndexedSymbol {
+	result := []IndexedSymbol{}
+	idx.mu.RLock()
+	for _, syms := range idx.files {
+		for _, s := range syms {
+			if s.Name == name {
+				result = append(result, s)
+			}
+		}
+	}
+	idx.mu.RUnlock()
+
+	fromDir := filepath.Dir(fromPath)
+	rank := func(s IndexedSymbol) int {
+		r := 0
+		if filepath.Dir(s.Path) != fromDir {
+			r += 2
+		}
+		if s.Kind == "field" || s.Kind == "heading" {
+			r++
+		}
+		return r
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if ri, rj := rank(result[i]), rank(result[j]); ri != rj {
+			return ri < rj
+		}
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+		return result[i].Line < result[j].Line
+	})
+	return result
+}
+
+// IndexWorkspace (re)builds the symbol index for all folders of the open
+// workspace, or for the project containing dir if it lies outside of it.
+// Indexing runs in the background; "symbol-index-ready" is emitted when done.
+func (a *App) IndexWorkspace(dir string) (string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	root := findProjectRoot(dir, []string{"go.mod", "package.json"})
+	roots := []string{root}
+	if ws, wsRoot, ok := a.workspaceFor(dir); ok {
+		root, roots = wsRoot, ws.roots()
+	}
+
+	go func() {
+		count, err := a.symbols.build(roots, a.isExcluded)
+		if a.ctx == nil {
+			return
+		}
+		if err != nil {
+			runtime.EventsEmit(a.ctx, "error", fmt.Sprintf("Fehler beim Indizieren: %v", err))
+			return
+		}
+		runtime.EventsEmit(a.ctx, "symbol-index-ready", map[string]interface{}{
+			"root":  root,
+			"count": count,
+		})
+	}()
+	return root, nil
+}
+
+// WorkspaceSymbols searches the index ("go to symbol in workspace")
+func (a *App) WorkspaceSymbols(query string, limit int) []IndexedSymbol {
+	return a.symbols.search(query, limit)
+}
+
+// FindDefinition looks up symbols by exact name, best candidates first
+func (a *App) FindDefinition(name string, fromPath string) []IndexedSymbol {
+	return a.symbols.definitions(name, fromPath)
+}
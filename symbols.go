@@ -0,0 +1,1084 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// OutlineSymbol is one node of the hierarchical document outline.
+// Ranges are zero-based and use UTF-16 columns like the LSP types.
+type OutlineSymbol struct {
+	Name     string          `json:"name"`
+	Kind     string          `json:"kind"` // function, method, type, struct, interface, field, const, var, class, heading
+	Detail   string          `json:"detail"`
+	Level    int             `json:"level"` // Heading level for Markdown, nesting depth otherwise
+	Range    LSPRange        `json:"range"`
+	Children []OutlineSymbol `json:"children"`
+}
+
+// outlineNode is the mutable form used while building a tree
+type outlineNode struct {
+	OutlineSymbol
+	start, end int // Byte offsets
+	children   []*outlineNode
+}
+
+func (n *outlineNode) add(child *outlineNode) {
+	n.children = append(n.children, child)
+}
+
+// toSymbols converts the node tree into JSON-ready values
+func toSymbols(nodes []*outlineNode, li *lineIndex, depth int) []OutlineSymbol {
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].start < nodes[j].start })
+	result := make([]OutlineSymbol, 0, len(nodes))
+	for _, n := range nodes {
+		s := n.OutlineSymbol
+		if s.Kind != "heading" {
+			s.Level = depth
+		}
+		s.Range = LSPRange{Start: li.position(n.start), End: li.position(n.end)}
+		s.Children = toSymbols(n.children, li, depth+1)
+		result = append(result, s)
+	}
+	return result
+}
+
+// lineIndex converts byte offsets into line/column positions
+type lineIndex struct {
+	src    []byte
+	starts []int
+}
+
+func newLineIndex(src []byte) *lineIndex {
+	starts := []int{0}
+	for i, b := range src {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return &lineIndex{src: src, starts: starts}
+}
+
+// line returns the zero-based line containing offset
+func (li *lineIndex) line(offset int) int {
+	return sort.Search(len(li.starts), func(i int) bool { return li.starts[i] > offset }) - 1
+}
+
+// position returns the zero-based line and UTF-16 column of offset
+func (li *lineIndex) position(offset int) LSPPosition {
+	if offset > len(li.src) {
+		offset = len(li.src)
+	}
+	line := li.line(offset)
+	col := 0
+	for _, r := range string(li.src[li.starts[line]:offset]) {
+		if r >= 0x10000 {
+			col += 2
+		} else {
+			col++
+		}
+	}
+	return LSPPosition{Line: line, Character: col}
+}
+
+// lineEnd returns the offset of the end of the given line (without newline)
+func (li *lineIndex) lineEnd(line int) int {
+	if line+1 < len(li.starts) {
+		end := li.starts[line+1] - 1
+		if end > 0 && li.src[end-1] == '\r' {
+			end--
+		}
+		return end
+	}
+	return len(li.src)
+}
+
+// extractOutline picks the extractor by file extension
+func extractOutline(path string, src []byte) ([]OutlineSymbol, error) {
+	li := newLineIndex(src)
+	var nodes []*outlineNode
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		nodes, err = goOutline(path, src)
+	case ".md", ".markdown":
+		nodes = markdownOutline(src, li)
+	case ".js", ".mjs", ".cjs", ".jsx", ".ts", ".tsx":
+		nodes = jsOutline(src, li)
+	case ".py", ".pyw":
+		nodes = pythonOutline(src, li)
+	default:
+		return nil, fmt.Errorf("Keine Symbol-Extraktion für %s verfügbar", filepath.Base(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toSymbols(nodes, li, 0), nil
+}
+
+// ---------------------------------------------------------------------------
+// Go
+// ---------------------------------------------------------------------------
+
+func goOutline(path string, src []byte) ([]*outlineNode, error) {
+	fset := token.NewFileSet()
+	// On syntax errors ParseFile still returns the declarations it could read
+	file, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+	if file == nil {
+		return nil, fmt.Errorf("Fehler beim Parsen: %w", err)
+	}
+	off := func(p token.Pos) int { return fset.Position(p).Offset }
+
+	var roots []*outlineNode
+	typeNodes := map[string]*outlineNode{}
+	var methods []*outlineNode
+	var receivers []string
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			n := &outlineNode{start: off(d.Pos()), end: off(d.End())}
+			n.Name = d.Name.Name
+			n.Kind = "function"
+			n.Detail = strings.TrimPrefix(types.ExprString(d.Type), "func")
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				recv := types.ExprString(d.Recv.List[0].Type)
+				n.Kind = "method"
+				n.Detail = "(" + recv + ")" + n.Detail
+				methods = append(methods, n)
+				receivers = append(receivers, receiverTypeName(d.Recv.List[0].Type))
+				continue
+			}
+			roots = append(roots, n)
+
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					start := off(s.Pos())
+					if len(d.Specs) == 1 && !d.Lparen.IsValid() {
+						start = off(d.Pos())
+					}
+					n := &outlineNode{start: start, end: off(s.End())}
+					n.Name = s.Name.Name
+					n.Kind = "type"
+					n.Detail = types.ExprString(s.Type)
+					switch t := s.Type.(type) {
+					case *ast.StructType:
+						n.Kind = "struct"
+						n.Detail = ""
+						for _, f := range t.Fields.List {
+							goFieldNodes(n, f, "field", off)
+						}
+					case *ast.InterfaceType:
+						n.Kind = "interface"
+						n.Detail = ""
+						for _, f := range t.Methods.List {
+							kind := "method"
+							if _, ok := f.Type.(*ast.FuncType); !ok {
+								kind = "type"
+							}
+							goFieldNodes(n, f, kind, off)
+						}
+					}
+					typeNodes[n.Name] = n
+					roots = append(roots, n)
+
+				case *ast.ValueSpec:
+					kind := "var"
+					if d.Tok == token.CONST {
+						kind = "const"
+					}
+					detail := ""
+					if s.Type != nil {
+						detail = types.ExprString(s.Type)
+					}
+					for _, name := range s.Names {
+						if name.Name == "_" {
+							continue
+						}
+						start, end := off(name.Pos()), off(s.End())
+						if len(d.Specs) == 1 && !d.Lparen.IsValid() {
+							start, end = off(d.Pos()), off(d.End())
+						}
+						n := &outlineNode{start: start, end: end}
+						n.Name = name.Name
+						n.Kind = kind
+						n.Detail = detail
+						roots = append(roots, n)
+					}
+				}
+			}
+		}
+	}
+
+	// Methods are shown below their receiver type if it is declared in this file
+	for i, m := range methods {
+		if t, ok := typeNodes[receivers[i]]; ok {
+			t.add(m)
+		} else {
+			roots = append(roots, m)
+		}
+	}
+	return roots, nil
+}
+
+func goFieldNodes(parent *outlineNode, f *ast.Field, kind string, off func(token.Pos) int) {
+	detail := types.ExprString(f.Type)
+	if len(f.Names) == 0 {
+		// Embedded field or interface
+		n := &outlineNode{start: off(f.Pos()), end: off(f.End())}
+		n.Name = detail
+		n.Kind = kind
+		if kind == "field" {
+			n.Detail = "embedded"
+		}
+		parent.add(n)
+		return
+	}
+	if kind == "method" {
+		detail = strings.TrimPrefix(detail, "func")
+	}
+	for _, name := range f.Names {
+		n := &outlineNode{start: off(name.Pos()), end: off(f.End())}
+		n.Name = name.Name
+		n.Kind = kind
+		n.Detail = detail
+		parent.add(n)
+	}
+}
+
+// receiverTypeName strips pointers and type parameters from a receiver type
+func receiverTypeName(expr ast.Expr) string {
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Markdown
+// ---------------------------------------------------------------------------
+
+func markdownOutline(src []byte, li *lineIndex) []*outlineNode {
+	lines := strings.Split(string(src), "\n")
+	type heading struct {
+		level int
+		text  string
+		line  int
+	}
+	var headings []heading
+
+	fence := ""
+	start := 0
+	// YAML front matter
+	if len(lines) > 0 && strings.TrimRight(lines[0], "\r") == "---" {
+		for i := 1; i < len(lines); i++ {
+			if l := strings.TrimRight(lines[i], "\r"); l == "---" || l == "..." {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	prevBlank := true
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+
+		if fence != "" {
+			if indent < 4 && strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		// Lines of only whitespace are blank
+		if strings.TrimSpace(line) == "" {
+			prevBlank = true
+			continue
+		}
+		if indent < 4 && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")) {
+			ch := trimmed[:1]
+			n := len(trimmed) - len(strings.TrimLeft(trimmed, ch))
+			fence = strings.Repeat(ch, n)
+			prevBlank = false
+			continue
+		}
+		// Indented code block
+		if (indent >= 4 || strings.HasPrefix(line, "\t")) && prevBlank {
+			continue
+		}
+
+		if indent < 4 && strings.HasPrefix(trimmed, "#") {
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			rest := trimmed[level:]
+			if level <= 6 && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+				text := strings.TrimSpace(rest)
+				// Optional closing sequence
+				if t := strings.TrimRight(text, "#"); t != text && (t == "" || strings.HasSuffix(t, " ")) {
+					text = strings.TrimSpace(t)
+				}
+				headings = append(headings, heading{level: level, text: text, line: i})
+				prevBlank = false
+				continue
+			}
+		}
+
+		// Setext heading: underline below a paragraph line
+		if under := strings.TrimSpace(trimmed); i > start && !prevBlank && indent < 4 && under != "" {
+			if strings.Trim(under, "=") == "" || strings.Trim(under, "-") == "" {
+				prev := strings.TrimSpace(strings.TrimRight(lines[i-1], "\r"))
+				if prev != "" && !strings.HasPrefix(prev, "#") && !strings.HasPrefix(prev, "-") && !strings.HasPrefix(prev, ">") {
+					level := 1
+					if under[0] == '-' {
+						level = 2
+					}
+					headings = append(headings, heading{level: level, text: prev, line: i - 1})
+					prevBlank = false
+					continue
+				}
+			}
+		}
+
+		prevBlank = false
+	}
+
+	var roots []*outlineNode
+	var stack []*outlineNode
+	for idx, h := range headings {
+		// A section ends before the next heading of the same or a higher level
+		endLine := len(li.starts) - 1
+		for _, next := range headings[idx+1:] {
+			if next.level <= h.level {
+				endLine = next.line - 1
+				break
+			}
+		}
+		n := &outlineNode{start: li.starts[h.line], end: li.lineEnd(endLine)}
+		n.Name = h.text
+		n.Kind = "heading"
+		n.Level = h.level
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= h.level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, n)
+		} else {
+			stack[len(stack)-1].add(n)
+		}
+		stack = append(stack, n)
+	}
+	return roots
+}
+
+// ---------------------------------------------------------------------------
+// Tokenizer shared by the JavaScript and Python extractors
+// ---------------------------------------------------------------------------
+
+type codeToken struct {
+	kind  byte // 'i' identifier/keyword, 'p' punctuation, 's' string, 'n' number, 'r' regex
+	text  string
+	start int
+	end   int
+	bol   bool // First token of a (logical) line
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
+
+// jsRegexAllowed reports whether a '/' after prev starts a regex literal
+func jsRegexAllowed(prev *codeToken) bool {
+	if prev == nil {
+		return true
+	}
+	switch prev.kind {
+	case 'i':
+		switch prev.text {
+		case "return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await":
+			return true
+		}
+		return false
+	case 'p':
+		return prev.text != ")" && prev.text != "]" && prev.text != "}"
+	}
+	return false
+}
+
+// tokenizeJS splits JavaScript/TypeScript into tokens, dropping comments and
+// keeping string, template and regex literals as single tokens
+func tokenizeJS(src []byte) []codeToken {
+	var toks []codeToken
+	// Brace depths at which a template literal continues after "}"
+	var templateStack []int
+	depth := 0
+	i := 0
+	bol := true
+
+	emit := func(kind byte, start, end int) {
+		toks = append(toks, codeToken{kind: kind, text: string(src[start:end]), start: start, end: end, bol: bol})
+		bol = false
+	}
+	prev := func() *codeToken {
+		if len(toks) == 0 {
+			return nil
+		}
+		return &toks[len(toks)-1]
+	}
+	// scanTemplate scans template characters from i until "`" or "${"
+	scanTemplate := func(start int) {
+		for i < len(src) {
+			switch {
+			case src[i] == '\\':
+				i += 2
+			case src[i] == '`':
+				i++
+				emit('s', start, i)
+				return
+			case src[i] == '$' && i+1 < len(src) && src[i+1] == '{':
+				i += 2
+				emit('s', start, i)
+				depth++
+				templateStack = append(templateStack, depth)
+				return
+			default:
+				i++
+			}
+		}
+		emit('s', start, len(src))
+	}
+
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			bol = true
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 4
+			}
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			for i < len(src) && src[i] != c && src[i] != '\n' {
+				if src[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i = min(i+1, len(src))
+			emit('s', start, i)
+		case c == '`':
+			start := i
+			i++
+			scanTemplate(start)
+		case c == '/' && jsRegexAllowed(prev()):
+			start := i
+			i++
+			inClass := false
+			for i < len(src) && src[i] != '\n' {
+				if src[i] == '\\' {
+					i += 2
+					continue
+				}
+				if src[i] == '[' {
+					inClass = true
+				} else if src[i] == ']' {
+					inClass = false
+				} else if src[i] == '/' && !inClass {
+					break
+				}
+				i++
+			}
+			i = min(i+1, len(src))
+			for i < len(src) && isIdentPart(rune(src[i])) {
+				i++
+			}
+			emit('r', start, i)
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (isIdentPart(rune(src[i])) || src[i] == '.') {
+				i++
+			}
+			emit('n', start, i)
+		default:
+			r, size := utf8.DecodeRune(src[i:])
+			if isIdentStart(r) || r == '#' {
+				start := i
+				i += size
+				for i < len(src) {
+					r, size = utf8.DecodeRune(src[i:])
+					if !isIdentPart(r) {
+						break
+					}
+					i += size
+				}
+				emit('i', start, i)
+				continue
+			}
+			start := i
+			switch {
+			case c == '=' && i+1 < len(src) && src[i+1] == '>':
+				i += 2
+			case c == '.' && i+2 < len(src) && src[i+1] == '.' && src[i+2] == '.':
+				i += 3
+			default:
+				i += size
+			}
+			if c == '{' {
+				depth++
+			} else if c == '}' {
+				if n := len(templateStack); n > 0 && templateStack[n-1] == depth {
+					// End of a ${...} expression, the template continues
+					templateStack = templateStack[:n-1]
+					depth--
+					scanTemplate(start)
+					continue
+				}
+				depth--
+			}
+			emit('p', start, i)
+		}
+	}
+	return toks
+}
+
+// tokenizePython splits Python source into tokens. Newlines inside brackets
+// or after a backslash continue the logical line.
+func tokenizePython(src []byte) []codeToken {
+	var toks []codeToken
+	parens := 0
+	bol := true
+	i := 0
+
+	emit := func(kind byte, start, end int) {
+		toks = append(toks, codeToken{kind: kind, text: string(src[start:end]), start: start, end: end, bol: bol})
+		bol = false
+	}
+
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			if parens == 0 {
+				bol = true
+			}
+			i++
+		case c == '\\' && i+1 < len(src) && (src[i+1] == '\n' || src[i+1] == '\r'):
+			i += 2
+			if i < len(src) && src[i-1] == '\r' && src[i] == '\n' {
+				i++
+			}
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			start := i
+			i = scanPythonString(src, i)
+			emit('s', start, i)
+		default:
+			r, size := utf8.DecodeRune(src[i:])
+			if isIdentStart(r) && r != '$' {
+				start := i
+				i += size
+				for i < len(src) {
+					r, size = utf8.DecodeRune(src[i:])
+					if !isIdentPart(r) || r == '$' {
+						break
+					}
+					i += size
+				}
+				// String prefixes like r"", b'', f""", rb''
+				if i < len(src) && (src[i] == '"' || src[i] == '\'') && i-start <= 2 &&
+					strings.Trim(strings.ToLower(string(src[start:i])), "rbuf") == "" {
+					i = scanPythonString(src, i)
+					emit('s', start, i)
+					continue
+				}
+				emit('i', start, i)
+				continue
+			}
+			if c >= '0' && c <= '9' {
+				start := i
+				for i < len(src) && (isIdentPart(rune(src[i])) || src[i] == '.') {
+					i++
+				}
+				emit('n', start, i)
+				continue
+			}
+			switch c {
+			case '(', '[', '{':
+				parens++
+			case ')', ']', '}':
+				if parens > 0 {
+					parens--
+				}
+			}
+			emit('p', i, i+size)
+			i += size
+		}
+	}
+	return toks
+}
+
+// scanPythonString returns the offset after the string literal starting at i
+func scanPythonString(src []byte, i int) int {
+	q := src[i]
+	if i+2 < len(src) && src[i+1] == q && src[i+2] == q {
+		end := bytes.Index(src[i+3:], []byte{q, q, q})
+		for end >= 0 && src[i+3+end-1] == '\\' {
+			next := bytes.Index(src[i+3+end+1:], []byte{q, q, q})
+			if next < 0 {
+				end = -1
+				break
+			}
+			end += next + 1
+		}
+		if end < 0 {
+			return len(src)
+		}
+		return i + 3 + end + 3
+	}
+	i++
+	for i < len(src) && src[i] != q && src[i] != '\n' {
+		if src[i] == '\\' {
+			i++
+		}
+		i++
+	}
+	return min(i+1, len(src))
+}
+
+// ---------------------------------------------------------------------------
+// JavaScript / TypeScript
+// ---------------------------------------------------------------------------
+
+func jsOutline(src []byte, li *lineIndex) []*outlineNode {
+	toks := tokenizeJS(src)
+
+	type scope struct {
+		sym     *outlineNode
+		isClass bool
+	}
+	var roots []*outlineNode
+	var scopes []scope
+	// Opening braces that start the body of a symbol
+	bodies := map[int]*outlineNode{}
+	classBodies := map[int]bool{}
+
+	is := func(i int, kind byte, text string) bool {
+		return i >= 0 && i < len(toks) && toks[i].kind == kind && (text == "" || toks[i].text == text)
+	}
+	parent := func() *outlineNode {
+		for j := len(scopes) - 1; j >= 0; j-- {
+			if scopes[j].sym != nil {
+				return scopes[j].sym
+			}
+		}
+		return nil
+	}
+	attach := func(n *outlineNode) {
+		if p := parent(); p != nil {
+			p.add(n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+	// matching returns the index of the bracket closing the one at i
+	matching := func(i int) int {
+		openText, closeText := toks[i].text, map[string]string{"(": ")", "[": "]", "{": "}", "<": ">"}[toks[i].text]
+		level := 0
+		for j := i; j < len(toks); j++ {
+			if toks[j].kind != 'p' {
+				continue
+			}
+			switch toks[j].text {
+			case openText:
+				level++
+			case closeText:
+				level--
+				if level == 0 {
+					return j
+				}
+			}
+		}
+		return len(toks) - 1
+	}
+	// nextBrace finds the "{" after a parameter list, skipping TypeScript return types
+	nextBrace := func(i int) int {
+		for j := i; j < len(toks); j++ {
+			if is(j, 'p', "{") {
+				return j
+			}
+			if is(j, 'p', ";") || is(j, 'p', "}") {
+				return -1
+			}
+		}
+		return -1
+	}
+	// statementEnd returns the last token index of an expression statement starting at i
+	statementEnd := func(i int) int {
+		level := 0
+		for j := i; j < len(toks); j++ {
+			if toks[j].kind == 'p' {
+				switch toks[j].text {
+				case "(", "[", "{":
+					level++
+				case ")", "]", "}":
+					level--
+					if level < 0 {
+						return j - 1
+					}
+				case ";", ",":
+					if level == 0 {
+						return j - 1
+					}
+				}
+			}
+			if level == 0 && j+1 < len(toks) && toks[j+1].bol && li.line(toks[j+1].start) > li.line(toks[j].end) {
+				next := toks[j+1]
+				if next.kind != 'p' || (next.text != "." && next.text != "?" && next.text != ":" && next.text != "+" && next.text != "-") {
+					return j
+				}
+			}
+		}
+		return len(toks) - 1
+	}
+
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		inClass := len(scopes) > 0 && scopes[len(scopes)-1].isClass
+
+		switch {
+		case t.kind == 'p' && t.text == "{":
+			scopes = append(scopes, scope{sym: bodies[i], isClass: classBodies[i]})
+			continue
+		case t.kind == 'p' && t.text == "}":
+			if len(scopes) > 0 {
+				if s := scopes[len(scopes)-1]; s.sym != nil {
+					s.sym.end = t.end
+				}
+				scopes = scopes[:len(scopes)-1]
+			}
+			continue
+		}
+		if t.kind != 'i' || is(i-1, 'p', ".") {
+			continue
+		}
+
+		switch {
+		case (t.text == "class" || t.text == "interface") && is(i+1, 'i', ""):
+			n := &outlineNode{start: t.start, end: toks[i+1].end}
+			n.Name = toks[i+1].text
+			n.Kind = "class"
+			if t.text == "interface" {
+				n.Kind = "interface"
+			}
+			attach(n)
+			if b := nextBrace(i + 2); b >= 0 {
+				bodies[b] = n
+				classBodies[b] = true
+			}
+			i++
+
+		case t.text == "function":
+			j := i + 1
+			if is(j, 'p', "*") {
+				j++
+			}
+			if !is(j, 'i', "") || !is(j+1, 'p', "(") && !is(j+1, 'p', "<") {
+				continue
+			}
+			n := &outlineNode{start: t.start, end: toks[j].end}
+			if is(i-1, 'i', "async") {
+				n.start = toks[i-1].start
+			}
+			n.Name = toks[j].text
+			n.Kind = "function"
+			paren := j + 1
+			if is(paren, 'p', "<") {
+				paren = matching(paren) + 1
+			}
+			if is(paren, 'p', "(") {
+				rparen := matching(paren)
+				n.Detail = jsSignature(src, toks[paren].start, toks[rparen].end)
+				if b := nextBrace(rparen + 1); b >= 0 {
+					bodies[b] = n
+				}
+			}
+			attach(n)
+			i = j
+
+		case (t.text == "const" || t.text == "let" || t.text == "var") && is(i+1, 'i', "") && !inClass:
+			name := toks[i+1]
+			j := i + 2
+			// TypeScript type annotation
+			if is(j, 'p', ":") {
+				for j < len(toks) && !is(j, 'p', "=") && !is(j, 'p', ";") && !toks[j].bol {
+					j++
+				}
+			}
+			if !is(j, 'p', "=") {
+				continue
+			}
+			j++
+			if is(j, 'i', "async") {
+				j++
+			}
+			n := &outlineNode{start: t.start, end: name.end}
+			n.Name = name.text
+			switch {
+			case is(j, 'i', "function"):
+				n.Kind = "function"
+				k := j + 1
+				for k < len(toks) && !is(k, 'p', "(") {
+					k++
+				}
+				if k < len(toks) {
+					rparen := matching(k)
+					n.Detail = jsSignature(src, toks[k].start, toks[rparen].end)
+					if b := nextBrace(rparen + 1); b >= 0 {
+						bodies[b] = n
+					}
+					i = rparen
+				}
+			case is(j, 'p', "(") && is(matching(j)+1, 'p', "=>"), is(j, 'i', "") && is(j+1, 'p', "=>"):
+				n.Kind = "function"
+				arrow := j + 1
+				if is(j, 'p', "(") {
+					arrow = matching(j) + 1
+					n.Detail = jsSignature(src, toks[j].start, toks[arrow-1].end)
+				} else {
+					n.Detail = "(" + toks[j].text + ")"
+				}
+				if is(arrow+1, 'p', "{") {
+					bodies[arrow+1] = n
+					i = arrow
+				} else {
+					end := statementEnd(arrow + 1)
+					n.end = toks[end].end
+					i = end
+				}
+			default:
+				if len(scopes) > 0 {
+					continue
+				}
+				n.Kind = "var"
+				if t.text == "const" {
+					n.Kind = "const"
+				}
+				n.end = toks[statementEnd(j)].end
+			}
+			attach(n)
+
+		case inClass && (is(i+1, 'p', "(") || is(i+1, 'p', "<")):
+			if t.text == "if" || t.text == "for" || t.text == "while" || t.text == "switch" || t.text == "catch" {
+				continue
+			}
+			// Walk back over modifiers to find the start of the member
+			start := i
+			for start > 0 && toks[start-1].kind == 'i' && !toks[start].bol && jsModifier(toks[start-1].text) {
+				start--
+			}
+			if start > 0 && !is(start-1, 'p', "{") && !is(start-1, 'p', "}") && !is(start-1, 'p', ";") && !is(start-1, 'p', "*") {
+				continue
+			}
+			paren := i + 1
+			if is(paren, 'p', "<") {
+				paren = matching(paren) + 1
+			}
+			if !is(paren, 'p', "(") {
+				continue
+			}
+			rparen := matching(paren)
+			b := nextBrace(rparen + 1)
+			if b < 0 {
+				continue
+			}
+			n := &outlineNode{start: toks[start].start, end: t.end}
+			n.Name = t.text
+			n.Kind = "method"
+			n.Detail = jsSignature(src, toks[paren].start, toks[rparen].end)
+			bodies[b] = n
+			attach(n)
+			i = rparen
+		}
+	}
+	return roots
+}
+
+func jsModifier(s string) bool {
+	switch s {
+	case "static", "async", "get", "set", "public", "private", "protected", "readonly", "override", "abstract":
+		return true
+	}
+	return false
+}
+
+// jsSignature returns the parameter list with whitespace collapsed
+func jsSignature(src []byte, start, end int) string {
+	s := strings.Join(strings.Fields(string(src[start:end])), " ")
+	if utf8.RuneCountInString(s) > 80 {
+		s = string([]rune(s)[:77]) + "..."
+	}
+	return s
+}
+
+// ---------------------------------------------------------------------------
+// Python
+// ---------------------------------------------------------------------------
+
+func pythonOutline(src []byte, li *lineIndex) []*outlineNode {
+	toks := tokenizePython(src)
+
+	type scope struct {
+		sym    *outlineNode
+		indent int
+	}
+	var roots []*outlineNode
+	var scopes []scope
+	lastEnd := 0
+
+	indentOf := func(t codeToken) int {
+		lineStart := li.starts[li.line(t.start)]
+		return t.start - lineStart
+	}
+
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		if t.bol {
+			indent := indentOf(t)
+			for len(scopes) > 0 && scopes[len(scopes)-1].indent >= indent {
+				scopes[len(scopes)-1].sym.end = lastEnd
+				scopes = scopes[:len(scopes)-1]
+			}
+
+			// Decorators belong to the following definition
+			start := t.start
+			j := i
+			for j < len(toks) && toks[j].kind == 'p' && toks[j].text == "@" {
+				k := j + 1
+				for k < len(toks) && !toks[k].bol {
+					k++
+				}
+				j = k
+			}
+			def := j
+			if def < len(toks) && toks[def].text == "async" && def+1 < len(toks) && toks[def+1].text == "def" {
+				def++
+			}
+
+			if def+1 < len(toks) && toks[def].kind == 'i' && (toks[def].text == "def" || toks[def].text == "class") && toks[def+1].kind == 'i' {
+				n := &outlineNode{start: start, end: toks[def+1].end}
+				n.Name = toks[def+1].text
+				n.Kind = "function"
+				if toks[def].text == "class" {
+					n.Kind = "class"
+				} else if len(scopes) > 0 && scopes[len(scopes)-1].sym.Kind == "class" {
+					n.Kind = "method"
+				}
+				if k := def + 2; k < len(toks) && toks[k].text == "(" {
+					level := 0
+					for m := k; m < len(toks); m++ {
+						if toks[m].text == "(" {
+							level++
+						} else if toks[m].text == ")" {
+							level--
+							if level == 0 {
+								n.Detail = jsSignature(src, toks[k].start, toks[m].end)
+								break
+							}
+						}
+					}
+				}
+				if len(scopes) > 0 {
+					scopes[len(scopes)-1].sym.add(n)
+				} else {
+					roots = append(roots, n)
+				}
+				scopes = append(scopes, scope{sym: n, indent: indent})
+				// Continue after the name so the "def"/"class" line is not seen twice
+				i = def + 1
+				lastEnd = toks[i].end
+				continue
+			} else if indent == 0 && len(scopes) == 0 && t.kind == 'i' && i+1 < len(toks) &&
+				(toks[i+1].text == "=" || toks[i+1].text == ":") && !isPythonKeyword(t.text) {
+				// Module level assignment
+				n := &outlineNode{start: t.start, end: t.end}
+				n.Name = t.text
+				n.Kind = "var"
+				if strings.ToUpper(t.text) == t.text {
+					n.Kind = "const"
+				}
+				k := i + 1
+				for k+1 < len(toks) && !toks[k+1].bol {
+					k++
+				}
+				n.end = toks[k].end
+				roots = append(roots, n)
+			}
+		}
+		lastEnd = t.end
+	}
+	for _, s := range scopes {
+		s.sym.end = lastEnd
+	}
+	return roots
+}
+
+func isPythonKeyword(s string) bool {
+	switch s {
+	case "if", "elif", "else", "for", "while", "with", "try", "except", "finally", "return",
+		"import", "from", "pass", "break", "continue", "raise", "global", "nonlocal", "del",
+		"assert", "lambda", "yield", "match", "case", "print":
+		return true
+	}
+	return false
+}
+
+// GetOutline returns the symbol outline of a buffer. The content is passed in
+// so that unsaved changes are reflected.
+func (a *App) GetOutline(path string, content string) ([]OutlineSymbol, error) {
+	return extractOutline(path, []byte(content))
+}
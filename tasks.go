@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// TaskDefinition is one entry of .leoedit/tasks.json. Without Args, Command
+// is run through the shell ("go build ./... && go vet ./..."). Cwd is
+// relative to the workspace root; Pattern is a problem matcher like the
+// ones of the linters.
+type TaskDefinition struct {
+	Name    string            `json:"name"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args,omitempty"`
+	Cwd     string            `json:"cwd,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Pattern string            `json:"pattern,omitempty"`
+}
+
+type tasksFile struct {
+	Tasks []TaskDefinition `json:"tasks"`
+}
+
+// TaskList is returned to the frontend together with the workspace it belongs to
+type TaskList struct {
+	Root  string           `json:"root"`
+	File  string           `json:"file"`
+	Tasks []TaskDefinition `json:"tasks"`
+}
+
+const tasksFileName = ".leoedit/tasks.json"
+
+// loadTasks reads the task definitions of the project containing dir and
+// adds the tasks of the open workspace. Project tasks win on equal names.
+func (a *App) loadTasks(dir string) (TaskList, error) {
+	list, err := loadProjectTasks(dir)
+	if err != nil {
+		return list, err
+	}
+	ws, root, ok := a.workspaceFor(dir)
+	if !ok {
+		return list, nil
+	}
+
+	names := make(map[string]bool, len(list.Tasks))
+	for _, t := range list.Tasks {
+		names[t.Name] = true
+	}
+	for _, t := range ws.Settings.Tasks {
+		if t.Command == "" {
+			continue
+		}
+		if t.Name == "" {
+			t.Name = commandLine(t.Command, t.Args)
+		}
+		if names[t.Name] {
+			continue
+		}
+		// Relative to the workspace folder containing dir
+		if t.Cwd == "" {
+			t.Cwd = root
+		} else if !filepath.IsAbs(t.Cwd) {
+			t.Cwd = filepath.Join(root, t.Cwd)
+		}
+		list.Tasks = append(list.Tasks, t)
+	}
+	return list, nil
+}
+
+// loadProjectTasks reads .leoedit/tasks.json of the project containing dir
+func loadProjectTasks(dir string) (TaskList, error) {
+	root := findProjectRoot(dir, []string{".leoedit"})
+	list := TaskList{Root: root, File: filepath.Join(root, filepath.FromSlash(tasksFileName)), Tasks: []TaskDefinition{}}
+
+	data, err := os.ReadFile(list.File)
+	if os.IsNotExist(err) {
+		return list, nil
+	}
+	if err != nil {
+		return list, fmt.Errorf("Fehler beim Lesen von %s: %w", tasksFileName, err)
+	}
+
+	var f tasksFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return list, fmt.Errorf("Fehler in %s: %w", tasksFileName, err)
+	}
+	for _, t := range f.Tasks {
+		if t.Command == "" {
+			continue
+		}
+		if t.Name == "" {
+			t.Name = commandLine(t.Command, t.Args)
+		}
+		list.Tasks = append(list.Tasks, t)
+	}
+	return list, nil
+}
+
+// shellCommand wraps a command line for the platform shell
+func shellCommand(line string) (string, []string) {
+	if runtime.GOOS == "windows" {
+		return "cmd.exe", []string{"/C", line}
+	}
+	return "/bin/sh", []string{"-c", line}
+}
+
+// envList turns an environment map into KEY=value entries in a stable order
+func envList(env map[string]string) []string {
+	list := make([]string, 0, len(env))
+	for k, v := range env {
+		list = append(list, k+"="+os.ExpandEnv(v))
+	}
+	sort.Strings(list)
+	return list
+}
+
+// GetTasks returns the tasks of the workspace containing dir
+func (a *App) GetTasks(dir string) (TaskList, error) {
+	return a.loadTasks(dir)
+}
+
+// RunTask starts the named task of the workspace containing dir. Output is
+// streamed as "process-output" events, the end as "process-exit".
+func (a *App) RunTask(dir string, name string) (ProcessInfo, error) {
+	list, err := a.loadTasks(dir)
+	if err != nil {
+		return ProcessInfo{}, err
+	}
+
+	for _, t := range list.Tasks {
+		if t.Name != name {
+			continue
+		}
+
+		command, args := t.Command, t.Args
+		if len(args) == 0 && strings.ContainsAny(command, " \t|&;<>") {
+			command, args = shellCommand(t.Command)
+		}
+		cwd := list.Root
+		if t.Cwd != "" {
+			cwd = t.Cwd
+			if !filepath.IsAbs(cwd) {
+				cwd = filepath.Join(list.Root, cwd)
+			}
+		}
+
+		info, err := a.processes.start(processSpec{
+			Name:    t.Name,
+			Command: command,
+			Args:    args,
+			Dir:     cwd,
+			Env:     envList(t.Env),
+			Pattern: t.Pattern,
+		})
+		if err != nil {
+			return ProcessInfo{}, fmt.Errorf("Fehler beim Starten von %s: %w", t.Name, err)
+		}
+		return info, nil
+	}
+	return ProcessInfo{}, fmt.Errorf("Task %s nicht gefunden", name)
+}
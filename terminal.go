@@ -0,0 +1,254 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/creack/pty"
+	wruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// terminalSession is one shell running in a pseudo-terminal. Where no PTY is
+// available (Windows) the shell runs on plain pipes and cannot be resized.
+type terminalSession struct {
+	id    string
+	cmd   *exec.Cmd
+	pty   *os.File       // nil when running on pipes
+	stdin io.WriteCloser // pty or stdin pipe
+	done  chan struct{}
+}
+
+type terminalManager struct {
+	app      *App
+	mu       sync.Mutex
+	sessions map[string]*terminalSession
+	nextID   int
+}
+
+func newTerminalManager(app *App) *terminalManager {
+	return &terminalManager{app: app, sessions: make(map[string]*terminalSession)}
+}
+
+// userShell returns the login shell of the user
+func userShell() (string, []string) {
+	if runtime.GOOS == "windows" {
+		if ps, err := exec.LookPath("powershell.exe"); err == nil {
+			return ps, []string{"-NoLogo"}
+		}
+		if comspec := os.Getenv("COMSPEC"); comspec != "" {
+			return comspec, nil
+		}
+		return "cmd.exe", nil
+	}
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell, []string{"-l"}
+	}
+	return "/bin/sh", nil
+}
+
+// terminalDir picks the start directory: the requested one, the first
+// workspace folder, the last used directory or the home directory
+func (a *App) terminalDir(dir string) string {
+	candidates := []string{dir}
+	if ws := a.workspace(); ws != nil {
+		candidates = append(candidates, ws.Folders[0].Path)
+	}
+	for _, d := range append(candidates, a.Config.LastDirectory) {
+		if d == "" {
+			continue
+		}
+		if info, err := os.Stat(d); err == nil && info.IsDir() {
+			return d
+		}
+	}
+	home, _ := os.UserHomeDir()
+	return home
+}
+
+func (m *terminalManager) start(dir string, cols, rows int) (*terminalSession, error) {
+	shell, args := userShell()
+	cmd := exec.Command(shell, args...)
+	cmd.Dir = m.app.terminalDir(dir)
+	cmd.Env = append(os.Environ(), "TERM=xterm-256color", "COLORTERM=truecolor")
+
+	s := &terminalSession{cmd: cmd, done: make(chan struct{})}
+	var output io.Reader
+
+	f, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)})
+	switch {
+	case err == nil:
+		s.pty = f
+		s.stdin = f
+		output = f
+	case errors.Is(err, pty.ErrUnsupported):
+		cmd = exec.Command(shell, args...)
+		cmd.Dir = s.cmd.Dir
+		cmd.Env = s.cmd.Env
+		s.cmd = cmd
+		r, w := io.Pipe()
+		cmd.Stdout = w
+		cmd.Stderr = w
+		if s.stdin, err = cmd.StdinPipe(); err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		go func() {
+			cmd.Wait()
+			w.Close()
+		}()
+		output = r
+	default:
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.nextID++
+	s.id = fmt.Sprintf("term-%d", m.nextID)
+	m.sessions[s.id] = s
+	m.mu.Unlock()
+
+	go m.readLoop(s, output)
+	return s, nil
+}
+
+// readLoop streams the shell output to the frontend until the shell exits
+func (m *terminalManager) readLoop(s *terminalSession, output io.Reader) {
+	buf := make([]byte, 32*1024)
+	var pending []byte
+	for {
+		n, err := output.Read(buf)
+		if n > 0 {
+			data := append(pending, buf[:n]...)
+			// Keep an incomplete UTF-8 sequence for the next read
+			cut := len(data)
+			for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+				if utf8.RuneStart(data[i]) {
+					if !utf8.FullRune(data[i:]) {
+						cut = i
+					}
+					break
+				}
+			}
+			pending = append([]byte(nil), data[cut:]...)
+			if m.app.ctx != nil && cut > 0 {
+				wruntime.EventsEmit(m.app.ctx, "terminal-output", map[string]interface{}{
+					"id":   s.id,
+					"data": string(data[:cut]),
+				})
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	// The pty read fails once the shell has exited
+	if s.pty != nil {
+		s.cmd.Wait()
+		s.pty.Close()
+	}
+	close(s.done)
+
+	m.mu.Lock()
+	delete(m.sessions, s.id)
+	m.mu.Unlock()
+
+	if m.app.ctx != nil {
+		code := -1
+		if s.cmd.ProcessState != nil {
+			code = s.cmd.ProcessState.ExitCode()
+		}
+		wruntime.EventsEmit(m.app.ctx, "terminal-exit", map[string]interface{}{
+			"id":   s.id,
+			"code": code,
+		})
+	}
+}
+
+func (m *terminalManager) get(id string) (*terminalSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("Terminal %s nicht gefunden", id)
+	}
+	return s, nil
+}
+
+func (s *terminalSession) kill() {
+	if s.cmd.Process != nil {
+		s.cmd.Process.Kill()
+	}
+	if s.pty != nil {
+		s.pty.Close()
+	} else {
+		s.stdin.Close()
+	}
+}
+
+// closeAll kills all shells, called when the app shuts down
+func (m *terminalManager) closeAll() {
+	m.mu.Lock()
+	sessions := make([]*terminalSession, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	m.mu.Unlock()
+
+	for _, s := range sessions {
+		s.kill()
+	}
+}
+
+// StartTerminal starts the user's shell in dir and returns the session id
+func (a *App) StartTerminal(dir string, cols int, rows int) (string, error) {
+	if cols <= 0 || rows <= 0 {
+		cols, rows = 80, 24
+	}
+	s, err := a.terminals.start(dir, cols, rows)
+	if err != nil {
+		return "", fmt.Errorf("Fehler beim Starten der Shell: %w", err)
+	}
+	return s.id, nil
+}
+
+// WriteTerminal sends keyboard input to a terminal session
+func (a *App) WriteTerminal(id string, data string) error {
+	s, err := a.terminals.get(id)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(s.stdin, data)
+	return err
+}
+
+// ResizeTerminal sets the window size of a terminal session
+func (a *App) ResizeTerminal(id string, cols int, rows int) error {
+	s, err := a.terminals.get(id)
+	if err != nil {
+		return err
+	}
+	if s.pty == nil || cols <= 0 || rows <= 0 {
+		return nil
+	}
+	return pty.Setsize(s.pty, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)})
+}
+
+// CloseTerminal ends a terminal session
+func (a *App) CloseTerminal(id string) error {
+	s, err := a.terminals.get(id)
+	if err != nil {
+		return nil
+	}
+	s.kill()
+	<-s.done
+	return nil
+}
@@ -0,0 +1,502 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// workspaceFileName is the workspace file inside a folder; files named
+// "<name>.leoedit-workspace" are workspace files as well.
+const workspaceFileName = ".leoedit-workspace"
+
+const maxRecentWorkspaces = 10
+
+// WorkspaceFolder is one root folder. Path is relative to the workspace file
+// on disk and absolute once loaded.
+type WorkspaceFolder struct {
+	Path string `json:"path"`
+	Name string `json:"name,omitempty"`
+}
+
+// WorkspaceAISettings selects the model and endpoint of the AI panel
+type WorkspaceAISettings struct {
+	Model     string `json:"model,omitempty"`     // "provider/model" as in the model selector
+	BaseURL   string `json:"baseUrl,omitempty"`   // OpenAI compatible chat completions endpoint
+	APIKeyEnv string `json:"apiKeyEnv,omitempty"` // Environment variable holding the API key
+}
+
+// WorkspaceSettings apply to all files below the workspace folders and take
+// precedence over the global configuration
+type WorkspaceSettings struct {
+	Formatters map[string]FormatterConfig `json:"formatters,omitempty"`
+	Tasks      []TaskDefinition           `json:"tasks,omitempty"`
+	// Glob patterns hidden from the explorer and skipped by indexing; without
+	// a slash they match names ("node_modules", "*.min.js"), otherwise paths
+	// relative to the folder ("build/**")
+	Exclude []string             `json:"exclude,omitempty"`
+	AI      *WorkspaceAISettings `json:"ai,omitempty"`
+}
+
+// Workspace is the open project: one or more root folders plus settings.
+// File is empty for a folder opened without a workspace file.
+type Workspace struct {
+	File     string            `json:"file"`
+	Name     string            `json:"name"`
+	Folders  []WorkspaceFolder `json:"folders"`
+	Settings WorkspaceSettings `json:"settings"`
+	// Keys of "settings" that override the user settings ("editor", "format_on_save", ...)
+	Overrides map[string]any `json:"-"`
+}
+
+type workspaceFile struct {
+	Folders  []WorkspaceFolder `json:"folders"`
+	Settings WorkspaceSettings `json:"settings"`
+}
+
+// Keys of the workspace "settings" that belong to WorkspaceSettings itself
+var workspaceOwnSettings = []string{"formatters", "tasks", "exclude", "ai"}
+
+// workspaceState guards the open workspace
+type workspaceState struct {
+	mu      sync.RWMutex
+	current *Workspace
+}
+
+func isWorkspaceFile(path string) bool {
+	return strings.HasSuffix(filepath.Base(path), workspaceFileName)
+}
+
+// loadWorkspace opens a workspace file, or a folder (with its workspace file if it has one)
+func loadWorkspace(path string) (*Workspace, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("Workspace nicht gefunden: %w", err)
+	}
+	if info.IsDir() {
+		file := filepath.Join(path, workspaceFileName)
+		if _, err := os.Stat(file); err != nil {
+			return &Workspace{
+				Name:    filepath.Base(path),
+				Folders: []WorkspaceFolder{{Path: path, Name: filepath.Base(path)}},
+			}, nil
+		}
+		path = file
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Fehler beim Lesen des Workspace: %w", err)
+	}
+	var f workspaceFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("Fehler in %s: %w", filepath.Base(path), err)
+	}
+
+	ws := &Workspace{File: path, Settings: f.Settings}
+	var raw struct {
+		Settings map[string]any `json:"settings"`
+	}
+	json.Unmarshal(data, &raw)
+	for key, v := range raw.Settings {
+		if !slices.Contains(workspaceOwnSettings, key) {
+			if ws.Overrides == nil {
+				ws.Overrides = map[string]any{}
+			}
+			ws.Overrides[key] = v
+		}
+	}
+	base := filepath.Dir(path)
+	ws.Name = strings.TrimSuffix(filepath.Base(path), workspaceFileName)
+	ws.Name = strings.TrimSuffix(ws.Name, ".")
+	if ws.Name == "" {
+		ws.Name = filepath.Base(base)
+	}
+	for _, folder := range f.Folders {
+		if folder.Path == "" {
+			continue
+		}
+		p := filepath.FromSlash(os.ExpandEnv(folder.Path))
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(base, p)
+		}
+		folder.Path = filepath.Clean(p)
+		if folder.Name == "" {
+			folder.Name = filepath.Base(folder.Path)
+		}
+		ws.Folders = append(ws.Folders, folder)
+	}
+	// A workspace file without folders stands for its own directory
+	if len(ws.Folders) == 0 {
+		ws.Folders = []WorkspaceFolder{{Path: base, Name: filepath.Base(base)}}
+	}
+	return ws, nil
+}
+
+// save writes the workspace file; folder paths below its directory are stored relative
+func (ws *Workspace) save() error {
+	base := filepath.Dir(ws.File)
+	f := workspaceFile{Settings: ws.Settings}
+	for _, folder := range ws.Folders {
+		if rel, err := filepath.Rel(base, folder.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			folder.Path = filepath.ToSlash(rel)
+		}
+		if folder.Name == filepath.Base(folder.Path) {
+			folder.Name = ""
+		}
+		f.Folders = append(f.Folders, folder)
+	}
+	var data []byte
+	var err error
+	if len(ws.Overrides) > 0 {
+		// Merge the setting overrides back into "settings"
+		var raw map[string]any
+		data, _ = json.Marshal(f)
+		json.Unmarshal(data, &raw)
+		settings, _ := raw["settings"].(map[string]any)
+		for key, v := range ws.Overrides {
+			settings[key] = v
+		}
+		data, err = json.MarshalIndent(raw, "", "  ")
+	} else {
+		data, err = json.MarshalIndent(f, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ws.File, append(data, '\n'), 0644)
+}
+
+// roots returns the absolute root folders
+func (ws *Workspace) roots() []string {
+	roots := make([]string, len(ws.Folders))
+	for i, f := range ws.Folders {
+		roots[i] = f.Path
+	}
+	return roots
+}
+
+// rootOf returns the workspace folder containing path
+func (ws *Workspace) rootOf(path string) (string, bool) {
+	best := ""
+	for _, root := range ws.roots() {
+		if rel, err := filepath.Rel(root, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			if len(root) > len(best) {
+				best = root
+			}
+		}
+	}
+	return best, best != ""
+}
+
+// excluded reports whether path (below root) matches one of the exclude globs
+func (ws *Workspace) excluded(root string, path string) bool {
+	if len(ws.Settings.Exclude) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	return matchExcludes(ws.Settings.Exclude, filepath.ToSlash(rel))
+}
+
+// matchExcludes reports whether the slash separated relative path matches
+// one of the patterns (see WorkspaceSettings.Exclude)
+func matchExcludes(patterns []string, rel string) bool {
+	name := path.Base(rel)
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				return true
+			}
+			continue
+		}
+		if globMatch(strings.TrimPrefix(pattern, "/"), rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch matches a slash separated path against a pattern in which "**"
+// stands for any number of path segments
+func globMatch(pattern string, path string) bool {
+	return globMatchSegments(strings.Split(pattern, "/"), strings.Split(path, "/"))
+}
+
+func globMatchSegments(pattern []string, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(path); i++ {
+				if globMatchSegments(pattern[1:], path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(path) == 0 {
+			return false
+		}
+		if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	// "build" also excludes everything below build
+	return true
+}
+
+// workspace returns the open workspace or nil
+func (a *App) workspace() *Workspace {
+	a.ws.mu.RLock()
+	defer a.ws.mu.RUnlock()
+	return a.ws.current
+}
+
+// workspaceFor returns the open workspace and the root containing path
+func (a *App) workspaceFor(path string) (*Workspace, string, bool) {
+	ws := a.workspace()
+	if ws == nil {
+		return nil, "", false
+	}
+	root, ok := ws.rootOf(path)
+	return ws, root, ok
+}
+
+// isExcluded reports whether path is hidden by the exclude globs of the workspace
+func (a *App) isExcluded(path string) bool {
+	ws, root, ok := a.workspaceFor(path)
+	return ok && ws.excluded(root, path)
+}
+
+func (a *App) setWorkspace(ws *Workspace) {
+	a.ws.mu.Lock()
+	a.ws.current = ws
+	a.ws.mu.Unlock()
+
+	// The explorer loads the first root on "workspace-changed", which also rebuilds the symbol index
+	a.Config.LastWorkspace = ""
+	if ws != nil {
+		key := ws.File
+		if key == "" {
+			key = ws.Folders[0].Path
+		}
+		a.Config.LastDirectory = ws.Folders[0].Path
+		a.Config.LastWorkspace = key
+		a.addRecentWorkspace(key)
+	}
+	a.saveConfig()
+
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "workspace-changed", a.GetWorkspace())
+	}
+	a.reloadSettings()
+}
+
+func (a *App) addRecentWorkspace(path string) {
+	list := []string{path}
+	for _, p := range a.Config.RecentWorkspaces {
+		if p != path {
+			list = append(list, p)
+		}
+	}
+	if len(list) > maxRecentWorkspaces {
+		list = list[:maxRecentWorkspaces]
+	}
+	a.Config.RecentWorkspaces = list
+}
+
+// OpenWorkspace opens a .leoedit-workspace file or a folder as workspace
+func (a *App) OpenWorkspace(path string) (Workspace, error) {
+	ws, err := loadWorkspace(path)
+	if err != nil {
+		return Workspace{}, err
+	}
+	a.setWorkspace(ws)
+	return *ws, nil
+}
+
+// OpenWorkspaceDialog lets the user pick a workspace file
+func (a *App) OpenWorkspaceDialog() (Workspace, error) {
+	file, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:            "Workspace öffnen",
+		DefaultDirectory: a.Config.LastDirectory,
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Leoedit Workspace", Pattern: "*" + workspaceFileName},
+		},
+		ShowHiddenFiles: true,
+	})
+	if err != nil {
+		return Workspace{}, fmt.Errorf("Fehler beim Öffnen des Dialogs: %w", err)
+	}
+	if file == "" {
+		return Workspace{}, fmt.Errorf("Abgebrochen")
+	}
+	return a.OpenWorkspace(file)
+}
+
+func (a *App) chooseDirectory(title string) (string, error) {
+	dir, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:                title,
+		DefaultDirectory:     a.Config.LastDirectory,
+		CanCreateDirectories: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("Fehler beim Öffnen des Dialogs: %w", err)
+	}
+	if dir == "" {
+		return "", fmt.Errorf("Abgebrochen")
+	}
+	return dir, nil
+}
+
+// OpenFolderDialog lets the user pick a folder and opens it as workspace
+func (a *App) OpenFolderDialog() (Workspace, error) {
+	dir, err := a.chooseDirectory("Ordner öffnen")
+	if err != nil {
+		return Workspace{}, err
+	}
+	return a.OpenWorkspace(dir)
+}
+
+// AddWorkspaceFolderDialog lets the user pick a folder to add to the workspace
+func (a *App) AddWorkspaceFolderDialog() (Workspace, error) {
+	dir, err := a.chooseDirectory("Ordner zum Workspace hinzufügen")
+	if err != nil {
+		return Workspace{}, err
+	}
+	return a.AddWorkspaceFolder(dir)
+}
+
+// GetWorkspace returns the open workspace; it has no folders if none is open
+func (a *App) GetWorkspace() Workspace {
+	ws := a.workspace()
+	if ws == nil {
+		return Workspace{Folders: []WorkspaceFolder{}}
+	}
+	return *ws
+}
+
+// CloseWorkspace closes the open workspace
+func (a *App) CloseWorkspace() {
+	a.setWorkspace(nil)
+}
+
+// AddWorkspaceFolder adds a root folder; a workspace file is written if one exists
+func (a *App) AddWorkspaceFolder(dir string) (Workspace, error) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return Workspace{}, fmt.Errorf("Kein Verzeichnis: %s", dir)
+	}
+	dir, _ = filepath.Abs(dir)
+
+	ws := a.workspace()
+	if ws == nil {
+		return a.OpenWorkspace(dir)
+	}
+	updated := *ws
+	for _, f := range ws.Folders {
+		if f.Path == dir {
+			return updated, nil
+		}
+	}
+	updated.Folders = append(append([]WorkspaceFolder{}, ws.Folders...), WorkspaceFolder{Path: dir, Name: filepath.Base(dir)})
+	if updated.File != "" {
+		if err := updated.save(); err != nil {
+			return Workspace{}, fmt.Errorf("Fehler beim Speichern des Workspace: %w", err)
+		}
+	}
+	a.setWorkspace(&updated)
+	return updated, nil
+}
+
+// RemoveWorkspaceFolder removes a root folder from the workspace
+func (a *App) RemoveWorkspaceFolder(dir string) (Workspace, error) {
+	ws := a.workspace()
+	if ws == nil {
+		return Workspace{}, fmt.Errorf("Kein Workspace geöffnet")
+	}
+	updated := *ws
+	updated.Folders = nil
+	for _, f := range ws.Folders {
+		if f.Path != dir {
+			updated.Folders = append(updated.Folders, f)
+		}
+	}
+	if len(updated.Folders) == 0 {
+		return Workspace{}, fmt.Errorf("Der letzte Ordner kann nicht entfernt werden")
+	}
+	if updated.File != "" {
+		if err := updated.save(); err != nil {
+			return Workspace{}, fmt.Errorf("Fehler beim Speichern des Workspace: %w", err)
+		}
+	}
+	a.setWorkspace(&updated)
+	return updated, nil
+}
+
+// SaveWorkspaceAs writes the open workspace to a workspace file chosen by the user
+func (a *App) SaveWorkspaceAs() (Workspace, error) {
+	ws := a.workspace()
+	if ws == nil {
+		return Workspace{}, fmt.Errorf("Kein Workspace geöffnet")
+	}
+	file, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:            "Workspace speichern",
+		DefaultDirectory: ws.Folders[0].Path,
+		DefaultFilename:  ws.Name + workspaceFileName,
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Leoedit Workspace", Pattern: "*" + workspaceFileName},
+		},
+	})
+	if err != nil {
+		return Workspace{}, fmt.Errorf("Fehler beim Öffnen des Dialogs: %w", err)
+	}
+	if file == "" {
+		return Workspace{}, fmt.Errorf("Abgebrochen")
+	}
+	if !isWorkspaceFile(file) {
+		file += workspaceFileName
+	}
+
+	updated := *ws
+	updated.File = file
+	if err := updated.save(); err != nil {
+		return Workspace{}, fmt.Errorf("Fehler beim Speichern des Workspace: %w", err)
+	}
+	return a.OpenWorkspace(file)
+}
+
+// GetRecentWorkspaces returns the recently opened workspace files and folders
+func (a *App) GetRecentWorkspaces() []string {
+	if a.Config.RecentWorkspaces == nil {
+		return []string{}
+	}
+	return a.Config.RecentWorkspaces
+}
+
+// RemoveRecentWorkspace removes an entry from the recent workspaces
+func (a *App) RemoveRecentWorkspace(path string) []string {
+	list := []string{}
+	for _, p := range a.Config.RecentWorkspaces {
+		if p != path {
+			list = append(list, p)
+		}
+	}
+	a.Config.RecentWorkspaces = list
+	a.saveConfig()
+	return list
+}